@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/1mb-dev/lobster/v2/internal/cli"
 	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/linkgraph"
 	"github.com/1mb-dev/lobster/v2/internal/reporter"
 	"github.com/1mb-dev/lobster/v2/internal/tester"
 	"github.com/1mb-dev/lobster/v2/internal/util"
@@ -24,6 +27,15 @@ import (
 var version = "dev"
 
 func main() {
+	// Subcommands take their own flags
+	if len(os.Args) > 1 && os.Args[1] == "robots" {
+		if err := cli.RunRobots(os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var (
 		configPath         = flag.String("config", "", "Path to configuration file (JSON)")
 		baseURL            = flag.String("url", "", "Base URL to test")
@@ -35,12 +47,44 @@ func main() {
 		followLinks        = flag.Bool("follow-links", true, "Follow links found in pages")
 		maxDepth           = flag.Int("max-depth", 0, "Maximum crawl depth")
 		queueSize          = flag.Int("queue-size", 0, "URL queue buffer size (default: 10000)")
+		maxResponseSize    = flag.Int64("max-response-size", 0, "Maximum response body bytes to read per request (default: 10485760)")
+		maxURLValidations  = flag.Int("max-url-validations", 0, "Per-URL results kept in the report; the first URLs tested are kept (default: 10000)")
+		noURLValidations   = flag.Bool("no-url-validations", false, "Keep no per-URL results; route statistics still cover every request")
 		respect429         = flag.Bool("respect-429", true, "Respect HTTP 429 with exponential backoff")
 		dryRun             = flag.Bool("dry-run", false, "Discover URLs without making test requests")
 		insecureSkipVerify = flag.Bool("insecure-skip-verify", false, "INSECURE: Skip TLS certificate verification")
 		allowPrivateIPs    = flag.Bool("allow-private-ips", false, "Allow private/localhost IPs (for internal testing)")
 		ignoreRobots       = flag.Bool("ignore-robots", false, "Ignore robots.txt directives (use responsibly)")
+		ignoreNofollow     = flag.Bool("ignore-nofollow", false, "Follow links excluded by meta robots, X-Robots-Tag or rel=nofollow")
+		trailingSlash      = flag.String("trailing-slash", "", "Trailing-slash normalization: keep, strip, add (default: keep)")
+		honorCanonical     = flag.Bool("honor-canonical", false, "Treat pages as duplicates of their rel=canonical URL")
+		spillDir           = flag.String("spill-dir", "", "Spill URL queue overflow to disk under this directory")
+		visitedSet         = flag.String("visited-set", "", "Visited URL storage: memory (exact) or bloom (bounded memory)")
+		checkpointPath     = flag.String("checkpoint", "", "Periodically save crawl state to this file")
+		checkpointInterval = flag.String("checkpoint-interval", "", "How often to save the checkpoint (default: 30s)")
+		resume             = flag.Bool("resume", false, "Continue the crawl saved in the -checkpoint file")
+		metricsInterval    = flag.String("metrics-interval", "", "Width of each time-series bucket in the report (default: 1s)")
+		histogramPrecision = flag.Int("histogram-precision", 0, "Significant digits kept by latency histograms, 1-5 (default: 3)")
+		samples            = flag.Int("samples", 0, "Raw response times kept in the report, by reservoir sampling (default: 10000)")
+		noSamples          = flag.Bool("no-samples", false, "Keep no raw response times; report statistics from histograms only")
+		maxRedirects       = flag.Int("max-redirects", 10, "Maximum redirect hops to follow per request; 0 follows none")
+		scopedRedirects    = flag.Bool("no-cross-scope-redirects", false, "Do not follow redirects to other hosts")
+		separateRedirects  = flag.Bool("separate-redirects", false, "Record redirects as separate results instead of following them")
+		noTrapDetection    = flag.Bool("no-trap-detection", false, "Disable crawler trap detection")
+		extractors         = flag.String("extractors", "", "Comma-separated link extractors: html, json, feed, link_header, js (default: all but js)")
+		jsEndpoints        = flag.Bool("js-endpoints", false, "Scan same-origin JavaScript files for API endpoints (adds the js extractor)")
+		routeTemplates     = flag.String("routes", "", "Comma-separated route templates for per-route statistics (e.g., /users/:id,/static/*)")
+		routeSort          = flag.String("route-sort", "", "Order of the per-route table: requests, errors, p50, p95, p99, name (default: requests)")
+		noRouteDetect      = flag.Bool("no-route-detect", false, "Do not group numeric and UUID path segments into routes")
+		successStatus      = flag.String("success-status", "", "Comma-separated status codes, ranges or classes counted as success (e.g., 200-299,304; default: 2xx,3xx)")
+		noDenylist         = flag.Bool("no-denylist", false, "Follow logout, delete and other destructive links (use with care)")
+		noForms            = flag.Bool("no-forms", false, "Do not generate requests from HTML forms")
+		submitPostForms    = flag.Bool("submit-post-forms", false, "Also submit POST forms, which can change data (use with care)")
+		linkCheck          = flag.Bool("link-check", false, "Check each discovered URL once and report broken links")
+		checkExternal      = flag.Bool("check-external", false, "In link-check mode, also check off-site links (not crawled)")
 		outputFile         = flag.String("output", "", "Output file for results (JSON)")
+		graphOutput        = flag.String("graph", "", "Export the crawl link graph (.dot, .graphml or .json)")
+		rawOutput          = flag.String("raw-output", "", "Stream one JSON line per request to this file while the test runs")
 		verbose            = flag.Bool("verbose", false, "Verbose logging")
 		noProgress         = flag.Bool("no-progress", false, "Disable progress updates")
 		showVersion        = flag.Bool("version", false, "Show version information")
@@ -56,6 +100,14 @@ func main() {
 	)
 	flag.Parse()
 
+	// -max-redirects 0 follows no redirects, so only an explicit flag overrides the config
+	var maxRedirectsOverride *int
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "max-redirects" {
+			maxRedirectsOverride = maxRedirects
+		}
+	})
+
 	// Setup logger early so all errors use consistent output format
 	logLevel := slog.LevelInfo
 	if *verbose {
@@ -86,17 +138,48 @@ func main() {
 		FollowLinks:        *followLinks,
 		MaxDepth:           *maxDepth,
 		QueueSize:          *queueSize,
+		MaxResponseSize:    *maxResponseSize,
+		MaxURLValidations:  *maxURLValidations,
+		NoURLValidations:   *noURLValidations,
 		Respect429:         *respect429,
 		DryRun:             *dryRun,
 		InsecureSkipVerify: *insecureSkipVerify,
 		IgnoreRobots:       *ignoreRobots,
+		IgnoreNofollow:     *ignoreNofollow,
 		OutputFile:         *outputFile,
+		GraphOutput:        *graphOutput,
+		RawOutput:          *rawOutput,
 		Verbose:            *verbose,
 		AuthType:           *authType,
 		AuthUsername:       *authUsername,
 		AuthPasswordStdin:  *authPasswordStdin,
 		AuthTokenStdin:     *authTokenStdin,
 		AuthHeader:         *authHeader,
+		TrailingSlash:      *trailingSlash,
+		HonorCanonical:     *honorCanonical,
+		SpillDir:           *spillDir,
+		VisitedSet:         *visitedSet,
+		Checkpoint:         *checkpointPath,
+		CheckpointInterval: *checkpointInterval,
+		MetricsInterval:    *metricsInterval,
+		HistogramPrecision: *histogramPrecision,
+		Samples:            *samples,
+		NoSamples:          *noSamples,
+		MaxRedirects:       maxRedirectsOverride,
+		ScopedRedirects:    *scopedRedirects,
+		SeparateRedirects:  *separateRedirects,
+		NoTrapDetection:    *noTrapDetection,
+		NoDenylist:         *noDenylist,
+		NoForms:            *noForms,
+		SubmitPostForms:    *submitPostForms,
+		LinkExtractors:     *extractors,
+		RouteTemplates:     *routeTemplates,
+		RouteSort:          *routeSort,
+		NoRouteDetect:      *noRouteDetect,
+		SuccessStatus:      *successStatus,
+		JSEndpoints:        *jsEndpoints,
+		LinkCheck:          *linkCheck,
+		CheckExternal:      *checkExternal,
 	})
 	if err != nil {
 		logger.Error("Configuration error",
@@ -105,6 +188,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Validate every setting once file values, flags and defaults are merged
+	if validateErr := cfg.Validate(); validateErr != nil {
+		logger.Error("Invalid configuration",
+			"error", validateErr,
+			"hint", "Check your config file values and command-line flags (see -help)")
+		os.Exit(1)
+	}
+
 	// Validate and enforce rate limit safety
 	if validateErr := cli.ValidateRateLimit(&cfg.Rate); validateErr != nil {
 		logger.Error("Invalid rate limit",
@@ -193,6 +284,37 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Parse checkpoint interval
+	checkpointEvery, err := time.ParseDuration(cfg.Checkpoint.Interval)
+	if err != nil {
+		logger.Error("Invalid checkpoint interval format",
+			"error", err,
+			"hint", "Use format like: 30s, 5m (e.g., -checkpoint-interval 1m)")
+		os.Exit(1)
+	}
+
+	// Parse time-series bucket width
+	metricsEvery, err := time.ParseDuration(cfg.MetricsInterval)
+	if err != nil || metricsEvery <= 0 {
+		logger.Error("Invalid metrics interval",
+			"metrics_interval", cfg.MetricsInterval,
+			"hint", "Use a positive duration like: 1s, 10s (e.g., -metrics-interval 5s)")
+		os.Exit(1)
+	}
+
+	if *resume && cfg.Checkpoint.Path == "" {
+		logger.Error("Nothing to resume from",
+			"hint", "Use -checkpoint with the file written by the interrupted run")
+		os.Exit(1)
+	}
+
+	if cfg.GraphOutput != "" {
+		if _, graphErr := linkgraph.FormatFromPath(cfg.GraphOutput); graphErr != nil {
+			logger.Error("Invalid graph output", "error", graphErr)
+			os.Exit(1)
+		}
+	}
+
 	// Initialize stress tester config
 	testerConfig := domain.TesterConfig{
 		BaseURL:            cfg.BaseURL,
@@ -200,13 +322,35 @@ func main() {
 		RequestTimeout:     requestTimeout,
 		UserAgent:          cfg.UserAgent,
 		Auth:               cfg.Auth,
+		Normalization:      *cfg.Normalization,
+		Frontier:           cfg.Frontier,
+		Latency:            cfg.Latency,
+		Redirects:          *cfg.Redirects,
+		Traps:              *cfg.Traps,
+		Denylist:           *cfg.Denylist,
+		Routes:             *cfg.Routes,
+		Success:            *cfg.Success,
+		Forms:              *cfg.Forms,
+		LinkExtractors:     cfg.LinkExtractors,
+		CheckpointPath:     cfg.Checkpoint.Path,
+		GraphPath:          cfg.GraphOutput,
+		RawOutputPath:      cfg.RawOutput,
+		CheckpointInterval: checkpointEvery,
+		MetricsInterval:    metricsEvery,
+		Resume:             *resume,
 		FollowLinks:        cfg.FollowLinks,
 		MaxDepth:           cfg.MaxDepth,
 		QueueSize:          cfg.QueueSize,
+		MaxResponseSize:    cfg.MaxResponseSize,
+		MaxURLValidations:  cfg.MaxURLValidations,
+		NoURLValidations:   cfg.NoURLValidations,
 		Respect429:         cfg.Respect429,
 		DryRun:             cfg.DryRun,
+		LinkCheck:          cfg.LinkCheck,
+		CheckExternal:      cfg.CheckExternal,
 		InsecureSkipVerify: cfg.InsecureSkipVerify,
 		IgnoreRobots:       cfg.IgnoreRobots,
+		IgnoreNofollow:     cfg.IgnoreNofollow,
 		Rate:               cfg.Rate,
 		Verbose:            cfg.Verbose,
 		NoProgress:         *noProgress,
@@ -271,6 +415,10 @@ func runStressTest(config domain.TesterConfig, duration time.Duration, logger *s
 	ctx, cancel := context.WithTimeout(context.Background(), duration)
 	defer cancel()
 
+	// Stop gracefully on interrupt so results are reported and the checkpoint is saved
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	stressTester, err := tester.New(config, logger)
 	if err != nil {
 		return nil, fmt.Errorf("tester initialization failed: %w", err)
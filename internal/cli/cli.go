@@ -11,19 +11,50 @@ type ConfigOptions struct {
 	Timeout            string
 	UserAgent          string
 	OutputFile         string
+	GraphOutput        string
+	RawOutput          string
 	Rate               float64
 	Concurrency        int
 	MaxDepth           int
 	QueueSize          int
+	MaxResponseSize    int64
+	MaxURLValidations  int
+	NoURLValidations   bool
 	FollowLinks        bool
 	Respect429         bool
 	DryRun             bool
 	Verbose            bool
 	InsecureSkipVerify bool
 	IgnoreRobots       bool
+	IgnoreNofollow     bool
 	AuthType           string
 	AuthUsername       string
 	AuthHeader         string
 	AuthPasswordStdin  bool
 	AuthTokenStdin     bool
+	TrailingSlash      string
+	HonorCanonical     bool
+	SpillDir           string
+	VisitedSet         string
+	Checkpoint         string
+	CheckpointInterval string
+	MetricsInterval    string
+	HistogramPrecision int
+	Samples            int
+	NoSamples          bool
+	MaxRedirects       *int // nil keeps the configured limit; 0 follows no redirects
+	ScopedRedirects    bool
+	SeparateRedirects  bool
+	NoTrapDetection    bool
+	NoDenylist         bool
+	NoForms            bool
+	SubmitPostForms    bool
+	LinkExtractors     string
+	JSEndpoints        bool
+	RouteTemplates     string
+	RouteSort          string
+	NoRouteDetect      bool
+	SuccessStatus      string
+	LinkCheck          bool
+	CheckExternal      bool
 }
@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -50,6 +53,31 @@ func TestLoadConfiguration_CLIOverrides(t *testing.T) {
 	}
 }
 
+func TestLoadConfiguration_JSEndpoints(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ConfigOptions
+		want string
+	}{
+		{"off by default", ConfigOptions{}, "html,json,feed,link_header"},
+		{"added to the defaults", ConfigOptions{JSEndpoints: true}, "html,json,feed,link_header,js"},
+		{"added to explicit extractors", ConfigOptions{LinkExtractors: "html", JSEndpoints: true}, "html,js"},
+		{"not duplicated", ConfigOptions{LinkExtractors: "js,html", JSEndpoints: true}, "js,html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfiguration("", &tt.opts)
+			if err != nil {
+				t.Fatalf("LoadConfiguration() error = %v", err)
+			}
+			if got := strings.Join(cfg.LinkExtractors, ","); got != tt.want {
+				t.Errorf("Expected extractors %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestLoadConfiguration_FromFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "test-config.json")
@@ -338,3 +366,116 @@ func TestIsInteractiveTerminal(_ *testing.T) {
 	// but we can verify it doesn't panic
 	_ = IsInteractiveTerminal()
 }
+
+func TestRunRobots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 2\nDisallow: /admin\n"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "disallowed path",
+			args: []string{"-allow-private-ips", server.URL + "/admin/users"},
+			want: []string{"Result:      DISALLOWED", "Group:       User-agent: * (line 1)", "Rule:        Disallow: /admin (line 3)", "Crawl-delay: 2s"},
+		},
+		{
+			name: "allowed path",
+			args: []string{"-allow-private-ips", "-user-agent", "Other/2.0", server.URL + "/blog"},
+			want: []string{"User agent:  Other/2.0", "Result:      ALLOWED", "no rule of the group matches /blog"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			if err := RunRobots(tt.args, &stdout, &stderr); err != nil {
+				t.Fatalf("RunRobots() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunRobots_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no URL", args: nil},
+		{name: "two URLs", args: []string{"http://example.com/a", "http://example.com/b"}},
+		{name: "private host", args: []string{"http://127.0.0.1/"}},
+		{name: "unsupported scheme", args: []string{"ftp://example.com/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			if err := RunRobots(tt.args, &stdout, &stderr); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_RouteFlags(t *testing.T) {
+	opts := ConfigOptions{
+		BaseURL:        "http://example.com",
+		RouteTemplates: "/users/:id, /static/*",
+		RouteSort:      "p95",
+		NoRouteDetect:  true,
+	}
+	cfg, err := LoadConfiguration("", &opts)
+	if err != nil {
+		t.Fatalf("LoadConfiguration() error = %v", err)
+	}
+	if got := strings.Join(cfg.Routes.Templates, ","); got != "/users/:id,/static/*" {
+		t.Errorf("Expected templates /users/:id,/static/*, got %s", got)
+	}
+	if cfg.Routes.Sort != "p95" || !cfg.Routes.NoAutoDetect {
+		t.Errorf("Expected sort p95 without auto-detection, got %+v", cfg.Routes)
+	}
+}
+
+func TestLoadConfiguration_SuccessStatus(t *testing.T) {
+	opts := ConfigOptions{BaseURL: "http://example.com", SuccessStatus: "200-299, 404"}
+	cfg, err := LoadConfiguration("", &opts)
+	if err != nil {
+		t.Fatalf("LoadConfiguration() error = %v", err)
+	}
+	if got := strings.Join(cfg.Success.Status, ","); got != "200-299,404" {
+		t.Errorf("Expected success status 200-299,404, got %s", got)
+	}
+}
+
+func TestLoadConfiguration_MaxRedirects(t *testing.T) {
+	none := 0
+	tests := []struct {
+		name string
+		opts ConfigOptions
+		want int
+	}{
+		{"default when unset", ConfigOptions{}, 10},
+		{"explicit zero follows none", ConfigOptions{MaxRedirects: &none}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfiguration("", &tt.opts)
+			if err != nil {
+				t.Fatalf("LoadConfiguration() error = %v", err)
+			}
+			if cfg.Redirects.MaxHops != tt.want {
+				t.Errorf("Expected MaxHops %d, got %d", tt.want, cfg.Redirects.MaxHops)
+			}
+		})
+	}
+}
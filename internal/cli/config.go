@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/1mb-dev/lobster/v2/internal/config"
 	"github.com/1mb-dev/lobster/v2/internal/domain"
@@ -52,15 +54,63 @@ func LoadConfiguration(configPath string, opts *ConfigOptions) (*domain.Config,
 	if opts.QueueSize != 0 {
 		cfg.QueueSize = opts.QueueSize
 	}
+	if opts.MaxResponseSize != 0 {
+		cfg.MaxResponseSize = opts.MaxResponseSize
+	}
+	if opts.MaxURLValidations != 0 {
+		cfg.MaxURLValidations = opts.MaxURLValidations
+	}
+	if opts.NoURLValidations {
+		cfg.NoURLValidations = true
+	}
 	if opts.OutputFile != "" {
 		cfg.OutputFile = opts.OutputFile
 	}
+	if opts.GraphOutput != "" {
+		cfg.GraphOutput = opts.GraphOutput
+	}
+	if opts.RawOutput != "" {
+		cfg.RawOutput = opts.RawOutput
+	}
+	if opts.SpillDir != "" {
+		cfg.Frontier.SpillDir = opts.SpillDir
+	}
+	if opts.VisitedSet != "" {
+		cfg.Frontier.VisitedSet = opts.VisitedSet
+	}
+	if opts.Checkpoint != "" {
+		cfg.Checkpoint.Path = opts.Checkpoint
+	}
+	if opts.CheckpointInterval != "" {
+		cfg.Checkpoint.Interval = opts.CheckpointInterval
+	}
+	if opts.MetricsInterval != "" {
+		cfg.MetricsInterval = opts.MetricsInterval
+	}
+	if opts.HistogramPrecision != 0 {
+		cfg.Latency.Precision = opts.HistogramPrecision
+	}
+	if opts.Samples != 0 {
+		cfg.Latency.Samples = opts.Samples
+	}
+	if opts.NoSamples {
+		cfg.Latency.NoSamples = true
+	}
 	cfg.FollowLinks = opts.FollowLinks
 	cfg.Respect429 = opts.Respect429
 	cfg.DryRun = opts.DryRun
+	if opts.LinkCheck {
+		cfg.LinkCheck = true
+	}
+	if opts.CheckExternal {
+		cfg.CheckExternal = true
+	}
 	cfg.Verbose = opts.Verbose
 	cfg.InsecureSkipVerify = opts.InsecureSkipVerify
 	cfg.IgnoreRobots = opts.IgnoreRobots
+	if opts.IgnoreNofollow {
+		cfg.IgnoreNofollow = true
+	}
 
 	// Build authentication configuration from CLI flags and environment variables
 	authCfg, err := BuildAuthConfig(opts)
@@ -74,5 +124,69 @@ func LoadConfiguration(configPath string, opts *ConfigOptions) (*domain.Config,
 	// Merge with defaults for any missing values
 	cfg = loader.MergeWithDefaults(cfg)
 
+	// Normalization flags refine the merged block rather than replacing it
+	if opts.TrailingSlash != "" {
+		cfg.Normalization.TrailingSlash = opts.TrailingSlash
+	}
+	if opts.HonorCanonical {
+		cfg.Normalization.HonorCanonical = true
+	}
+
+	// Redirect flags likewise refine the merged policy
+	if opts.MaxRedirects != nil {
+		cfg.Redirects.MaxHops = *opts.MaxRedirects
+	}
+	if opts.ScopedRedirects {
+		cfg.Redirects.FollowCrossScope = false
+	}
+	if opts.SeparateRedirects {
+		cfg.Redirects.SeparateResults = true
+	}
+
+	if opts.NoTrapDetection {
+		cfg.Traps.Disabled = true
+	}
+	if opts.NoDenylist {
+		cfg.Denylist.Disabled = true
+	}
+	if opts.NoForms {
+		cfg.Forms.Disabled = true
+	}
+	if opts.SubmitPostForms {
+		cfg.Forms.SubmitPost = true
+	}
+	if opts.RouteTemplates != "" {
+		for _, template := range strings.Split(opts.RouteTemplates, ",") {
+			if template = strings.TrimSpace(template); template != "" {
+				cfg.Routes.Templates = append(cfg.Routes.Templates, template)
+			}
+		}
+	}
+	if opts.RouteSort != "" {
+		cfg.Routes.Sort = opts.RouteSort
+	}
+	if opts.NoRouteDetect {
+		cfg.Routes.NoAutoDetect = true
+	}
+	if opts.SuccessStatus != "" {
+		cfg.Success.Status = nil
+		for _, status := range strings.Split(opts.SuccessStatus, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				cfg.Success.Status = append(cfg.Success.Status, status)
+			}
+		}
+	}
+	if opts.LinkExtractors != "" {
+		cfg.LinkExtractors = nil
+		for _, extractor := range strings.Split(opts.LinkExtractors, ",") {
+			if extractor = strings.TrimSpace(extractor); extractor != "" {
+				cfg.LinkExtractors = append(cfg.LinkExtractors, extractor)
+			}
+		}
+	}
+	if opts.JSEndpoints && !slices.Contains(cfg.LinkExtractors, domain.ExtractorJS) {
+		cfg.LinkExtractors = append(slices.Clone(cfg.LinkExtractors), domain.ExtractorJS)
+	}
+
 	return cfg, nil
 }
@@ -44,6 +44,12 @@ func ShowHelpMessage(version string) {
 
 USAGE:
     lobster [OPTIONS]
+    lobster robots [-user-agent UA] [-allow-private-ips] URL
+
+COMMANDS:
+    robots URL
+        Fetch the robots.txt of URL's host and explain whether URL may
+        be crawled, naming the group and rule that decided
 
 OPTIONS:
     -config string
@@ -64,17 +70,97 @@ OPTIONS:
         User agent string (default: Lobster/1.0)
     -follow-links
         Follow links found in pages (default: true)
+    -extractors string
+        Comma-separated link extractors: html, json (HAL / JSON:API
+        bodies), feed (RSS / Atom items), link_header (RFC 8288 Link
+        headers), js (endpoints in script files)
+        (default: all but js)
+    -js-endpoints
+        Follow same-origin <script src> files and queue API endpoint
+        candidates found in them (fetch/axios calls, path literals)
     -max-depth int
         Maximum crawl depth (default: 3)
     -queue-size int
         URL queue buffer size (default: 10000)
         Memory usage: ~8 bytes per queue slot
+    -max-response-size int
+        Maximum response body bytes read per request; larger bodies
+        are cut off and their connection closed (default: 10485760)
+    -max-url-validations int
+        Per-URL results kept in the report; the first URLs tested are
+        kept, while counts and statistics cover every request
+        (default: 10000)
+    -no-url-validations
+        Keep no per-URL results
+    -trailing-slash string
+        Trailing-slash normalization for deduplication: keep, strip, add
+        (default: keep)
+    -honor-canonical
+        Treat pages as duplicates of their <link rel="canonical"> URL
+    -spill-dir string
+        Spill URL queue overflow to disk instead of dropping it
+        Breadth-first order is preserved across the overflow
+    -visited-set string
+        Visited URL storage: memory (exact) or bloom (bounded memory)
+        (default: memory)
+    -max-redirects int
+        Maximum redirect hops to follow per request; 0 follows none
+        (default: 10)
+    -no-cross-scope-redirects
+        Do not follow redirects to hosts other than the base URL's
+    -separate-redirects
+        Record each redirect as its own result and queue its target
+        instead of following it inline
+    -no-trap-detection
+        Do not suppress crawler traps (endless calendars, repeating path
+        segments, session IDs, query-string variant explosions)
+    -no-denylist
+        Follow links that look destructive (logout, delete, unsubscribe)
+        and stop denylisting links that end the authenticated session
+    -no-forms
+        Do not generate requests from HTML forms (GET forms are
+        submitted with sampled field values by default)
+    -submit-post-forms
+        Also submit POST forms; they can create or change data
     -respect-429
         Respect HTTP 429 with exponential backoff (default: true)
         Backoff: 1s, 2s, 4s, 8s, 16s (max 30s)
     -dry-run
         Discover URLs without making test requests
         Shows estimated test scope and discovered URLs
+    -link-check
+        Check each discovered URL once (HEAD, falling back to GET) and
+        report broken links with the pages that link to them
+    -check-external
+        With -link-check, also check off-site links without crawling them
+    -checkpoint string
+        Periodically save crawl state (frontier, visited URLs, results,
+        robots.txt rules) to this file
+    -checkpoint-interval string
+        How often to save the checkpoint (default: 30s)
+    -resume
+        Continue the crawl saved in the -checkpoint file
+    -histogram-precision int
+        Significant digits kept by latency histograms, 1-5 (default: 3)
+    -samples int
+        Raw response times kept in the report, picked by reservoir
+        sampling; percentiles always cover every request (default: 10000)
+    -no-samples
+        Keep no raw response times
+    -routes string
+        Comma-separated route templates for per-route statistics, tried
+        before automatic detection (e.g., /users/:id,/static/*)
+    -route-sort string
+        Order of the per-route table: requests, errors, p50, p95, p99,
+        name (default: requests)
+    -no-route-detect
+        Do not group numeric and UUID path segments into :id and :uuid
+    -success-status string
+        Comma-separated status codes, ranges or classes counted as
+        success (e.g., 200-299,304 or 2xx); other responses fail
+        (default: 2xx,3xx)
+    -metrics-interval string
+        Width of each time-series bucket in the report (default: 1s)
     -insecure-skip-verify
         INSECURE: Skip TLS certificate verification
         Use ONLY for testing with self-signed certificates
@@ -87,8 +173,17 @@ OPTIONS:
         Ignore robots.txt directives (use responsibly)
         Only use if you OWN the website or have explicit permission
         Bypassing robots.txt may violate terms of service
+    -ignore-nofollow
+        Follow links on pages with a nofollow meta robots tag or
+        X-Robots-Tag header, and links marked rel="nofollow"
     -output string
         Output file for results (JSON format)
+    -graph string
+        Export the crawl link graph; the extension selects the format:
+        .dot/.gv (Graphviz), .graphml or .json (adjacency list + stats)
+    -raw-output string
+        Stream one JSON line per request (timestamp, URL, method, status,
+        timings, bytes, error class, worker) to this file during the run
     -verbose
         Enable verbose logging with structured output
     -no-progress
@@ -131,6 +226,17 @@ EXAMPLES:
     # Use configuration file
     lobster -config myconfig.json
 
+    # Resumable discovery crawl (rerun with -resume after an interruption)
+    lobster -url https://example.com -dry-run -duration 1h \
+        -checkpoint crawl.ckpt
+
+    # Find broken links, including links to other sites
+    lobster -url https://example.com -link-check -check-external \
+        -output links.json
+
+    # Explain why robots.txt blocks a URL
+    lobster robots https://example.com/admin/
+
     # Compare against competitor
     lobster -url http://localhost:3000 -compare "Ghost"
 
@@ -169,6 +275,14 @@ CONFIGURATION FILE EXAMPLE:
       "max_depth": 3,
       "queue_size": 10000,
       "output_file": "results.json",
+      "normalization": {
+        "trailing_slash": "keep",
+        "lowercase_host": true,
+        "strip_default_port": true,
+        "sort_query": true,
+        "strip_tracking": true,
+        "honor_canonical": false
+      },
       "verbose": false,
       "auth": {
         "type": "basic",
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/robots"
+	"github.com/1mb-dev/lobster/v2/internal/util"
+)
+
+// RunRobots implements "lobster robots": it fetches the robots.txt of a URL's host
+// and explains whether the URL may be crawled, naming the group and rule that decided.
+// args are the command-line arguments following "robots"; the report goes to stdout.
+func RunRobots(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("lobster robots", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	userAgent := flags.String("user-agent", domain.DefaultConfig().UserAgent, "User agent whose rules apply")
+	allowPrivateIPs := flags.Bool("allow-private-ips", false, "Allow private/localhost hosts")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout for fetching robots.txt")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: lobster robots [-user-agent UA] [-allow-private-ips] [-timeout 10s] URL\n\n")
+		fmt.Fprintf(stderr, "Explains whether robots.txt allows URL to be crawled.\n\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one URL, got %d arguments", flags.NArg())
+	}
+
+	target := flags.Arg(0)
+	if err := util.ValidateBaseURL(target, *allowPrivateIPs); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	parser := robots.New(*userAgent)
+	fetchErr := parser.FetchAndParse(ctx, target)
+	decision := parser.Explain(target)
+
+	verdict := "ALLOWED"
+	if !decision.Allowed {
+		verdict = "DISALLOWED"
+	}
+
+	fmt.Fprintf(stdout, "URL:         %s\n", target)
+	fmt.Fprintf(stdout, "User agent:  %s\n", *userAgent)
+	switch {
+	case fetchErr != nil:
+		fmt.Fprintf(stdout, "robots.txt:  %v\n", fetchErr)
+	case parser.RobotsTxtFound():
+		fmt.Fprintf(stdout, "robots.txt:  found\n")
+	default:
+		fmt.Fprintf(stdout, "robots.txt:  not found\n")
+	}
+	fmt.Fprintf(stdout, "Result:      %s\n", verdict)
+	if decision.Group != nil {
+		fmt.Fprintf(stdout, "Group:       User-agent: %s%s\n", strings.Join(decision.Group, ", "), lineSuffix(decision.GroupLine))
+	}
+	if decision.Rule != "" {
+		fmt.Fprintf(stdout, "Rule:        %s%s\n", decision.Rule, lineSuffix(decision.RuleLine))
+	}
+	fmt.Fprintf(stdout, "Reason:      %s\n", decision.Reason)
+	if delay := parser.GetCrawlDelay(); delay > 0 {
+		fmt.Fprintf(stdout, "Crawl-delay: %s\n", delay)
+	}
+	return nil
+}
+
+// lineSuffix formats a robots.txt line number for display, or "" if it is unknown.
+func lineSuffix(line int) string {
+	if line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (line %d)", line)
+}
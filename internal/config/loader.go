@@ -118,6 +118,14 @@ func mergeInt(value, fallback int) int {
 	return value
 }
 
+// mergeInt64 returns value if non-zero, otherwise returns fallback.
+func mergeInt64(value, fallback int64) int64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
 // mergeFloat64 returns value if non-zero, otherwise returns fallback.
 func mergeFloat64(value, fallback float64) float64 {
 	if value == 0 {
@@ -135,10 +143,59 @@ func (l *Loader) MergeWithDefaults(config *domain.Config) *domain.Config {
 	config.Concurrency = mergeInt(config.Concurrency, defaults.Concurrency)
 	config.Duration = mergeString(config.Duration, defaults.Duration)
 	config.Timeout = mergeString(config.Timeout, defaults.Timeout)
+	config.MetricsInterval = mergeString(config.MetricsInterval, defaults.MetricsInterval)
 	config.Rate = mergeFloat64(config.Rate, defaults.Rate)
 	config.UserAgent = mergeString(config.UserAgent, defaults.UserAgent)
 	config.MaxDepth = mergeInt(config.MaxDepth, defaults.MaxDepth)
 	config.QueueSize = mergeInt(config.QueueSize, defaults.QueueSize)
+	config.MaxResponseSize = mergeInt64(config.MaxResponseSize, defaults.MaxResponseSize)
+	config.MaxURLValidations = mergeInt(config.MaxURLValidations, defaults.MaxURLValidations)
+
+	// Merge frontier settings
+	config.Frontier.VisitedSet = mergeString(config.Frontier.VisitedSet, defaults.Frontier.VisitedSet)
+	config.Frontier.BloomCapacity = mergeInt(config.Frontier.BloomCapacity, defaults.Frontier.BloomCapacity)
+	config.Frontier.BloomFalsePositiveRate = mergeFloat64(config.Frontier.BloomFalsePositiveRate, defaults.Frontier.BloomFalsePositiveRate)
+	config.Checkpoint.Interval = mergeString(config.Checkpoint.Interval, defaults.Checkpoint.Interval)
+	config.Latency.Precision = mergeInt(config.Latency.Precision, defaults.Latency.Precision)
+	config.Latency.Samples = mergeInt(config.Latency.Samples, defaults.Latency.Samples)
+
+	// Normalization fields omitted from the file were defaulted while decoding
+	if config.Normalization == nil {
+		config.Normalization = defaults.Normalization
+	}
+	config.Normalization.TrailingSlash = mergeString(config.Normalization.TrailingSlash, domain.TrailingSlashKeep)
+
+	// Redirect fields omitted from the file were defaulted while decoding
+	if config.Redirects == nil {
+		config.Redirects = defaults.Redirects
+	}
+
+	// Trap limits omitted from the file were defaulted while decoding; zero disables one
+	if config.Traps == nil {
+		config.Traps = defaults.Traps
+	}
+
+	if config.Denylist == nil {
+		config.Denylist = defaults.Denylist
+	}
+	if config.Routes == nil {
+		config.Routes = defaults.Routes
+	}
+	config.Routes.Sort = mergeString(config.Routes.Sort, defaults.Routes.Sort)
+	if config.Success == nil {
+		config.Success = defaults.Success
+	}
+
+	if config.Forms == nil {
+		config.Forms = defaults.Forms
+	}
+	config.Forms.MaxCombinations = mergeInt(config.Forms.MaxCombinations, defaults.Forms.MaxCombinations)
+	if len(config.Forms.TextValues) == 0 {
+		config.Forms.TextValues = defaults.Forms.TextValues
+	}
+	if len(config.LinkExtractors) == 0 {
+		config.LinkExtractors = defaults.LinkExtractors
+	}
 
 	// Merge performance targets
 	pt := &config.PerformanceTargets
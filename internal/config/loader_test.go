@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/1mb-dev/lobster/v2/internal/domain"
@@ -193,6 +194,136 @@ func TestMergeWithDefaults_EmptyConfig(t *testing.T) {
 	if merged.QueueSize != defaults.QueueSize {
 		t.Errorf("Expected merged QueueSize %d, got %d", defaults.QueueSize, merged.QueueSize)
 	}
+	if merged.MaxResponseSize != domain.DefaultMaxResponseSize {
+		t.Errorf("Expected merged MaxResponseSize %d, got %d", domain.DefaultMaxResponseSize, merged.MaxResponseSize)
+	}
+	if merged.Normalization == nil {
+		t.Fatal("Expected merged Normalization to be set")
+	}
+	if !reflect.DeepEqual(*merged.Normalization, *defaults.Normalization) {
+		t.Errorf("Expected merged Normalization %+v, got %+v", *defaults.Normalization, *merged.Normalization)
+	}
+	if merged.Redirects == nil || *merged.Redirects != *defaults.Redirects {
+		t.Errorf("Expected merged Redirects %+v, got %+v", *defaults.Redirects, merged.Redirects)
+	}
+	if merged.Frontier != defaults.Frontier {
+		t.Errorf("Expected merged Frontier %+v, got %+v", defaults.Frontier, merged.Frontier)
+	}
+	if merged.Checkpoint != defaults.Checkpoint {
+		t.Errorf("Expected merged Checkpoint %+v, got %+v", defaults.Checkpoint, merged.Checkpoint)
+	}
+}
+
+func TestMergeWithDefaults_NormalizationBlock(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configJSON := `{"normalization": {"honor_canonical": true, "sort_query": false}}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	loader := NewLoader()
+	config, err := loader.LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() returned error: %v", err)
+	}
+	merged := loader.MergeWithDefaults(config)
+
+	// Fields set in the block are kept; omitted ones get defaults
+	want := domain.DefaultURLNormalization()
+	want.HonorCanonical = true
+	want.SortQuery = false
+	if !reflect.DeepEqual(*merged.Normalization, want) {
+		t.Errorf("Expected merged Normalization %+v, got %+v", want, *merged.Normalization)
+	}
+}
+
+func TestMergeWithDefaults_RedirectsBlock(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configJSON := `{"redirects": {"max_hops": 0, "separate_results": true}}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	loader := NewLoader()
+	config, err := loader.LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() returned error: %v", err)
+	}
+	merged := loader.MergeWithDefaults(config)
+
+	// Fields set in the block are kept, including a 0 limit; omitted ones get defaults
+	if !merged.Redirects.FollowCrossScope {
+		t.Error("Expected omitted follow_cross_scope to keep its default true")
+	}
+	if !merged.Redirects.SeparateResults {
+		t.Error("Expected explicit SeparateResults to be preserved")
+	}
+	if merged.Redirects.MaxHops != 0 {
+		t.Errorf("Expected explicit max_hops 0 to follow no redirects, got %d", merged.Redirects.MaxHops)
+	}
+	if merged.Redirects.LongChainHops != 3 {
+		t.Errorf("Expected default long_chain_hops 3, got %d", merged.Redirects.LongChainHops)
+	}
+}
+
+func TestMergeWithDefaults_TrapsBlock(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configJSON := `{"traps": {"max_path_variants": 500, "max_segment_repeats": 0, "session_params": ["token"]}}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	loader := NewLoader()
+	config, err := loader.LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() returned error: %v", err)
+	}
+	merged := loader.MergeWithDefaults(config)
+
+	if merged.Traps.Disabled {
+		t.Error("Expected an explicit traps block to keep detection enabled")
+	}
+	if merged.Traps.MaxPathVariants != 500 || len(merged.Traps.SessionParams) != 1 {
+		t.Errorf("Expected explicit values to be preserved, got %+v", merged.Traps)
+	}
+	if merged.Traps.MaxSegmentRepeats != 0 {
+		t.Errorf("Expected explicit zero to disable the segment check, got %d", merged.Traps.MaxSegmentRepeats)
+	}
+	if merged.Traps.MaxParamCombinations != 25 || merged.Traps.CalendarYearsAhead != 2 {
+		t.Errorf("Expected default limits for omitted fields, got %+v", merged.Traps)
+	}
+}
+
+func TestMergeWithDefaults_FormsBlock(t *testing.T) {
+	loader := NewLoader()
+	config := &domain.Config{
+		Forms: &domain.FormPolicy{SubmitPost: true},
+	}
+
+	merged := loader.MergeWithDefaults(config)
+
+	if !merged.Forms.SubmitPost {
+		t.Error("Expected explicit submit_post to be preserved")
+	}
+	if merged.Forms.MaxCombinations != 10 || len(merged.Forms.TextValues) != 1 || merged.Forms.TextValues[0] != "test" {
+		t.Errorf("Expected default combinations and text values for omitted fields, got %+v", merged.Forms)
+	}
+}
+
+func TestMergeWithDefaults_RoutesBlock(t *testing.T) {
+	loader := NewLoader()
+	config := &domain.Config{
+		Routes: &domain.RoutePolicy{Templates: []string{"/users/:id"}},
+	}
+
+	merged := loader.MergeWithDefaults(config)
+
+	if len(merged.Routes.Templates) != 1 || merged.Routes.Templates[0] != "/users/:id" {
+		t.Errorf("Expected explicit templates to be preserved, got %v", merged.Routes.Templates)
+	}
+	if merged.Routes.Sort != domain.RouteSortRequests {
+		t.Errorf("Expected default sort %q, got %q", domain.RouteSortRequests, merged.Routes.Sort)
+	}
 }
 
 func TestMergeWithDefaults_PartialConfig(t *testing.T) {
@@ -0,0 +1,172 @@
+package crawler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// trackPending records a task as part of the frontier until it is completed.
+func (c *Crawler) trackPending(task domain.URLTask) {
+	if c.pending == nil {
+		return
+	}
+	c.pendingMu.Lock()
+	c.pending[requestKey(task.Method, task.URL, task.Body)] = task
+	c.pendingMu.Unlock()
+}
+
+// Complete marks a task as processed so it is no longer part of the frontier
+func (c *Crawler) Complete(task domain.URLTask) {
+	if c.pending == nil {
+		return
+	}
+	c.pendingMu.Lock()
+	delete(c.pending, requestKey(task.Method, task.URL, task.Body))
+	c.pendingMu.Unlock()
+}
+
+// Snapshot returns the frontier and deduplication state for checkpointing.
+// The frontier is only available when the crawler was created with TrackFrontier.
+// Spilled URLs are referenced by their files rather than copied, so those files
+// are kept for the checkpoint. Callers must ensure no URLs are added or completed
+// while the snapshot is taken; spilled URLs may still be moved back into the queue.
+func (c *Crawler) Snapshot() (domain.CrawlState, error) {
+	state := domain.CrawlState{
+		Discovered:           c.GetDiscoveredCount(),
+		Dropped:              c.GetDroppedCount(),
+		NormalizedDuplicates: c.GetNormalizedDuplicateCount(),
+		CanonicalDuplicates:  c.GetCanonicalDuplicateCount(),
+	}
+
+	c.pendingMu.Lock()
+	state.Frontier = make([]domain.URLTask, 0, len(c.pending))
+	for _, task := range c.pending {
+		state.Frontier = append(state.Frontier, task)
+	}
+	if c.pending != nil && c.spill != nil {
+		segments, undelivered, err := c.spill.Segments()
+		if err != nil {
+			c.pendingMu.Unlock()
+			return domain.CrawlState{}, err
+		}
+		state.Spilled = segments
+		state.Frontier = append(state.Frontier, undelivered...)
+	}
+	c.pendingMu.Unlock()
+
+	// Breadth-first order, with URL as a tie-breaker for stable output
+	sort.Slice(state.Frontier, func(i, j int) bool {
+		if state.Frontier[i].Depth != state.Frontier[j].Depth {
+			return state.Frontier[i].Depth < state.Frontier[j].Depth
+		}
+		return state.Frontier[i].URL < state.Frontier[j].URL
+	})
+
+	switch set := c.discoveredURLs.(type) {
+	case *memorySet:
+		set.m.Range(func(key, _ any) bool {
+			state.Visited = append(state.Visited, key.(string))
+			return true
+		})
+		sort.Strings(state.Visited)
+	case *bloomSet:
+		set.mu.Lock()
+		state.VisitedBloom = append([]uint64(nil), set.bits...)
+		set.mu.Unlock()
+	}
+
+	if c.referrers != nil {
+		c.referrers.mu.Lock()
+		state.Referrers = make(map[string]domain.ReferrerList, len(c.referrers.m))
+		for target, list := range c.referrers.m {
+			state.Referrers[target] = domain.ReferrerList{
+				Referrers: append([]domain.Referrer(nil), list.Referrers...),
+				Links:     list.Links,
+			}
+		}
+		c.referrers.mu.Unlock()
+	}
+
+	if c.traps != nil {
+		traps := c.traps.snapshot()
+		state.Traps = &traps
+	}
+	if c.denylist != nil {
+		state.DeniedPaths, state.DeniedLinks = c.denylist.snapshot()
+	}
+
+	return state, nil
+}
+
+// Restore loads a snapshot taken by Snapshot and queues its frontier, reading spilled
+// URLs back from the files it references. Frontier URLs that do not fit in the queue
+// are spilled or counted as dropped.
+func (c *Crawler) Restore(state domain.CrawlState, urlQueue chan<- domain.URLTask) error {
+	if len(state.Spilled) > 0 && c.spill == nil {
+		return fmt.Errorf("checkpoint frontier spilled to disk; resume with -spill-dir")
+	}
+
+	switch set := c.discoveredURLs.(type) {
+	case *memorySet:
+		if len(state.VisitedBloom) > 0 {
+			return fmt.Errorf("checkpoint uses a bloom visited set; resume with -visited-set bloom")
+		}
+		for _, key := range state.Visited {
+			set.Add(key)
+		}
+	case *bloomSet:
+		if len(state.VisitedBloom) > 0 {
+			set.mu.Lock()
+			if len(state.VisitedBloom) != len(set.bits) {
+				set.mu.Unlock()
+				return fmt.Errorf("checkpoint Bloom filter has %d words, configured filter has %d; resume with the same bloom settings",
+					len(state.VisitedBloom), len(set.bits))
+			}
+			copy(set.bits, state.VisitedBloom)
+			set.mu.Unlock()
+		}
+		// A memory visited set converts losslessly into a Bloom filter
+		for _, key := range state.Visited {
+			set.Add(key)
+		}
+	}
+
+	c.discoveredCnt.Store(int64(state.Discovered))
+	c.droppedCnt.Store(int64(state.Dropped))
+	c.normalizedCnt.Store(int64(state.NormalizedDuplicates))
+	c.canonicalDupCnt.Store(int64(state.CanonicalDuplicates))
+
+	if c.referrers != nil {
+		c.referrers.mu.Lock()
+		for target, list := range state.Referrers {
+			restored := list
+			c.referrers.m[target] = &restored
+		}
+		c.referrers.mu.Unlock()
+	}
+
+	if c.traps != nil && state.Traps != nil {
+		c.traps.restore(*state.Traps)
+	}
+	if c.denylist != nil {
+		c.denylist.restore(state.DeniedPaths, state.DeniedLinks)
+	}
+
+	for _, task := range state.Frontier {
+		c.enqueue(task, urlQueue)
+	}
+	for _, segment := range state.Spilled {
+		err := readSpillSegment(segment, func(task domain.URLTask) {
+			c.enqueue(task, urlQueue)
+		})
+		if err != nil {
+			return err
+		}
+		// The next checkpoint holds these URLs in this run's own files
+		c.spill.Retire(segment.Path)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package crawler
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// snapshot returns c's checkpoint state, failing the test on error.
+func snapshot(t *testing.T, c *Crawler) domain.CrawlState {
+	t.Helper()
+	state, err := c.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	return state
+}
+
+func TestSnapshot_FrontierExcludesCompleted(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	c.AddURL("/", 0, urlQueue)
+	c.AddURL("/b", 1, urlQueue)
+	c.AddURL("/a", 1, urlQueue)
+	c.Complete(<-urlQueue)
+
+	state := snapshot(t, c)
+
+	expected := []domain.URLTask{
+		{URL: "http://example.com/a", Depth: 1},
+		{URL: "http://example.com/b", Depth: 1},
+	}
+	if len(state.Frontier) != len(expected) {
+		t.Fatalf("Expected frontier %v, got %v", expected, state.Frontier)
+	}
+	for i := range expected {
+		if state.Frontier[i] != expected[i] {
+			t.Errorf("Expected frontier[%d] = %v, got %v", i, expected[i], state.Frontier[i])
+		}
+	}
+	if len(state.Visited) != 3 {
+		t.Errorf("Expected 3 visited URLs, got %v", state.Visited)
+	}
+	if state.Discovered != 3 {
+		t.Errorf("Expected discovered count 3, got %d", state.Discovered)
+	}
+}
+
+func TestSnapshot_DroppedURLsLeaveFrontier(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 1)
+
+	c.AddURL("/", 0, urlQueue)
+	c.AddURL("/dropped", 1, urlQueue)
+
+	state := snapshot(t, c)
+	if len(state.Frontier) != 1 {
+		t.Errorf("Expected only the queued URL in the frontier, got %v", state.Frontier)
+	}
+	if state.Dropped != 1 {
+		t.Errorf("Expected dropped count 1, got %d", state.Dropped)
+	}
+}
+
+func TestRestore_RoundTrip(t *testing.T) {
+	original := newTestCrawler(t, 3, Options{TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 10)
+	original.AddURL("/", 0, urlQueue)
+	original.AddURL("/next", 1, urlQueue)
+	original.Complete(<-urlQueue)
+	state := snapshot(t, original)
+
+	resumed := newTestCrawler(t, 3, Options{TrackFrontier: true})
+	resumedQueue := make(chan domain.URLTask, 10)
+	if err := resumed.Restore(state, resumedQueue); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if len(resumedQueue) != 1 {
+		t.Fatalf("Expected 1 restored task in the queue, got %d", len(resumedQueue))
+	}
+	if task := <-resumedQueue; task.URL != "http://example.com/next" || task.Depth != 1 {
+		t.Errorf("Expected restored task /next at depth 1, got %+v", task)
+	}
+
+	// URLs visited before the checkpoint must not be crawled again
+	if result := resumed.AddURL("/", 0, resumedQueue); result.Reason != domain.AddURLDuplicate {
+		t.Errorf("Expected base URL to be a duplicate after restore, got %s", result.Reason)
+	}
+	if got := resumed.GetDiscoveredCount(); got != 2 {
+		t.Errorf("Expected discovered count 2 after restore, got %d", got)
+	}
+
+	// The restored task stays in the frontier until completed
+	if got := len(snapshot(t, resumed).Frontier); got != 1 {
+		t.Errorf("Expected restored task to be tracked, frontier size %d", got)
+	}
+}
+
+func TestSnapshot_SpilledFrontierStaysOnDisk(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{Frontier: spillFrontier(t), TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 1)
+	c.AddURL("/", 0, urlQueue)
+	for _, path := range []string{"/a", "/b", "/c", "/d"} {
+		c.AddURL(path, 1, urlQueue)
+	}
+
+	// Only the queued task is held in memory
+	if len(c.pending) != 1 {
+		t.Errorf("Expected only the queued task to be tracked in memory, got %d", len(c.pending))
+	}
+	state := snapshot(t, c)
+	if len(state.Frontier) != 1 || len(state.Spilled) != 1 || state.Spilled[0].Count != 4 || state.FrontierSize() != 5 {
+		t.Fatalf("Expected 1 queued task and 4 spilled by reference, got %+v and %+v", state.Frontier, state.Spilled)
+	}
+
+	c.Complete(<-urlQueue)
+	if moved := c.DrainSpilled(context.Background(), urlQueue); moved != 1 {
+		t.Fatalf("Expected 1 spilled URL moved into the queue, moved %d", moved)
+	}
+	state = snapshot(t, c)
+	if len(state.Frontier) != 1 || state.Frontier[0].URL != "http://example.com/a" {
+		t.Errorf("Expected the moved task in the frontier, got %+v", state.Frontier)
+	}
+	if segment := state.Spilled[0]; segment.Count != 3 || segment.Offset == 0 {
+		t.Errorf("Expected 3 unread spilled tasks past the moved one, got %+v", segment)
+	}
+
+	// The checkpoint references the spill file, so it outlives the crawler
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(state.Spilled[0].Path); err != nil {
+		t.Errorf("Expected the spill file to be kept for resume, stat error: %v", err)
+	}
+}
+
+func TestRestore_SpilledFrontier(t *testing.T) {
+	original := newTestCrawler(t, 3, Options{Frontier: spillFrontier(t), TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 1)
+	original.AddURL("/", 0, urlQueue)
+	for _, path := range []string{"/a", "/b", "/c"} {
+		original.AddURL(path, 1, urlQueue)
+	}
+	original.Complete(<-urlQueue)
+	state := snapshot(t, original)
+	// URLs spilled after the snapshot are not part of it
+	original.AddURL("/later", 1, urlQueue)
+	original.AddURL("/later2", 1, urlQueue)
+
+	if err := newTestCrawler(t, 3, Options{TrackFrontier: true}).Restore(state, make(chan domain.URLTask, 10)); err == nil {
+		t.Error("Expected an error restoring a spilled frontier without a spill directory")
+	}
+
+	resumed := newTestCrawler(t, 3, Options{Frontier: spillFrontier(t), TrackFrontier: true})
+	resumedQueue := make(chan domain.URLTask, 1)
+	if err := resumed.Restore(state, resumedQueue); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := snapshot(t, resumed).FrontierSize(); got != 3 {
+		t.Errorf("Expected 3 restored frontier URLs, got %d", got)
+	}
+	// The resumed crawler's own files now hold the frontier
+	if _, err := os.Stat(state.Spilled[0].Path); !os.IsNotExist(err) {
+		t.Errorf("Expected the restored spill file to be removed, stat error: %v", err)
+	}
+
+	var urls []string
+	for {
+		select {
+		case task := <-resumedQueue:
+			urls = append(urls, task.URL)
+			continue
+		default:
+		}
+		if resumed.DrainSpilled(context.Background(), resumedQueue) == 0 {
+			break
+		}
+	}
+	want := []string{"http://example.com/a", "http://example.com/b", "http://example.com/c"}
+	if !slices.Equal(urls, want) {
+		t.Errorf("Expected restored URLs %v, got %v", want, urls)
+	}
+}
+
+func TestRestore_BloomVisitedSet(t *testing.T) {
+	frontier := domain.DefaultFrontierConfig()
+	frontier.VisitedSet = domain.VisitedSetBloom
+	frontier.BloomCapacity = 1000
+
+	original := newTestCrawler(t, 3, Options{Frontier: frontier, TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 10)
+	original.AddURL("/seen", 1, urlQueue)
+	state := snapshot(t, original)
+	if len(state.VisitedBloom) == 0 {
+		t.Fatal("Expected Bloom filter bits in snapshot")
+	}
+
+	resumed := newTestCrawler(t, 3, Options{Frontier: frontier, TrackFrontier: true})
+	if err := resumed.Restore(state, make(chan domain.URLTask, 10)); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result := resumed.AddURL("/seen", 1, urlQueue); result.Reason != domain.AddURLDuplicate {
+		t.Errorf("Expected restored Bloom filter to report /seen as duplicate, got %s", result.Reason)
+	}
+
+	// A differently sized filter cannot take the checkpointed bits
+	frontier.BloomCapacity = 100000
+	resized := newTestCrawler(t, 3, Options{Frontier: frontier, TrackFrontier: true})
+	err := resized.Restore(state, make(chan domain.URLTask, 10))
+	if err == nil || !strings.Contains(err.Error(), "same bloom settings") {
+		t.Errorf("Expected Bloom size mismatch error, got %v", err)
+	}
+
+	// Nor can an exact set, which would silently forget every visited URL
+	exact := newTestCrawler(t, 3, Options{TrackFrontier: true})
+	if err := exact.Restore(state, make(chan domain.URLTask, 10)); err == nil {
+		t.Error("Expected error restoring Bloom state into a memory visited set")
+	}
+}
@@ -2,6 +2,7 @@
 package crawler
 
 import (
+	"fmt"
 	"html"
 	"net/url"
 	"regexp"
@@ -12,28 +13,145 @@ import (
 	"github.com/1mb-dev/lobster/v2/internal/domain"
 )
 
+// maxAnchorTextLen is the number of characters of anchor text kept per link.
+const maxAnchorTextLen = 100
+
+var (
+	// anchorTagPattern matches the start of an <a> tag up to one of its attributes
+	anchorTagPattern = regexp.MustCompile(`(?i)^<a\s`)
+	// anchorClosePattern matches the closing </a> tag
+	anchorClosePattern = regexp.MustCompile(`(?i)</a\s*>`)
+	// tagPattern matches any tag, for stripping markup from anchor text
+	tagPattern = regexp.MustCompile(`<[^>]*>`)
+	// altAttrPattern extracts an image's alt text for image-only links
+	altAttrPattern = regexp.MustCompile(`(?i)\balt\s*=\s*["']([^"']*)["']`)
+	// relAttrPattern extracts a tag's rel attribute
+	relAttrPattern = regexp.MustCompile(`(?i)\brel\s*=\s*["']([^"']*)["']`)
+)
+
 // Crawler handles URL discovery and link extraction
 type Crawler struct {
-	discoveredURLs sync.Map
-	baseURL        *url.URL
-	urlPattern     *regexp.Regexp
-	maxDepth       int
-	discoveredCnt  atomic.Int64 // O(1) counter for discovered URLs
-	droppedCnt     atomic.Int64 // Counter for URLs dropped due to queue full
+	discoveredURLs  visitedSet
+	spill           *spillQueue // Disk overflow for the URL queue (nil = drop on overflow)
+	baseURL         *url.URL
+	urlPattern      *regexp.Regexp
+	normalizer      *Normalizer
+	referrers       *referrerIndex     // Pages linking to each URL (nil = not tracked)
+	traps           *trapDetector      // Crawler trap detection (nil = disabled)
+	denylist        *denylist          // Destructive links never followed (nil = disabled)
+	forms           *domain.FormPolicy // Form discovery settings (nil = disabled)
+	pendingMu       sync.Mutex
+	pending         map[string]domain.URLTask // Queued or in-flight tasks by URL, not spilled ones (nil = not tracked)
+	maxDepth        int
+	checkExternal   bool         // Queue off-site links as external tasks instead of rejecting them
+	discoveredCnt   atomic.Int64 // O(1) counter for discovered URLs
+	droppedCnt      atomic.Int64 // Counter for URLs dropped due to queue full
+	normalizedCnt   atomic.Int64 // Counter for links deduplicated only after normalization
+	canonicalDupCnt atomic.Int64 // Counter for pages deduplicated by rel=canonical
 }
 
-// New creates a new crawler
+// Options configures optional crawler behavior.
+type Options struct {
+	// Normalization controls URL canonicalization for deduplication.
+	Normalization domain.URLNormalization
+	// Frontier controls queue overflow spilling and the visited-set implementation.
+	Frontier domain.FrontierConfig
+	// TrackFrontier records queued URLs until Complete is called, enabling Snapshot.
+	TrackFrontier bool
+	// TrackReferrers records the pages linking to each URL, enabling GetReferrers.
+	TrackReferrers bool
+	// Traps controls crawler trap detection; zero limits disable the corresponding check.
+	Traps domain.TrapPolicy
+	// Denylist controls which links are never followed.
+	Denylist domain.DenylistPolicy
+	// Forms controls the requests generated from HTML forms.
+	Forms domain.FormPolicy
+	// CheckExternal queues off-site links as external tasks to be checked but not crawled.
+	CheckExternal bool
+}
+
+// New creates a new crawler with default options
 func New(baseURL string, maxDepth int) (*Crawler, error) {
+	return NewWithOptions(baseURL, maxDepth, Options{
+		Normalization: domain.DefaultURLNormalization(),
+		Traps:         domain.DefaultTrapPolicy(),
+		Forms:         domain.DefaultFormPolicy(),
+	})
+}
+
+// NewWithOptions creates a new crawler with the given options
+func NewWithOptions(baseURL string, maxDepth int, opts Options) (*Crawler, error) {
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Crawler{
-		baseURL:    parsedURL,
-		urlPattern: regexp.MustCompile(`href=["']([^"']+)["']`),
-		maxDepth:   maxDepth,
-	}, nil
+	discovered, err := newVisitedSet(opts.Frontier)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizer := NewNormalizer(opts.Normalization)
+	normalizer.Normalize(parsedURL)
+
+	c := &Crawler{
+		discoveredURLs: discovered,
+		baseURL:        parsedURL,
+		urlPattern:     regexp.MustCompile(`href=["']([^"']+)["']`),
+		normalizer:     normalizer,
+		maxDepth:       maxDepth,
+		checkExternal:  opts.CheckExternal,
+	}
+
+	if opts.TrackFrontier {
+		c.pending = make(map[string]domain.URLTask)
+	}
+	if opts.TrackReferrers {
+		c.referrers = newReferrerIndex()
+	}
+	if !opts.Traps.Disabled {
+		c.traps = newTrapDetector(opts.Traps)
+	}
+	if !opts.Forms.Disabled {
+		c.forms = &opts.Forms
+	}
+	if !opts.Denylist.Disabled {
+		if c.denylist, err = newDenylist(opts.Denylist); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Frontier.SpillDir != "" {
+		spill, err := newSpillQueue(opts.Frontier.SpillDir, opts.TrackFrontier)
+		if err != nil {
+			return nil, err
+		}
+		c.spill = spill
+	}
+
+	return c, nil
+}
+
+// newVisitedSet creates the visited set selected by the frontier configuration
+// ("" selects the in-memory set).
+func newVisitedSet(cfg domain.FrontierConfig) (visitedSet, error) {
+	switch cfg.VisitedSet {
+	case "", domain.VisitedSetMemory:
+		return &memorySet{}, nil
+	case domain.VisitedSetBloom:
+		defaults := domain.DefaultFrontierConfig()
+		capacity := cfg.BloomCapacity
+		if capacity <= 0 {
+			capacity = defaults.BloomCapacity
+		}
+		fpRate := cfg.BloomFalsePositiveRate
+		if fpRate <= 0 || fpRate >= 1 {
+			fpRate = defaults.BloomFalsePositiveRate
+		}
+		return newBloomSet(capacity, fpRate), nil
+	default:
+		return nil, fmt.Errorf("invalid visited set %q: must be one of memory, bloom", cfg.VisitedSet)
+	}
 }
 
 // ExtractLinks extracts all links from HTML body
@@ -55,6 +173,76 @@ func (c *Crawler) ExtractLinks(body string) []string {
 	return links
 }
 
+// ExtractLinksWithText extracts the same links as ExtractLinks, together with
+// the anchor text of links found in <a> elements
+func (c *Crawler) ExtractLinksWithText(body string) []domain.Link {
+	matches := c.urlPattern.FindAllStringSubmatchIndex(body, -1)
+	links := make([]domain.Link, 0, len(matches))
+
+	for _, match := range matches {
+		link := strings.TrimSpace(body[match[2]:match[3]])
+		if !c.isValidLink(link) {
+			continue
+		}
+		links = append(links, domain.Link{
+			URL:        html.UnescapeString(link),
+			AnchorText: anchorText(body, match[0], match[1]),
+			NoFollow:   relNofollow(body, match[0], match[1]),
+		})
+	}
+
+	return links
+}
+
+// anchorText returns the text of the <a> element whose href spans body[start:end],
+// or "" if the href belongs to another element. Image-only links fall back to the alt text.
+func anchorText(body string, start, end int) string {
+	tagStart := strings.LastIndexByte(body[:start], '<')
+	if tagStart < 0 || !anchorTagPattern.MatchString(body[tagStart:start]) {
+		return ""
+	}
+	tagEnd := strings.IndexByte(body[end:], '>')
+	if tagEnd < 0 {
+		return ""
+	}
+	inner := body[end+tagEnd+1:]
+	if closing := anchorClosePattern.FindStringIndex(inner); closing != nil {
+		inner = inner[:closing[0]]
+	} else {
+		return ""
+	}
+
+	text := strings.Join(strings.Fields(html.UnescapeString(tagPattern.ReplaceAllString(inner, " "))), " ")
+	if text == "" {
+		if alt := altAttrPattern.FindStringSubmatch(inner); alt != nil {
+			text = strings.Join(strings.Fields(html.UnescapeString(alt[1])), " ")
+		}
+	}
+	if runes := []rune(text); len(runes) > maxAnchorTextLen {
+		text = string(runes[:maxAnchorTextLen]) + "…"
+	}
+	return text
+}
+
+// relNofollow reports whether the tag whose href spans body[start:end] has rel="nofollow".
+func relNofollow(body string, start, end int) bool {
+	tagStart := strings.LastIndexByte(body[:start], '<')
+	tagEnd := strings.IndexByte(body[end:], '>')
+	if tagStart < 0 || tagEnd < 0 {
+		return false
+	}
+	rel := relAttrPattern.FindStringSubmatch(body[tagStart : end+tagEnd])
+	if rel == nil {
+		return false
+	}
+	for _, value := range strings.Fields(rel[1]) {
+		if strings.EqualFold(value, "nofollow") {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidLink checks if a link should be followed
 func (c *Crawler) isValidLink(link string) bool {
 	if link == "" {
@@ -73,29 +261,66 @@ func (c *Crawler) isValidLink(link string) bool {
 
 // AddURL adds a URL to the discovery queue if it's valid and not already discovered
 func (c *Crawler) AddURL(rawURL string, depth int, urlQueue chan<- domain.URLTask) domain.AddURLResult {
+	return c.AddLink(domain.Link{URL: rawURL}, depth, urlQueue)
+}
+
+// AddLink adds a link found on a page to the discovery queue if it's valid and not
+// already discovered. Relative links resolve against the page they were found on,
+// and the page is recorded as a referrer of the target, even for duplicates.
+func (c *Crawler) AddLink(link domain.Link, depth int, urlQueue chan<- domain.URLTask) domain.AddURLResult {
 	// Parse and validate URL
-	parsedURL, err := url.Parse(rawURL)
+	parsedURL, err := url.Parse(link.URL)
 	if err != nil {
 		return domain.AddURLResult{Added: false, Reason: domain.AddURLParseError}
 	}
 
 	// Make relative URLs absolute
 	if !parsedURL.IsAbs() {
-		parsedURL = c.baseURL.ResolveReference(parsedURL)
+		parsedURL = c.resolveBase(link.SourceURL).ResolveReference(parsedURL)
 	}
 
-	// Only process URLs from the same host
-	if parsedURL.Host != c.baseURL.Host {
-		return domain.AddURLResult{Added: false, Reason: domain.AddURLInvalidHost}
+	// Drop session IDs so the same page reached in different sessions is one URL
+	sessionURL, sessionParam := "", ""
+	if c.traps != nil {
+		sessionURL = parsedURL.String()
+		sessionParam = c.traps.stripSessionIDs(parsedURL)
 	}
 
-	// Clean URL (remove fragment, normalize)
+	// Remember the literal form (minus fragment) to attribute duplicates to normalization
 	parsedURL.Fragment = ""
-	cleanURL := parsedURL.String()
+	literalURL := parsedURL.String()
+
+	// Canonicalize so equivalent URLs share one deduplication key
+	cleanURL := c.normalizer.Normalize(parsedURL)
+
+	// Only process URLs from the same host, unless off-site links are being checked
+	external := parsedURL.Host != c.baseURL.Host
+	if external && (!c.checkExternal || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https")) {
+		return domain.AddURLResult{Added: false, Reason: domain.AddURLInvalidHost}
+	}
+
+	if sessionParam != "" {
+		c.traps.record(domain.TrapSessionID, sessionParam, sessionURL)
+	}
+
+	// Never follow links that could end the session or change data; the start URL is always crawled
+	if c.denylist != nil && cleanURL != c.baseURL.String() {
+		if rule := c.denylist.match(parsedURL); rule != "" {
+			c.denylist.record(rule, cleanURL)
+			return domain.AddURLResult{Added: false, Reason: domain.AddURLDenied, URL: cleanURL}
+		}
+	}
+
+	if c.referrers != nil && link.SourceURL != "" {
+		c.referrers.record(cleanURL, domain.Referrer{SourceURL: link.SourceURL, AnchorText: link.AnchorText})
+	}
 
 	// Check if already discovered
-	if _, exists := c.discoveredURLs.LoadOrStore(cleanURL, true); exists {
-		return domain.AddURLResult{Added: false, Reason: domain.AddURLDuplicate}
+	if !c.discoveredURLs.Add(requestKey(link.Method, cleanURL, link.Body)) {
+		if literalURL != cleanURL {
+			c.normalizedCnt.Add(1)
+		}
+		return domain.AddURLResult{Added: false, Reason: domain.AddURLDuplicate, URL: cleanURL}
 	}
 
 	// Track discovered URL count (O(1) instead of iterating sync.Map)
@@ -103,20 +328,94 @@ func (c *Crawler) AddURL(rawURL string, depth int, urlQueue chan<- domain.URLTas
 
 	// Check depth limit
 	if depth > c.maxDepth {
-		return domain.AddURLResult{Added: false, Reason: domain.AddURLDepthExceeded}
+		return domain.AddURLResult{Added: false, Reason: domain.AddURLDepthExceeded, URL: cleanURL}
+	}
+
+	// Suppress URLs from spaces that generate endless variants
+	if c.traps != nil {
+		if reason, pattern := c.traps.check(parsedURL); reason != "" {
+			c.traps.record(reason, pattern, cleanURL)
+			return domain.AddURLResult{Added: false, Reason: domain.AddURLTrap, URL: cleanURL}
+		}
 	}
 
+	task := domain.URLTask{
+		URL:        cleanURL,
+		Method:     link.Method,
+		Body:       link.Body,
+		SourceURL:  link.SourceURL,
+		AnchorText: link.AnchorText,
+		Depth:      depth,
+		External:   external,
+	}
+	if link.FromFeed {
+		task.Feed = link.SourceURL
+	}
+	if link.Confidence != "" {
+		task.Script, task.Confidence = link.SourceURL, link.Confidence
+	}
+	result := c.enqueue(task, urlQueue)
+	result.URL = cleanURL
+	return result
+}
+
+// requestKey identifies a request for deduplication: the URL for GET, and the
+// method, URL and body for form submissions.
+func requestKey(method, cleanURL, body string) string {
+	if method == "" || method == "GET" {
+		return cleanURL
+	}
+	return method + " " + cleanURL + "\n" + body
+}
+
+// resolveBase returns the URL relative links on sourceURL resolve against,
+// falling back to the base URL when the source is unknown
+func (c *Crawler) resolveBase(sourceURL string) *url.URL {
+	if sourceURL == "" {
+		return c.baseURL
+	}
+	source, err := url.Parse(sourceURL)
+	if err != nil || !source.IsAbs() {
+		return c.baseURL
+	}
+	return source
+}
+
+// enqueue places a task on the queue, spilling or dropping it when the queue is full
+func (c *Crawler) enqueue(task domain.URLTask, urlQueue chan<- domain.URLTask) domain.AddURLResult {
+	// Once anything has spilled, new URLs queue behind it to keep breadth-first order
+	if c.spill != nil && c.spill.Len() > 0 {
+		return c.spillTask(task)
+	}
+
+	// Track before sending so a fast worker cannot complete the task first;
+	// spilled tasks are checkpointed from their files instead
+	c.trackPending(task)
+
 	// Add to queue
 	select {
-	case urlQueue <- domain.URLTask{URL: cleanURL, Depth: depth}:
+	case urlQueue <- task:
 		return domain.AddURLResult{Added: true, Reason: domain.AddURLSuccess}
 	default:
+		c.Complete(task)
+		if c.spill != nil {
+			return c.spillTask(task)
+		}
 		// Queue full - track dropped URLs for visibility
 		c.droppedCnt.Add(1)
 		return domain.AddURLResult{Added: false, Reason: domain.AddURLQueueFull}
 	}
 }
 
+// spillTask writes a task to the disk overflow, counting it as dropped if that fails
+func (c *Crawler) spillTask(task domain.URLTask) domain.AddURLResult {
+	if err := c.spill.Push(task); err != nil {
+		c.droppedCnt.Add(1)
+		return domain.AddURLResult{Added: false, Reason: domain.AddURLQueueFull}
+	}
+	return domain.AddURLResult{Added: true, Reason: domain.AddURLSpilled}
+}
+
 // GetDiscoveredCount returns the number of discovered URLs (O(1) operation)
 func (c *Crawler) GetDiscoveredCount() int {
 	return int(c.discoveredCnt.Load())
@@ -126,3 +425,14 @@ func (c *Crawler) GetDiscoveredCount() int {
 func (c *Crawler) GetDroppedCount() int {
 	return int(c.droppedCnt.Load())
 }
+
+// GetNormalizedDuplicateCount returns the number of duplicate links whose literal
+// form differed from their normalized form
+func (c *Crawler) GetNormalizedDuplicateCount() int {
+	return int(c.normalizedCnt.Load())
+}
+
+// GetCanonicalDuplicateCount returns the number of pages whose canonical URL was already discovered
+func (c *Crawler) GetCanonicalDuplicateCount() int {
+	return int(c.canonicalDupCnt.Load())
+}
@@ -2,10 +2,28 @@ package crawler
 
 import (
 	"testing"
+	"time"
 
 	"github.com/1mb-dev/lobster/v2/internal/domain"
 )
 
+// newTestCrawler creates a crawler for http://example.com with the default URL
+// normalization and opts, closed when the test ends. Trap detection sees a fixed
+// date, 2026-01-01, so calendar checks do not depend on the current year.
+func newTestCrawler(t *testing.T, maxDepth int, opts Options) *Crawler {
+	t.Helper()
+	opts.Normalization = domain.DefaultURLNormalization()
+	c, err := NewWithOptions("http://example.com", maxDepth, opts)
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	if c.traps != nil {
+		c.traps.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
 func TestNew_Success(t *testing.T) {
 	c, err := New("http://example.com", 3)
 	if err != nil {
@@ -322,3 +340,107 @@ func TestGetDroppedCount(t *testing.T) {
 		t.Errorf("Expected discovered count 4, got %d", discoveredCount)
 	}
 }
+
+func TestExtractLinksWithText(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	html := `<html><head><link rel="stylesheet" href="/style.css"></head>
+		<body>
+			<a href="/plain">Plain</a>
+			<A class="nav" href="/upper">  Spread
+				over <em>lines</em> </A>
+			<a href="/image"><img src="/logo.png" alt="Company logo"></a>
+			<a href="/entity">Fish &amp; Chips</a>
+			<a href="mailto:me@example.com">Mail</a>
+		</body></html>`
+
+	links := c.ExtractLinksWithText(html)
+
+	expected := []domain.Link{
+		{URL: "/style.css"},
+		{URL: "/plain", AnchorText: "Plain"},
+		{URL: "/upper", AnchorText: "Spread over lines"},
+		{URL: "/image", AnchorText: "Company logo"},
+		{URL: "/entity", AnchorText: "Fish & Chips"},
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("Expected %d links, got %d: %+v", len(expected), len(links), links)
+	}
+	for i, want := range expected {
+		if links[i] != want {
+			t.Errorf("Link %d: expected %+v, got %+v", i, want, links[i])
+		}
+	}
+}
+
+func TestExtractLinksWithText_RelNofollow(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	html := `<a href="/ad" rel="sponsored nofollow">Ad</a>
+		<a rel='NOFOLLOW' href="/login">Log in</a>
+		<a href="/about" rel="noopener">About</a>
+		<a href="/next">Next</a> <span rel="nofollow">not the link's tag</span>`
+
+	want := map[string]bool{"/ad": true, "/login": true, "/about": false, "/next": false}
+	links := c.ExtractLinksWithText(html)
+	if len(links) != len(want) {
+		t.Fatalf("Expected %d links, got %+v", len(want), links)
+	}
+	for _, link := range links {
+		if link.NoFollow != want[link.URL] {
+			t.Errorf("Expected %s NoFollow=%v, got %v", link.URL, want[link.URL], link.NoFollow)
+		}
+	}
+}
+
+func TestAddLink_ResolvesAgainstSource(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+	urlQueue := make(chan domain.URLTask, 10)
+
+	result := c.AddLink(domain.Link{
+		URL:        "intro",
+		SourceURL:  "http://example.com/docs/",
+		AnchorText: "Intro",
+	}, 1, urlQueue)
+	if !result.Added {
+		t.Fatalf("Expected link to be added, got reason: %s", result.Reason)
+	}
+
+	task := <-urlQueue
+	if task.URL != "http://example.com/docs/intro" {
+		t.Errorf("Expected URL resolved against its page, got '%s'", task.URL)
+	}
+	if task.SourceURL != "http://example.com/docs/" || task.AnchorText != "Intro" {
+		t.Errorf("Expected task to carry its source and anchor text, got %+v", task)
+	}
+}
+
+func TestAddLink_External(t *testing.T) {
+	tests := []struct {
+		name          string
+		link          string
+		checkExternal bool
+		wantReason    string
+	}{
+		{"rejected by default", "http://other.com/page", false, domain.AddURLInvalidHost},
+		{"queued when checking external", "http://other.com/page", true, domain.AddURLSuccess},
+		{"non-HTTP scheme rejected", "tel:+15550100", true, domain.AddURLInvalidHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := NewWithOptions("http://example.com", 3, Options{CheckExternal: tt.checkExternal})
+			urlQueue := make(chan domain.URLTask, 10)
+
+			result := c.AddLink(domain.Link{URL: tt.link, SourceURL: "http://example.com/"}, 1, urlQueue)
+			if result.Reason != tt.wantReason {
+				t.Fatalf("Expected reason %s, got %s", tt.wantReason, result.Reason)
+			}
+			if result.Added {
+				if task := <-urlQueue; !task.External {
+					t.Errorf("Expected off-site task to be marked external, got %+v", task)
+				}
+			}
+		})
+	}
+}
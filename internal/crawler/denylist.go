@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// DefaultDenylistPatterns match links whose request could end the session or
+// change data. They are matched case-insensitively against a link's path and query.
+var DefaultDenylistPatterns = []string{
+	`(^|/)(log|sign)[_-]?(out|off)([/?.;]|$)`,
+	`(^|/)(delete|destroy|remove|deactivate|revoke)([/?.;]|$)`,
+	`(^|/)(delete|deactivate|close|cancel)[_-]?(account|subscription|profile|user)([/?.;]|$)`,
+	`unsubscribe`,
+	`[?&](action|do|cmd|op)=(delete|destroy|remove|logout|signout|unsubscribe)(&|$)`,
+}
+
+// maxDeniedExamples is the number of example URLs kept per denylist rule.
+const maxDeniedExamples = 5
+
+// denylist rejects links matching destructive patterns, plus paths found to
+// end the session while crawling.
+type denylist struct {
+	patterns []*regexp.Regexp
+
+	mu      sync.Mutex
+	paths   map[string]bool // host+path denied because it ended the session
+	reports map[string]*domain.DeniedLink
+}
+
+func newDenylist(policy domain.DenylistPolicy) (*denylist, error) {
+	d := &denylist{
+		paths:   make(map[string]bool),
+		reports: make(map[string]*domain.DeniedLink),
+	}
+
+	var patterns []string
+	if !policy.NoDefaults {
+		patterns = append(patterns, DefaultDenylistPatterns...)
+	}
+	patterns = append(patterns, policy.Patterns...)
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("denylist pattern %q: %w", pattern, err)
+		}
+		d.patterns = append(d.patterns, re)
+	}
+
+	return d, nil
+}
+
+// match returns the rule denying u, or "" if u may be followed.
+func (d *denylist) match(u *url.URL) string {
+	d.mu.Lock()
+	denied := d.paths[u.Host+u.Path]
+	d.mu.Unlock()
+	if denied {
+		return domain.DenyRuleSessionInvalidated
+	}
+
+	target := u.EscapedPath()
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	for _, re := range d.patterns {
+		if re.MatchString(target) {
+			// Report the pattern as configured, without the case-insensitive flag
+			return re.String()[len("(?i)"):]
+		}
+	}
+	return ""
+}
+
+// record adds a denied link to the report.
+func (d *denylist) record(rule, rawURL string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	report, ok := d.reports[rule]
+	if !ok {
+		report = &domain.DeniedLink{Rule: rule}
+		d.reports[rule] = report
+	}
+	report.Links++
+	if len(report.Examples) >= maxDeniedExamples {
+		return
+	}
+	for _, example := range report.Examples {
+		if example == rawURL {
+			return
+		}
+	}
+	report.Examples = append(report.Examples, rawURL)
+}
+
+// DenyURL denylists the path of a URL whose request ended the authenticated
+// session, so no link to that path is followed for the rest of the run.
+func (c *Crawler) DenyURL(taskURL string) {
+	if c.denylist == nil {
+		return
+	}
+	parsed, err := url.Parse(taskURL)
+	if err != nil {
+		return
+	}
+	c.denylist.mu.Lock()
+	c.denylist.paths[parsed.Host+parsed.Path] = true
+	c.denylist.mu.Unlock()
+	c.denylist.record(domain.DenyRuleSessionInvalidated, taskURL)
+}
+
+// GetDeniedLinks returns the links not followed because of the denylist,
+// grouped by rule, most-denied first.
+func (c *Crawler) GetDeniedLinks() []domain.DeniedLink {
+	if c.denylist == nil {
+		return nil
+	}
+	_, reports := c.denylist.snapshot()
+	return reports
+}
+
+// snapshot returns the session-invalidating paths and the denied-link reports.
+func (d *denylist) snapshot() ([]string, []domain.DeniedLink) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	paths := make([]string, 0, len(d.paths))
+	for path := range d.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	reports := make([]domain.DeniedLink, 0, len(d.reports))
+	for _, report := range d.reports {
+		copied := *report
+		copied.Examples = append([]string(nil), report.Examples...)
+		reports = append(reports, copied)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Links != reports[j].Links {
+			return reports[i].Links > reports[j].Links
+		}
+		return reports[i].Rule < reports[j].Rule
+	})
+
+	return paths, reports
+}
+
+// restore loads state saved by snapshot.
+func (d *denylist) restore(paths []string, reports []domain.DeniedLink) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, path := range paths {
+		d.paths[path] = true
+	}
+	for _, report := range reports {
+		restored := report
+		d.reports[report.Rule] = &restored
+	}
+}
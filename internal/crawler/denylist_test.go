@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestAddLink_Denylist(t *testing.T) {
+	tests := []struct {
+		link   string
+		denied bool
+	}{
+		{"/logout", true},
+		{"/users/sign_out", true},
+		{"/Log-Off.php", true},
+		{"/account/delete?confirm=1", true},
+		{"/account/close-account", true},
+		{"/newsletter/unsubscribe?id=7", true},
+		{"/admin?action=delete&id=3", true},
+		{"/posts/42/remove/", true},
+		{"/blog/how-to-remove-stains", false},
+		{"/logout-tips-for-admins", false},
+		{"/catalog?sort=deleted", false},
+		{"/about", false},
+	}
+
+	c, _ := New("http://example.com", 3)
+	urlQueue := make(chan domain.URLTask, len(tests))
+
+	for _, tt := range tests {
+		result := c.AddLink(domain.Link{URL: tt.link}, 1, urlQueue)
+		if denied := result.Reason == domain.AddURLDenied; denied != tt.denied {
+			t.Errorf("AddLink(%q): expected denied=%v, got reason %q", tt.link, tt.denied, result.Reason)
+		}
+	}
+}
+
+func TestAddLink_DenylistPolicy(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{
+		Denylist: domain.DenylistPolicy{Patterns: []string{`^/cart/empty`}, NoDefaults: true},
+	})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	if result := c.AddLink(domain.Link{URL: "/Cart/Empty"}, 1, urlQueue); result.Reason != domain.AddURLDenied {
+		t.Errorf("Expected configured pattern to deny case-insensitively, got %q", result.Reason)
+	}
+	if result := c.AddLink(domain.Link{URL: "/logout"}, 1, urlQueue); !result.Added {
+		t.Errorf("Expected built-in patterns dropped with no_defaults, got %q", result.Reason)
+	}
+
+	denied := c.GetDeniedLinks()
+	if len(denied) != 1 || denied[0].Rule != `^/cart/empty` || denied[0].Links != 1 {
+		t.Errorf("Expected one denial reported under the configured pattern, got %+v", denied)
+	}
+
+	if _, err := NewWithOptions("http://example.com", 3, Options{
+		Denylist: domain.DenylistPolicy{Patterns: []string{`(`}},
+	}); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
+
+func TestAddLink_DenylistExemptsStartURL(t *testing.T) {
+	c, _ := New("http://example.com/logout", 3)
+	urlQueue := make(chan domain.URLTask, 10)
+
+	if result := c.AddURL("http://example.com/logout", 0, urlQueue); !result.Added {
+		t.Errorf("Expected the start URL to be crawled, got %q", result.Reason)
+	}
+}
+
+func TestAddLink_DenylistDisabled(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{Denylist: domain.DenylistPolicy{Disabled: true}})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	if result := c.AddLink(domain.Link{URL: "/logout"}, 1, urlQueue); !result.Added {
+		t.Errorf("Expected /logout to be followed with the denylist disabled, got %q", result.Reason)
+	}
+	c.DenyURL("http://example.com/bye")
+	if result := c.AddLink(domain.Link{URL: "/bye"}, 1, urlQueue); !result.Added {
+		t.Errorf("Expected DenyURL to be a no-op with the denylist disabled, got %q", result.Reason)
+	}
+}
+
+func TestDenyURL(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	c.DenyURL("http://example.com/session/end?token=1")
+	if result := c.AddLink(domain.Link{URL: "/session/end?token=2"}, 1, urlQueue); result.Reason != domain.AddURLDenied {
+		t.Errorf("Expected every link to the denied path to be denied, got %q", result.Reason)
+	}
+
+	denied := c.GetDeniedLinks()
+	if len(denied) != 1 || denied[0].Rule != domain.DenyRuleSessionInvalidated || denied[0].Links != 2 {
+		t.Fatalf("Expected the path and its later link under %q, got %+v", domain.DenyRuleSessionInvalidated, denied)
+	}
+
+	resumed := newTestCrawler(t, 3, Options{})
+	if err := resumed.Restore(snapshot(t, c), make(chan domain.URLTask, 10)); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result := resumed.AddLink(domain.Link{URL: "/session/end"}, 1, urlQueue); result.Reason != domain.AddURLDenied {
+		t.Errorf("Expected the denied path to survive a resume, got %q", result.Reason)
+	}
+}
@@ -0,0 +1,174 @@
+package crawler
+
+import (
+	"encoding/xml"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+var (
+	// typeAttrPattern extracts a tag's type attribute
+	typeAttrPattern = regexp.MustCompile(`(?i)\btype\s*=\s*["']([^"']*)["']`)
+	// titleAttrPattern extracts a tag's title attribute
+	titleAttrPattern = regexp.MustCompile(`(?i)\btitle\s*=\s*["']([^"']*)["']`)
+)
+
+// feedMediaTypes are the media types of RSS and Atom feeds.
+var feedMediaTypes = map[string]bool{
+	"application/rss+xml":  true,
+	"application/atom+xml": true,
+	"application/rdf+xml":  true,
+}
+
+// ExtractFeedLinks returns the feeds an HTML page advertises with
+// <link rel="alternate" type="application/rss+xml"> or an Atom equivalent.
+// The feed title, if any, becomes the anchor text.
+func (c *Crawler) ExtractFeedLinks(body string) []domain.Link {
+	var links []domain.Link
+	for _, tag := range linkTagPattern.FindAllString(body, -1) {
+		rel := relAttrPattern.FindStringSubmatch(tag)
+		typ := typeAttrPattern.FindStringSubmatch(tag)
+		href := hrefAttrPattern.FindStringSubmatch(tag)
+		if rel == nil || typ == nil || href == nil || !feedMediaTypes[strings.ToLower(strings.TrimSpace(typ[1]))] {
+			continue
+		}
+		if !strings.Contains(" "+strings.ToLower(rel[1])+" ", " alternate ") {
+			continue
+		}
+		link := domain.Link{URL: html.UnescapeString(strings.TrimSpace(href[1]))}
+		if !c.isValidLink(link.URL) {
+			continue
+		}
+		if title := titleAttrPattern.FindStringSubmatch(tag); title != nil {
+			link.AnchorText = strings.Join(strings.Fields(html.UnescapeString(title[1])), " ")
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// ExtractFeedItems returns the item links of an RSS 0.9x/1.0/2.0 or Atom feed,
+// marked as found in a feed. Item titles become the anchor text. A truncated
+// feed yields the items before the point where it was cut off.
+func (c *Crawler) ExtractFeedItems(body string) []domain.Link {
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	// Item URLs are ASCII in practice, so other charsets are read as-is
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	var (
+		links   []domain.Link
+		item    *feedItem
+		field   string // item field whose text is being read: "link", "title" or "guid"
+		text    strings.Builder
+		inItem  int // nesting depth inside the current item, 0 = not in an item
+		permaOK bool
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break // io.EOF, or a malformed or truncated feed: keep the items read so far
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if item == nil {
+				if name == "item" || name == "entry" {
+					item, inItem = &feedItem{}, 1
+				}
+				continue
+			}
+			inItem++
+			if inItem != 2 {
+				continue
+			}
+			switch name {
+			case "link":
+				// Atom links carry the URL in href; the entry's page is rel="alternate" (the default)
+				if href := xmlAttr(t, "href"); href != "" {
+					if rel := xmlAttr(t, "rel"); (rel == "" || rel == "alternate") && item.link == "" {
+						item.link = href
+					}
+					continue
+				}
+				field = name
+			case "title":
+				field = name
+			case "guid":
+				field, permaOK = name, !strings.EqualFold(xmlAttr(t, "isPermaLink"), "false")
+			}
+			text.Reset()
+
+		case xml.CharData:
+			if field != "" {
+				text.Write(t)
+			}
+
+		case xml.EndElement:
+			if item == nil {
+				continue
+			}
+			if inItem == 2 && field != "" {
+				value := strings.TrimSpace(text.String())
+				switch field {
+				case "link":
+					if item.link == "" {
+						item.link = value
+					}
+				case "title":
+					item.title = strings.Join(strings.Fields(value), " ")
+				case "guid":
+					if permaOK && (strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")) {
+						item.guid = value
+					}
+				}
+				field = ""
+			}
+			inItem--
+			if inItem == 0 {
+				if link := item.url(); c.isValidLink(link) {
+					anchor := item.title
+					if runes := []rune(anchor); len(runes) > maxAnchorTextLen {
+						anchor = string(runes[:maxAnchorTextLen]) + "…"
+					}
+					links = append(links, domain.Link{URL: link, AnchorText: anchor, FromFeed: true})
+				}
+				item = nil
+			}
+		}
+	}
+
+	return links
+}
+
+// feedItem collects the fields of one RSS item or Atom entry.
+type feedItem struct {
+	link  string
+	guid  string
+	title string
+}
+
+// url returns the item's link, falling back to a permalink guid.
+func (i *feedItem) url() string {
+	if i.link != "" {
+		return i.link
+	}
+	return i.guid
+}
+
+// xmlAttr returns the value of the attribute with the given local name.
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
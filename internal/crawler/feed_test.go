@@ -0,0 +1,121 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestExtractFeedLinks(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	body := `<html><head>
+		<link rel="alternate" type="application/rss+xml" title="Blog &amp; News" href="/feed.xml">
+		<link rel="alternate" type="application/atom+xml" href="http://example.com/atom">
+		<link rel="alternate" hreflang="de" href="/de/">
+		<link rel="stylesheet" type="text/css" href="/style.css">
+		<link type="application/rss+xml" href="/no-rel.xml">
+	</head></html>`
+
+	want := []domain.Link{
+		{URL: "/feed.xml", AnchorText: "Blog & News"},
+		{URL: "http://example.com/atom"},
+	}
+	links := c.ExtractFeedLinks(body)
+	if len(links) != len(want) {
+		t.Fatalf("Expected %d links, got %+v", len(want), links)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("Link %d: expected %+v, got %+v", i, want[i], links[i])
+		}
+	}
+}
+
+func TestExtractFeedItems(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	tests := []struct {
+		name string
+		body string
+		want []domain.Link
+	}{
+		{
+			name: "RSS 2.0",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+				<rss version="2.0"><channel>
+					<title>Blog</title>
+					<link>http://example.com/</link>
+					<item><title>First  post</title><link>http://example.com/posts/1</link></item>
+					<item><title>Permalink only</title><guid>http://example.com/posts/2</guid></item>
+					<item><title>Opaque guid</title><guid isPermaLink="false">http://example.com/posts/3</guid></item>
+					<item><title><![CDATA[Fish &amp; chips]]></title><link> /posts/4 </link></item>
+				</channel></rss>`,
+			want: []domain.Link{
+				{URL: "http://example.com/posts/1", AnchorText: "First post", FromFeed: true},
+				{URL: "http://example.com/posts/2", AnchorText: "Permalink only", FromFeed: true},
+				{URL: "/posts/4", AnchorText: "Fish &amp; chips", FromFeed: true},
+			},
+		},
+		{
+			name: "Atom",
+			body: `<?xml version="1.0" encoding="utf-8"?>
+				<feed xmlns="http://www.w3.org/2005/Atom">
+					<link rel="self" href="http://example.com/atom"/>
+					<entry>
+						<title>Entry</title>
+						<link rel="edit" href="http://example.com/edit/1"/>
+						<link href="http://example.com/entries/1"/>
+					</entry>
+					<entry>
+						<title type="html">Alternate</title>
+						<link rel="alternate" type="text/html" href="/entries/2"/>
+					</entry>
+				</feed>`,
+			want: []domain.Link{
+				{URL: "http://example.com/entries/1", AnchorText: "Entry", FromFeed: true},
+				{URL: "/entries/2", AnchorText: "Alternate", FromFeed: true},
+			},
+		},
+		{
+			name: "truncated",
+			body: `<rss><channel><item><link>/posts/1</link></item><item><link>/posts/2</li`,
+			want: []domain.Link{
+				{URL: "/posts/1", FromFeed: true},
+			},
+		},
+		{
+			name: "not a feed",
+			body: `<urlset><url><loc>http://example.com/</loc></url></urlset>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := c.ExtractFeedItems(tt.body)
+			if len(links) != len(tt.want) {
+				t.Fatalf("Expected %d links, got %+v", len(tt.want), links)
+			}
+			for i, want := range tt.want {
+				if links[i] != want {
+					t.Errorf("Link %d: expected %+v, got %+v", i, want, links[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAddLink_TagsFeedItems(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+	queue := make(chan domain.URLTask, 10)
+
+	c.AddLink(domain.Link{URL: "/posts/1", SourceURL: "http://example.com/feed.xml", FromFeed: true}, 1, queue)
+	c.AddLink(domain.Link{URL: "/about", SourceURL: "http://example.com/"}, 1, queue)
+
+	if task := <-queue; task.Feed != "http://example.com/feed.xml" {
+		t.Errorf("Expected feed item tagged with its feed, got %q", task.Feed)
+	}
+	if task := <-queue; task.Feed != "" {
+		t.Errorf("Expected page link without a feed, got %q", task.Feed)
+	}
+}
@@ -0,0 +1,257 @@
+package crawler
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+var (
+	// formPattern matches a <form> element, capturing its attributes and contents
+	formPattern = regexp.MustCompile(`(?is)<form\b([^>]*)>(.*?)</form\s*>`)
+	// controlPattern matches the opening tag of a form control
+	controlPattern = regexp.MustCompile(`(?is)<(input|select|textarea)\b([^>]*)>`)
+	// optionPattern matches an <option> tag and the text following it
+	optionPattern = regexp.MustCompile(`(?is)<option\b([^>]*)>([^<]*)`)
+	// attrPattern matches one attribute of a tag; the value is optional for boolean attributes
+	attrPattern = regexp.MustCompile(`(?s)([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+	// selectClosePattern and textareaClosePattern find the end of a control's contents
+	selectClosePattern   = regexp.MustCompile(`(?i)</select\s*>`)
+	textareaClosePattern = regexp.MustCompile(`(?i)</textarea\s*>`)
+)
+
+// formField is a named form control and the values to try in it, default value first.
+type formField struct {
+	name   string
+	values []string
+}
+
+// ExtractFormLinks returns the requests generated from a page's HTML forms.
+// Each form yields up to MaxCombinations requests built from its default values,
+// the options of its <select> and radio controls, and the configured text values.
+// GET forms become links with the fields in the query string; POST forms are
+// only submitted when SubmitPost is set.
+func (c *Crawler) ExtractFormLinks(body string) []domain.Link {
+	if c.forms == nil {
+		return nil
+	}
+
+	var links []domain.Link
+	for _, match := range formPattern.FindAllStringSubmatch(body, -1) {
+		attrs := tagAttrs(match[1])
+		method := strings.ToUpper(strings.TrimSpace(attrs["method"]))
+		if method == "" {
+			method = "GET"
+		}
+		if method == "POST" {
+			// File uploads need multipart bodies, which generated requests cannot fill
+			if !c.forms.SubmitPost || strings.EqualFold(strings.TrimSpace(attrs["enctype"]), "multipart/form-data") {
+				continue
+			}
+		} else if method != "GET" {
+			continue
+		}
+
+		action := strings.TrimSpace(attrs["action"])
+		if action != "" && !c.isValidLink(action) {
+			continue
+		}
+		actionURL, err := url.Parse(action)
+		if err != nil {
+			continue
+		}
+		actionURL.Fragment = ""
+
+		fields := c.formFields(match[2])
+		if len(fields) == 0 {
+			continue
+		}
+
+		for _, query := range formCombinations(fields, c.forms.MaxCombinations) {
+			if method == "POST" {
+				links = append(links, domain.Link{URL: actionURL.String(), Method: method, Body: query})
+				continue
+			}
+			actionURL.RawQuery = query
+			links = append(links, domain.Link{URL: actionURL.String()})
+		}
+	}
+	return links
+}
+
+// formFields returns the submittable controls of a form in document order.
+// Radio buttons sharing a name are one field; controls without a usable value are skipped.
+func (c *Crawler) formFields(form string) []formField {
+	var fields []formField
+	radios := make(map[string]int) // radio group name -> index in fields
+
+	for _, match := range controlPattern.FindAllStringSubmatchIndex(form, -1) {
+		tag := strings.ToLower(form[match[2]:match[3]])
+		attrs := tagAttrs(form[match[4]:match[5]])
+		name := attrs["name"]
+		if name == "" {
+			continue
+		}
+		if _, disabled := attrs["disabled"]; disabled {
+			continue
+		}
+		value, hasValue := attrs["value"]
+		_, checked := attrs["checked"]
+		inner := form[match[1]:]
+
+		var values []string
+		switch tag {
+		case "select":
+			if end := selectClosePattern.FindStringIndex(inner); end != nil {
+				values = selectOptions(inner[:end[0]])
+			}
+		case "textarea":
+			if end := textareaClosePattern.FindStringIndex(inner); end != nil {
+				value = strings.TrimSpace(html.UnescapeString(inner[:end[0]]))
+			}
+			values = c.textValues(value)
+		default:
+			switch strings.ToLower(strings.TrimSpace(attrs["type"])) {
+			case "", "text", "search":
+				values = c.textValues(value)
+			case "hidden":
+				values = []string{value}
+			case "checkbox":
+				if checked {
+					values = []string{checkValue(value, hasValue)}
+				}
+			case "radio":
+				index, seen := radios[name]
+				if !seen {
+					radios[name] = len(fields)
+					fields = append(fields, formField{name: name})
+					index = len(fields) - 1
+				}
+				// The checked button is the default, so it is tried first
+				if checked {
+					fields[index].values = append([]string{checkValue(value, hasValue)}, fields[index].values...)
+				} else {
+					fields[index].values = append(fields[index].values, checkValue(value, hasValue))
+				}
+				continue
+			case "submit", "image", "button", "reset", "file", "password":
+				continue
+			default:
+				// Typed inputs (number, date, email, ...) are only submitted with their default value
+				if value != "" {
+					values = []string{value}
+				}
+			}
+		}
+
+		if len(values) > 0 {
+			fields = append(fields, formField{name: name, values: values})
+		}
+	}
+	return fields
+}
+
+// textValues returns the values to try in a text control: its default value, or
+// the configured text values when it has none.
+func (c *Crawler) textValues(value string) []string {
+	if value != "" {
+		return []string{value}
+	}
+	return c.forms.TextValues
+}
+
+// selectOptions returns the option values of a <select>, the selected option first.
+func selectOptions(inner string) []string {
+	var values []string
+	for _, option := range optionPattern.FindAllStringSubmatch(inner, -1) {
+		attrs := tagAttrs(option[1])
+		if _, disabled := attrs["disabled"]; disabled {
+			continue
+		}
+		value, ok := attrs["value"]
+		if !ok {
+			value = strings.Join(strings.Fields(html.UnescapeString(option[2])), " ")
+		}
+		if _, selected := attrs["selected"]; selected {
+			values = append([]string{value}, values...)
+		} else {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// checkValue returns the submitted value of a checkbox or radio button.
+func checkValue(value string, hasValue bool) string {
+	if !hasValue {
+		return "on"
+	}
+	return value
+}
+
+// formCombinations returns up to limit distinct form-encoded submissions of the
+// fields (a limit below 1 submits the form once). The defaults come first, then
+// one submission per further value of the longest field, with every field advancing
+// together, so each value is tried before the remaining combinations fill the limit.
+func formCombinations(fields []formField, limit int) []string {
+	if limit < 1 {
+		limit = 1
+	}
+
+	longest := 0
+	for _, field := range fields {
+		longest = max(longest, len(field.values))
+	}
+
+	seen := make(map[string]bool)
+	var queries []string
+	add := func(indexes func(int) int) {
+		values := url.Values{}
+		for i, field := range fields {
+			values.Add(field.name, field.values[indexes(i)])
+		}
+		if query := values.Encode(); !seen[query] {
+			seen[query] = true
+			queries = append(queries, query)
+		}
+	}
+
+	// Every value of every field, advancing the fields together
+	for step := 0; step < longest && len(queries) < limit; step++ {
+		add(func(i int) int { return step % len(fields[i].values) })
+	}
+
+	// Then the remaining combinations, the last field varying fastest
+	odometer := make([]int, len(fields))
+	for len(queries) < limit {
+		add(func(i int) int { return odometer[i] })
+		i := len(odometer) - 1
+		for ; i >= 0; i-- {
+			if odometer[i]++; odometer[i] < len(fields[i].values) {
+				break
+			}
+			odometer[i] = 0
+		}
+		if i < 0 {
+			break // every combination has been generated
+		}
+	}
+	return queries
+}
+
+// tagAttrs returns the attributes of a tag by lowercase name, with entities decoded.
+// Boolean attributes such as checked or disabled map to "".
+func tagAttrs(attrs string) map[string]string {
+	result := make(map[string]string)
+	for _, match := range attrPattern.FindAllStringSubmatch(attrs, -1) {
+		name := strings.ToLower(match[1])
+		if _, dup := result[name]; dup {
+			continue
+		}
+		result[name] = html.UnescapeString(match[2] + match[3] + match[4])
+	}
+	return result
+}
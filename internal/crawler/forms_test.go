@@ -0,0 +1,149 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestExtractFormLinks(t *testing.T) {
+	search := `<form action="/search" method="get">
+		<input type="text" name="q">
+		<select name="sort"><option value="relevance">Relevance</option><option value="date" selected>Newest</option></select>
+		<input type="hidden" name="lang" value="en">
+		<input type="submit" value="Search">
+	</form>`
+
+	tests := []struct {
+		name   string
+		body   string
+		policy domain.FormPolicy
+		want   []domain.Link
+	}{
+		{
+			name:   "GET form with defaults, options and text values",
+			body:   search,
+			policy: domain.FormPolicy{TextValues: []string{"shoes", "hats"}, MaxCombinations: 10},
+			want: []domain.Link{
+				{URL: "/search?lang=en&q=shoes&sort=date"},
+				{URL: "/search?lang=en&q=hats&sort=relevance"},
+				{URL: "/search?lang=en&q=shoes&sort=relevance"},
+				{URL: "/search?lang=en&q=hats&sort=date"},
+			},
+		},
+		{
+			name:   "combinations capped",
+			body:   search,
+			policy: domain.FormPolicy{TextValues: []string{"shoes", "hats"}, MaxCombinations: 2},
+			want: []domain.Link{
+				{URL: "/search?lang=en&q=shoes&sort=date"},
+				{URL: "/search?lang=en&q=hats&sort=relevance"},
+			},
+		},
+		{
+			name: "radios, checkboxes and disabled controls",
+			body: `<form>
+				<input type="radio" name="size" value="s"><input type="radio" name="size" value="m" checked>
+				<input type="checkbox" name="instock" checked><input type="checkbox" name="sale" value="1">
+				<input name="page" type="number" value="1"><input name="from" type="date">
+				<input name="q" value="x" disabled>
+			</form>`,
+			policy: domain.FormPolicy{MaxCombinations: 10},
+			want: []domain.Link{
+				{URL: "?instock=on&page=1&size=m"},
+				{URL: "?instock=on&page=1&size=s"},
+			},
+		},
+		{
+			name:   "POST form skipped by default",
+			body:   `<form method="post" action="/comment"><textarea name="body"></textarea></form>`,
+			policy: domain.FormPolicy{TextValues: []string{"hello"}, MaxCombinations: 10},
+		},
+		{
+			name:   "POST form submitted when opted in",
+			body:   `<form method="POST" action="/comment"><textarea name="body"></textarea></form>`,
+			policy: domain.FormPolicy{TextValues: []string{"hello"}, MaxCombinations: 10, SubmitPost: true},
+			want: []domain.Link{
+				{URL: "/comment", Method: "POST", Body: "body=hello"},
+			},
+		},
+		{
+			name:   "multipart POST form skipped",
+			body:   `<form method="post" enctype="multipart/form-data" action="/upload"><input type="file" name="f"><input name="t"></form>`,
+			policy: domain.FormPolicy{TextValues: []string{"hello"}, MaxCombinations: 10, SubmitPost: true},
+		},
+		{
+			name:   "form without fields or with a script action",
+			body:   `<form action="/go"><input type="submit"></form><form action="javascript:void(0)"><input name="q"></form>`,
+			policy: domain.FormPolicy{TextValues: []string{"a"}, MaxCombinations: 10},
+		},
+		{
+			name:   "disabled",
+			body:   search,
+			policy: domain.FormPolicy{TextValues: []string{"shoes"}, MaxCombinations: 10, Disabled: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := NewWithOptions("http://example.com", 3, Options{Forms: tt.policy})
+			links := c.ExtractFormLinks(tt.body)
+			if len(links) != len(tt.want) {
+				t.Fatalf("Expected %d links, got %+v", len(tt.want), links)
+			}
+			for i, want := range tt.want {
+				if links[i] != want {
+					t.Errorf("Link %d: expected %+v, got %+v", i, want, links[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFormCombinations_CoversEveryValue(t *testing.T) {
+	fields := []formField{
+		{name: "a", values: []string{"1", "2", "3", "4"}},
+		{name: "b", values: []string{"x", "y"}},
+	}
+
+	queries := formCombinations(fields, 4)
+	if len(queries) != 4 {
+		t.Fatalf("Expected 4 combinations, got %v", queries)
+	}
+	joined := strings.Join(queries, " ")
+	for _, value := range []string{"a=1", "a=2", "a=3", "a=4", "b=x", "b=y"} {
+		if !strings.Contains(joined, value) {
+			t.Errorf("Expected %s among the sampled combinations, got %v", value, queries)
+		}
+	}
+
+	if all := formCombinations(fields, 100); len(all) != 8 {
+		t.Errorf("Expected all 8 combinations below the cap, got %d", len(all))
+	}
+	if once := formCombinations(fields, 0); len(once) != 1 || once[0] != "a=1&b=x" {
+		t.Errorf("Expected a zero cap to submit the defaults once, got %v", once)
+	}
+}
+
+func TestAddLink_DeduplicatesFormSubmissions(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+	queue := make(chan domain.URLTask, 10)
+
+	results := []domain.AddURLResult{
+		c.AddLink(domain.Link{URL: "/comment"}, 1, queue),
+		c.AddLink(domain.Link{URL: "/comment", Method: "POST", Body: "body=a"}, 1, queue),
+		c.AddLink(domain.Link{URL: "/comment", Method: "POST", Body: "body=b"}, 1, queue),
+		c.AddLink(domain.Link{URL: "/comment", Method: "POST", Body: "body=a"}, 1, queue),
+	}
+	for i, want := range []bool{true, true, true, false} {
+		if results[i].Added != want {
+			t.Errorf("Link %d: expected Added=%v, got %+v", i, want, results[i])
+		}
+	}
+
+	<-queue
+	if task := <-queue; task.Method != "POST" || task.Body != "body=a" {
+		t.Errorf("Expected POST task with its body, got %+v", task)
+	}
+}
@@ -0,0 +1,381 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// maxSpillLineSize bounds a single encoded task in a spill file (1MB).
+const maxSpillLineSize = 1024 * 1024
+
+// spillQueue is a disk-backed overflow for the in-memory URL queue.
+// Tasks are kept in one append-only file per crawl depth and always dequeued from
+// the shallowest depth first, preserving breadth-first order across the overflow.
+//
+// A retaining queue backs checkpoints, which reference its files instead of copying
+// their tasks: drained files are kept until the next Segments call, and files with
+// unread tasks outlive Close so an interrupted crawl can be resumed from them.
+type spillQueue struct {
+	mu      sync.Mutex
+	dir     string
+	levels  map[int]*spillLevel
+	front   []domain.URLTask // Tasks popped but not delivered, returned first
+	retired []string         // Drained files a checkpoint may still reference
+	files   int              // Files created, numbering their names
+	pending int
+	retain  bool
+}
+
+// spillLevel holds the overflow file for a single crawl depth.
+type spillLevel struct {
+	path    string
+	writer  *os.File
+	buf     *bufio.Writer
+	reader  *os.File
+	scanner *bufio.Scanner
+	written int64 // Bytes appended to the file
+	read    int64 // Bytes of the tasks popped from the file
+	pending int
+}
+
+// newSpillQueue creates a spill queue in a fresh temporary directory under parentDir.
+// retain keeps files for checkpoints (see spillQueue).
+func newSpillQueue(parentDir string, retain bool) (*spillQueue, error) {
+	dir, err := os.MkdirTemp(parentDir, "lobster-frontier-")
+	if err != nil {
+		return nil, fmt.Errorf("creating frontier spill directory in %s: %w", parentDir, err)
+	}
+	return &spillQueue{
+		dir:    dir,
+		levels: make(map[int]*spillLevel),
+		retain: retain,
+	}, nil
+}
+
+// Push appends a task to the overflow file for its depth.
+func (q *spillQueue) Push(task domain.URLTask) error {
+	line, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("encoding frontier task: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	level, err := q.level(task.Depth)
+	if err != nil {
+		return err
+	}
+	if _, err := level.buf.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing frontier spill file: %w", err)
+	}
+	level.written += int64(len(line)) + 1
+	level.pending++
+	q.pending++
+	return nil
+}
+
+// Pop removes and returns the next task in breadth-first order.
+// Returns false if the queue is empty.
+func (q *spillQueue) Pop() (domain.URLTask, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if n := len(q.front); n > 0 {
+		task := q.front[n-1]
+		q.front = q.front[:n-1]
+		q.pending--
+		return task, true, nil
+	}
+
+	depths := make([]int, 0, len(q.levels))
+	for depth, level := range q.levels {
+		if level.pending > 0 {
+			depths = append(depths, depth)
+		}
+	}
+	if len(depths) == 0 {
+		return domain.URLTask{}, false, nil
+	}
+	sort.Ints(depths)
+
+	level := q.levels[depths[0]]
+	if err := level.buf.Flush(); err != nil {
+		return domain.URLTask{}, false, fmt.Errorf("flushing frontier spill file: %w", err)
+	}
+	if !level.scanner.Scan() {
+		if err := level.scanner.Err(); err != nil {
+			return domain.URLTask{}, false, fmt.Errorf("reading frontier spill file: %w", err)
+		}
+		return domain.URLTask{}, false, fmt.Errorf("frontier spill file %s ended early", level.path)
+	}
+
+	var task domain.URLTask
+	if err := json.Unmarshal(level.scanner.Bytes(), &task); err != nil {
+		return domain.URLTask{}, false, fmt.Errorf("decoding frontier task: %w", err)
+	}
+	level.read += int64(len(level.scanner.Bytes())) + 1
+	level.pending--
+	q.pending--
+
+	// Reclaim disk space once a depth is fully drained
+	if level.pending == 0 {
+		level.close()
+		if q.retain {
+			q.retired = append(q.retired, level.path)
+		} else {
+			_ = os.Remove(level.path)
+		}
+		delete(q.levels, depths[0])
+	}
+
+	return task, true, nil
+}
+
+// Unpop returns an undelivered task to the head of the queue.
+func (q *spillQueue) Unpop(task domain.URLTask) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.front = append(q.front, task)
+	q.pending++
+}
+
+// Len returns the number of tasks waiting in the overflow.
+func (q *spillQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending
+}
+
+// Discard drops every waiting task and its files, returning how many were dropped.
+func (q *spillQueue) Discard() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, level := range q.levels {
+		level.close()
+		_ = os.Remove(level.path)
+	}
+	dropped := q.pending
+	q.levels = make(map[int]*spillLevel)
+	q.front = nil
+	q.pending = 0
+	return dropped
+}
+
+// Segments returns the unread part of every spill file, shallowest depth first, and
+// the tasks popped but not delivered. Files drained since the previous call are
+// removed, as the returned segments supersede any checkpoint referencing them.
+func (q *spillQueue) Segments() ([]domain.SpillSegment, []domain.URLTask, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.removeRetired()
+
+	depths := make([]int, 0, len(q.levels))
+	for depth := range q.levels {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
+	segments := make([]domain.SpillSegment, 0, len(depths))
+	for _, depth := range depths {
+		level := q.levels[depth]
+		if err := level.buf.Flush(); err != nil {
+			return nil, nil, fmt.Errorf("flushing frontier spill file: %w", err)
+		}
+		segments = append(segments, domain.SpillSegment{
+			Path:   level.path,
+			Offset: level.read,
+			End:    level.written,
+			Count:  level.pending,
+		})
+	}
+	return segments, append([]domain.URLTask(nil), q.front...), nil
+}
+
+// Retire marks a file, such as one restored from a checkpoint, for removal on the
+// next Segments call.
+func (q *spillQueue) Retire(path string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retired = append(q.retired, path)
+}
+
+// removeRetired removes retired files, and their directory once empty if it was
+// left by an earlier run. Caller must hold q.mu.
+func (q *spillQueue) removeRetired() {
+	for _, path := range q.retired {
+		_ = os.Remove(path)
+		if dir := filepath.Dir(path); dir != q.dir {
+			_ = os.Remove(dir)
+		}
+	}
+	q.retired = nil
+}
+
+// Close releases file handles and removes the spill directory. A retaining queue
+// keeps the directory while files hold unread tasks, as a checkpoint references them.
+func (q *spillQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, level := range q.levels {
+		level.close()
+	}
+	unread := len(q.levels) > 0
+	q.levels = make(map[int]*spillLevel)
+	if q.retain && unread {
+		q.removeRetired()
+		return nil
+	}
+	return os.RemoveAll(q.dir)
+}
+
+// readSpillSegment decodes the tasks of a segment saved by Segments, calling fn for each.
+func readSpillSegment(segment domain.SpillSegment, fn func(domain.URLTask)) error {
+	file, err := os.Open(segment.Path)
+	if err != nil {
+		return fmt.Errorf("opening frontier spill file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if _, err := file.Seek(segment.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking frontier spill file %s: %w", segment.Path, err)
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(file, segment.End-segment.Offset))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSpillLineSize)
+	read := 0
+	for scanner.Scan() {
+		var task domain.URLTask
+		if err := json.Unmarshal(scanner.Bytes(), &task); err != nil {
+			return fmt.Errorf("decoding frontier task in %s: %w", segment.Path, err)
+		}
+		fn(task)
+		read++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading frontier spill file %s: %w", segment.Path, err)
+	}
+	if read != segment.Count {
+		return fmt.Errorf("frontier spill file %s holds %d of %d tasks", segment.Path, read, segment.Count)
+	}
+	return nil
+}
+
+// level returns the spill file for depth, creating it on first use.
+// Caller must hold q.mu.
+func (q *spillQueue) level(depth int) (*spillLevel, error) {
+	if level, ok := q.levels[depth]; ok {
+		return level, nil
+	}
+
+	// Numbered names keep a retired file of the same depth from being appended to
+	q.files++
+	path := filepath.Join(q.dir, fmt.Sprintf("depth-%03d-%d.ndjson", depth, q.files))
+	writer, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("creating frontier spill file: %w", err)
+	}
+	reader, err := os.Open(path) //nolint:gosec // Path is built from our own temp directory
+	if err != nil {
+		_ = writer.Close()
+		return nil, fmt.Errorf("opening frontier spill file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSpillLineSize)
+
+	level := &spillLevel{
+		path:    path,
+		writer:  writer,
+		buf:     bufio.NewWriter(writer),
+		reader:  reader,
+		scanner: scanner,
+	}
+	q.levels[depth] = level
+	return level, nil
+}
+
+func (l *spillLevel) close() {
+	_ = l.buf.Flush()
+	_ = l.writer.Close()
+	_ = l.reader.Close()
+}
+
+// DrainSpilled moves spilled URLs back into the queue while it has free capacity.
+// It returns the number of URLs moved. Must not be called concurrently with itself.
+func (c *Crawler) DrainSpilled(ctx context.Context, urlQueue chan<- domain.URLTask) int {
+	if c.spill == nil {
+		return 0
+	}
+
+	moved := 0
+	for len(urlQueue) < cap(urlQueue) {
+		task, ok, err := c.popSpilled()
+		if err != nil {
+			// A corrupt spill file cannot be recovered; count remaining URLs as dropped
+			c.droppedCnt.Add(int64(c.spill.Discard()))
+			return moved
+		}
+		if !ok {
+			return moved
+		}
+
+		select {
+		case urlQueue <- task:
+			moved++
+		case <-ctx.Done():
+			c.unpopSpilled(task)
+			return moved
+		}
+	}
+	return moved
+}
+
+// popSpilled takes the next spilled task and tracks it as pending in one step, so a
+// concurrent Snapshot finds it either in a spill file or in the frontier.
+func (c *Crawler) popSpilled() (domain.URLTask, bool, error) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	task, ok, err := c.spill.Pop()
+	if ok && c.pending != nil {
+		c.pending[requestKey(task.Method, task.URL, task.Body)] = task
+	}
+	return task, ok, err
+}
+
+// unpopSpilled returns an undelivered task to the head of the spill queue.
+func (c *Crawler) unpopSpilled(task domain.URLTask) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if c.pending != nil {
+		delete(c.pending, requestKey(task.Method, task.URL, task.Body))
+	}
+	c.spill.Unpop(task)
+}
+
+// GetSpilledCount returns the number of URLs currently waiting in the disk overflow
+func (c *Crawler) GetSpilledCount() int {
+	if c.spill == nil {
+		return 0
+	}
+	return c.spill.Len()
+}
+
+// Close releases resources held by the crawler, such as frontier spill files
+func (c *Crawler) Close() error {
+	if c.spill == nil {
+		return nil
+	}
+	return c.spill.Close()
+}
@@ -0,0 +1,177 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// spillFrontier returns the default frontier config spilling to a temporary directory.
+func spillFrontier(t *testing.T) domain.FrontierConfig {
+	frontier := domain.DefaultFrontierConfig()
+	frontier.SpillDir = t.TempDir()
+	return frontier
+}
+
+func TestAddURL_SpillsWhenQueueFull(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{Frontier: spillFrontier(t)})
+	urlQueue := make(chan domain.URLTask, 2)
+
+	for i := 0; i < 5; i++ {
+		result := c.AddURL(fmt.Sprintf("/page%d", i), 1, urlQueue)
+		if !result.Added {
+			t.Fatalf("Expected URL %d to be accepted, got reason: %s", i, result.Reason)
+		}
+	}
+
+	if got := c.GetDroppedCount(); got != 0 {
+		t.Errorf("Expected no dropped URLs with spilling enabled, got %d", got)
+	}
+	if got := c.GetSpilledCount(); got != 3 {
+		t.Errorf("Expected 3 spilled URLs, got %d", got)
+	}
+}
+
+func TestDrainSpilled_PreservesBreadthFirstOrder(t *testing.T) {
+	c := newTestCrawler(t, 5, Options{Frontier: spillFrontier(t)})
+	urlQueue := make(chan domain.URLTask, 1)
+
+	// Fill the queue, then spill deeper URLs before shallower ones
+	c.AddURL("/root", 0, urlQueue)
+	c.AddURL("/deep-a", 3, urlQueue)
+	c.AddURL("/mid-a", 2, urlQueue)
+	c.AddURL("/shallow", 1, urlQueue)
+	c.AddURL("/mid-b", 2, urlQueue)
+
+	<-urlQueue // Consume the in-memory task to make room
+
+	var depths []int
+	var urls []string
+	for c.GetSpilledCount() > 0 {
+		if moved := c.DrainSpilled(context.Background(), urlQueue); moved != 1 {
+			t.Fatalf("Expected to move 1 URL into a queue of capacity 1, moved %d", moved)
+		}
+		task := <-urlQueue
+		depths = append(depths, task.Depth)
+		urls = append(urls, task.URL)
+	}
+
+	expected := []string{
+		"http://example.com/shallow",
+		"http://example.com/mid-a",
+		"http://example.com/mid-b",
+		"http://example.com/deep-a",
+	}
+	if fmt.Sprint(urls) != fmt.Sprint(expected) {
+		t.Errorf("Expected drain order %v, got %v (depths %v)", expected, urls, depths)
+	}
+}
+
+func TestDrainSpilled_CanceledContextKeepsTask(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{Frontier: spillFrontier(t)})
+	urlQueue := make(chan domain.URLTask, 1)
+
+	c.AddURL("/a", 1, urlQueue)
+	c.AddURL("/b", 1, urlQueue)
+	<-urlQueue
+
+	// A full queue and canceled context must not lose the popped task
+	urlQueue <- domain.URLTask{URL: "blocker"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.DrainSpilled(ctx, urlQueue)
+
+	if got := c.GetSpilledCount(); got != 1 {
+		t.Errorf("Expected spilled URL to be retained, got count %d", got)
+	}
+}
+
+func TestCrawlerClose_RemovesSpillDirectory(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{Frontier: spillFrontier(t)})
+	urlQueue := make(chan domain.URLTask, 1)
+	c.AddURL("/a", 1, urlQueue)
+	c.AddURL("/b", 1, urlQueue)
+
+	dir := c.spill.dir
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected spill directory %s to be removed, stat error: %v", dir, err)
+	}
+}
+
+func TestBloomSet_NoFalseNegatives(t *testing.T) {
+	set := newBloomSet(1000, 0.01)
+
+	for i := 0; i < 1000; i++ {
+		set.Add(fmt.Sprintf("http://example.com/page%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		if set.Add(fmt.Sprintf("http://example.com/page%d", i)) {
+			t.Fatalf("Expected page%d to be reported as already visited", i)
+		}
+	}
+}
+
+func TestBloomSet_FalsePositiveRate(t *testing.T) {
+	const capacity = 10000
+	set := newBloomSet(capacity, 0.01)
+
+	for i := 0; i < capacity; i++ {
+		set.Add(fmt.Sprintf("seen-%d", i))
+	}
+
+	falsePositives := 0
+	for i := 0; i < capacity; i++ {
+		if bloomContains(set, fmt.Sprintf("unseen-%d", i)) {
+			falsePositives++
+		}
+	}
+
+	// Allow slack over the 1% target for hash variance
+	if rate := float64(falsePositives) / capacity; rate > 0.03 {
+		t.Errorf("Expected false-positive rate near 1%%, got %.2f%%", rate*100)
+	}
+}
+
+// bloomContains reports whether every bit for key is set, without inserting it.
+func bloomContains(s *bloomSet, key string) bool {
+	h1, h2 := bloomHashes(key)
+	for i := uint64(0); i < s.numHash; i++ {
+		idx := (h1 + i*h2) % s.numBits
+		if s.bits[idx/64]&(uint64(1)<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewWithOptions_BloomVisitedSet(t *testing.T) {
+	frontier := domain.DefaultFrontierConfig()
+	frontier.VisitedSet = domain.VisitedSetBloom
+	c, err := NewWithOptions("http://example.com", 3, Options{Frontier: frontier})
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	if _, ok := c.discoveredURLs.(*bloomSet); !ok {
+		t.Fatalf("Expected bloom visited set, got %T", c.discoveredURLs)
+	}
+
+	urlQueue := make(chan domain.URLTask, 10)
+	c.AddURL("/page", 1, urlQueue)
+	if result := c.AddURL("/page", 1, urlQueue); result.Reason != domain.AddURLDuplicate {
+		t.Errorf("Expected duplicate with bloom visited set, got %s", result.Reason)
+	}
+}
+
+func TestNewWithOptions_UnknownVisitedSet(t *testing.T) {
+	frontier := domain.DefaultFrontierConfig()
+	frontier.VisitedSet = "blom"
+	if _, err := NewWithOptions("http://example.com", 3, Options{Frontier: frontier}); err == nil {
+		t.Error("Expected an unknown visited set to be rejected")
+	}
+}
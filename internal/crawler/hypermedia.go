@@ -0,0 +1,196 @@
+package crawler
+
+import (
+	"encoding/json"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// maxJSONLinkDepth bounds recursion into nested JSON documents.
+const maxJSONLinkDepth = 64
+
+// followedLinkRels are the Link header relation types that lead to crawlable
+// resources. Others, such as preload or stylesheet, point at page assets.
+var followedLinkRels = map[string]bool{
+	"next":       true,
+	"prev":       true,
+	"previous":   true,
+	"first":      true,
+	"last":       true,
+	"up":         true,
+	"collection": true,
+	"item":       true,
+	"related":    true,
+}
+
+// BodyExtractor returns the extractor for a response body of the given content type:
+// domain.ExtractorHTML, domain.ExtractorJSON, or "" when the body has no links to extract.
+func BodyExtractor(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return domain.ExtractorHTML
+	case mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json"):
+		return domain.ExtractorJSON
+	case feedMediaTypes[mediaType] || mediaType == "application/xml" || mediaType == "text/xml":
+		return domain.ExtractorFeed
+	case scriptMediaTypes[mediaType]:
+		return domain.ExtractorJS
+	}
+	return ""
+}
+
+// ExtractJSONLinks returns the links in a JSON API response: HAL "_links" and
+// JSON:API "links" objects anywhere in the document, including embedded resources.
+// Link relation names become the anchor text. Templated HAL links are skipped.
+func (c *Crawler) ExtractJSONLinks(body string) []domain.Link {
+	var doc any
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return nil
+	}
+	var links []domain.Link
+	c.collectJSONLinks(doc, &links, 0)
+	return links
+}
+
+// collectJSONLinks walks a decoded JSON value, appending the links of every links object.
+func (c *Crawler) collectJSONLinks(node any, links *[]domain.Link, depth int) {
+	if depth > maxJSONLinkDepth {
+		return
+	}
+	switch value := node.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(value) {
+			if key == "_links" || key == "links" {
+				if rels, ok := value[key].(map[string]any); ok {
+					for _, rel := range sortedKeys(rels) {
+						c.appendJSONLink(rel, rels[rel], links)
+					}
+					continue
+				}
+			}
+			c.collectJSONLinks(value[key], links, depth+1)
+		}
+	case []any:
+		for _, item := range value {
+			c.collectJSONLinks(item, links, depth+1)
+		}
+	}
+}
+
+// appendJSONLink appends the link target of one relation: a URL string (JSON:API),
+// a link object with an href, or an array of link objects (HAL).
+func (c *Crawler) appendJSONLink(rel string, target any, links *[]domain.Link) {
+	if rel == "curies" {
+		return
+	}
+	var href string
+	switch value := target.(type) {
+	case string:
+		href = value
+	case map[string]any:
+		if templated, _ := value["templated"].(bool); templated {
+			return
+		}
+		href, _ = value["href"].(string)
+	case []any:
+		for _, item := range value {
+			c.appendJSONLink(rel, item, links)
+		}
+		return
+	}
+	if href = strings.TrimSpace(href); c.isValidLink(href) {
+		*links = append(*links, domain.Link{URL: href, AnchorText: rel})
+	}
+}
+
+// sortedKeys returns the keys of m in order, so extraction order is deterministic.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseLinkHeader returns the navigational links in RFC 8288 Link header values,
+// such as rel="next" pagination. The relation types become the anchor text.
+func (c *Crawler) ParseLinkHeader(values []string) []domain.Link {
+	var links []domain.Link
+	for _, value := range values {
+		for value != "" {
+			start := strings.IndexByte(value, '<')
+			if start < 0 {
+				break
+			}
+			end := strings.IndexByte(value[start:], '>')
+			if end < 0 {
+				break
+			}
+			target := strings.TrimSpace(value[start+1 : start+end])
+			params, rest := splitLinkValue(value[start+end+1:])
+			value = rest
+
+			rel := linkParam(params, "rel")
+			if !followsRel(rel) || !c.isValidLink(target) {
+				continue
+			}
+			links = append(links, domain.Link{URL: target, AnchorText: strings.Join(strings.Fields(rel), " ")})
+		}
+	}
+	return links
+}
+
+// splitLinkValue splits the parameters of one link-value from the rest of a
+// Link header at the next comma outside a quoted string.
+func splitLinkValue(s string) (params, rest string) {
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case '\\':
+			if inQuotes {
+				i++
+			}
+		case ',':
+			if !inQuotes {
+				return s[:i], s[i+1:]
+			}
+		}
+	}
+	return s, ""
+}
+
+// linkParam returns the value of a link parameter such as rel, unquoted.
+func linkParam(params, name string) string {
+	for _, param := range strings.Split(params, ";") {
+		key, value, found := strings.Cut(param, "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), name) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		return value
+	}
+	return ""
+}
+
+// followsRel reports whether a space-separated rel list includes a followed relation type.
+func followsRel(rel string) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		if followedLinkRels[r] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestBodyExtractor(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"text/html; charset=utf-8", domain.ExtractorHTML},
+		{"application/xhtml+xml", domain.ExtractorHTML},
+		{"application/json", domain.ExtractorJSON},
+		{"application/hal+json", domain.ExtractorJSON},
+		{"application/vnd.api+json", domain.ExtractorJSON},
+		{"Application/JSON; charset=utf-8", domain.ExtractorJSON},
+		{"application/rss+xml", domain.ExtractorFeed},
+		{"application/atom+xml; charset=utf-8", domain.ExtractorFeed},
+		{"text/xml", domain.ExtractorFeed},
+		{"text/javascript; charset=utf-8", domain.ExtractorJS},
+		{"application/javascript", domain.ExtractorJS},
+		{"image/png", ""},
+		{"text/plain", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := BodyExtractor(tt.contentType); got != tt.want {
+			t.Errorf("BodyExtractor(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestExtractJSONLinks(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	tests := []struct {
+		name string
+		body string
+		want []domain.Link
+	}{
+		{
+			name: "HAL",
+			body: `{
+				"_links": {
+					"self": {"href": "/orders"},
+					"next": {"href": "/orders?page=2"},
+					"find": {"href": "/orders{?id}", "templated": true},
+					"curies": [{"name": "ea", "href": "/docs/rels/{rel}", "templated": true}],
+					"ea:admin": [{"href": "/admins/2"}, {"href": "/admins/5"}]
+				},
+				"_embedded": {
+					"orders": [{"_links": {"self": {"href": "/orders/123"}}, "total": 30}]
+				}
+			}`,
+			want: []domain.Link{
+				{URL: "/orders/123", AnchorText: "self"},
+				{URL: "/admins/2", AnchorText: "ea:admin"},
+				{URL: "/admins/5", AnchorText: "ea:admin"},
+				{URL: "/orders?page=2", AnchorText: "next"},
+				{URL: "/orders", AnchorText: "self"},
+			},
+		},
+		{
+			name: "JSON:API",
+			body: `{
+				"links": {"self": "http://example.com/articles", "next": "http://example.com/articles?page[offset]=2", "prev": null},
+				"data": [{
+					"type": "articles",
+					"id": "1",
+					"relationships": {
+						"author": {"links": {"related": {"href": "http://example.com/articles/1/author"}}}
+					},
+					"links": {"self": "http://example.com/articles/1"}
+				}]
+			}`,
+			want: []domain.Link{
+				{URL: "http://example.com/articles/1", AnchorText: "self"},
+				{URL: "http://example.com/articles/1/author", AnchorText: "related"},
+				{URL: "http://example.com/articles?page[offset]=2", AnchorText: "next"},
+				{URL: "http://example.com/articles", AnchorText: "self"},
+			},
+		},
+		{
+			name: "plain JSON",
+			body: `{"links": ["not", "a", "links", "object"], "url": "/ignored"}`,
+		},
+		{
+			name: "invalid JSON",
+			body: `{"_links": {"next": {"href": "/trunc`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := c.ExtractJSONLinks(tt.body)
+			if len(links) != len(tt.want) {
+				t.Fatalf("Expected %d links, got %+v", len(tt.want), links)
+			}
+			for i, want := range tt.want {
+				if links[i] != want {
+					t.Errorf("Link %d: expected %+v, got %+v", i, want, links[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	values := []string{
+		`<https://api.example.com/items?page=2>; rel="next", <https://api.example.com/items?page=9>; rel="last"`,
+		`</items?page=1>; rel="first prev"; title="Back, to start"`,
+		`</style.css>; rel=preload; as=style, </>; rel="canonical"`,
+		`<mailto:help@example.com>; rel="related"`,
+		`malformed; rel=next`,
+	}
+
+	links := c.ParseLinkHeader(values)
+
+	expected := []domain.Link{
+		{URL: "https://api.example.com/items?page=2", AnchorText: "next"},
+		{URL: "https://api.example.com/items?page=9", AnchorText: "last"},
+		{URL: "/items?page=1", AnchorText: "first prev"},
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("Expected %d links, got %+v", len(expected), links)
+	}
+	for i, want := range expected {
+		if links[i] != want {
+			t.Errorf("Link %d: expected %+v, got %+v", i, want, links[i])
+		}
+	}
+}
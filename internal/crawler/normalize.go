@@ -0,0 +1,222 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// DefaultTrackingParams lists query parameters dropped when tracking stripping is enabled.
+// Entries ending in "*" match any parameter with that prefix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid", "gclid", "dclid", "msclkid", "yclid",
+	"mc_cid", "mc_eid",
+	"_ga", "_gl",
+	"igshid",
+}
+
+var (
+	// linkTagPattern matches <link ...> tags for canonical extraction
+	linkTagPattern = regexp.MustCompile(`(?i)<link\s[^>]*>`)
+	// relCanonicalPattern matches rel="canonical" (rel may list several tokens)
+	relCanonicalPattern = regexp.MustCompile(`(?i)\brel\s*=\s*["']?(?:[^"'>]*\s)?canonical(?:\s[^"'>]*)?["']?`)
+	// hrefAttrPattern captures the href attribute value of a tag
+	hrefAttrPattern = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']+)["']`)
+)
+
+// Normalizer canonicalizes URLs so equivalent forms share one deduplication key.
+type Normalizer struct {
+	config         domain.URLNormalization
+	trackingExact  map[string]bool
+	trackingPrefix []string
+}
+
+// NewNormalizer creates a normalizer for the given configuration.
+// Configured tracking parameters are added to DefaultTrackingParams.
+func NewNormalizer(config domain.URLNormalization) *Normalizer {
+	n := &Normalizer{
+		config:        config,
+		trackingExact: make(map[string]bool),
+	}
+
+	params := make([]string, 0, len(DefaultTrackingParams)+len(config.TrackingParams))
+	params = append(params, DefaultTrackingParams...)
+	params = append(params, config.TrackingParams...)
+	for _, param := range params {
+		param = strings.ToLower(strings.TrimSpace(param))
+		if param == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			n.trackingPrefix = append(n.trackingPrefix, prefix)
+		} else {
+			n.trackingExact[param] = true
+		}
+	}
+
+	return n
+}
+
+// Normalize rewrites u in place to its canonical form and returns the resulting string.
+// The fragment is always removed and an empty path on an absolute URL becomes "/".
+func (n *Normalizer) Normalize(u *url.URL) string {
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if n.config.LowercaseHost {
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+	}
+
+	if n.config.StripDefaultPort {
+		u.Host = stripDefaultPort(u.Scheme, u.Host)
+	}
+
+	if u.Host != "" && u.Path == "" {
+		u.Path = "/"
+		u.RawPath = ""
+	}
+
+	if u.RawQuery != "" && (n.config.StripTracking || n.config.SortQuery) {
+		u.RawQuery = n.normalizeQuery(u.RawQuery)
+		if u.RawQuery == "" {
+			u.ForceQuery = false
+		}
+	}
+
+	switch n.config.TrailingSlash {
+	case domain.TrailingSlashStrip:
+		if u.Path != "/" && strings.HasSuffix(u.Path, "/") {
+			u.Path = strings.TrimSuffix(u.Path, "/")
+			u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+		}
+	case domain.TrailingSlashAdd:
+		if !strings.HasSuffix(u.Path, "/") && !hasFileExtension(u.Path) {
+			u.Path += "/"
+			if u.RawPath != "" {
+				u.RawPath += "/"
+			}
+		}
+	}
+
+	return u.String()
+}
+
+// normalizeQuery drops tracking parameters and sorts the remaining ones by name.
+// Parameters are handled as raw "key=value" pairs so their original encoding is kept.
+func (n *Normalizer) normalizeQuery(rawQuery string) string {
+	pairs := strings.Split(rawQuery, "&")
+	kept := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair == "" {
+			continue
+		}
+		if n.config.StripTracking && n.isTrackingParam(queryKey(pair)) {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+
+	if n.config.SortQuery {
+		// Stable sort keeps the order of repeated keys, which can be significant
+		sort.SliceStable(kept, func(i, j int) bool {
+			return queryKey(kept[i]) < queryKey(kept[j])
+		})
+	}
+
+	return strings.Join(kept, "&")
+}
+
+// isTrackingParam reports whether a query parameter name is a known tracking parameter.
+func (n *Normalizer) isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if n.trackingExact[key] {
+		return true
+	}
+	for _, prefix := range n.trackingPrefix {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryKey returns the decoded parameter name of a raw "key=value" pair.
+func queryKey(pair string) string {
+	key, _, _ := strings.Cut(pair, "=")
+	if decoded, err := url.QueryUnescape(key); err == nil {
+		return decoded
+	}
+	return key
+}
+
+// stripDefaultPort removes the port from host when it is the scheme's default.
+func stripDefaultPort(scheme, host string) string {
+	switch {
+	case strings.EqualFold(scheme, "http") && strings.HasSuffix(host, ":80"):
+		return strings.TrimSuffix(host, ":80")
+	case strings.EqualFold(scheme, "https") && strings.HasSuffix(host, ":443"):
+		return strings.TrimSuffix(host, ":443")
+	case strings.HasSuffix(host, ":"):
+		// Empty port ("host:") is equivalent to no port
+		return strings.TrimSuffix(host, ":")
+	}
+	return host
+}
+
+// hasFileExtension reports whether the last path segment looks like a file name.
+func hasFileExtension(path string) bool {
+	segment := path[strings.LastIndex(path, "/")+1:]
+	return strings.Contains(segment, ".")
+}
+
+// ExtractCanonical returns the href of the first <link rel="canonical"> tag in body.
+// Returns an empty string if the page declares no canonical URL.
+func (c *Crawler) ExtractCanonical(body string) string {
+	for _, tag := range linkTagPattern.FindAllString(body, -1) {
+		if !relCanonicalPattern.MatchString(tag) {
+			continue
+		}
+		if match := hrefAttrPattern.FindStringSubmatch(tag); len(match) > 1 {
+			return strings.TrimSpace(match[1])
+		}
+	}
+	return ""
+}
+
+// RegisterCanonical records that pageURL declared canonicalURL as its canonical form.
+// The canonical URL is marked as discovered so it is not fetched again, since its content
+// has just been retrieved. Returns true if the canonical URL had already been discovered,
+// in which case the page is a duplicate and its links need not be followed.
+func (c *Crawler) RegisterCanonical(pageURL, canonicalURL string) bool {
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	canonical, err := url.Parse(canonicalURL)
+	if err != nil {
+		return false
+	}
+	canonical = page.ResolveReference(canonical)
+
+	// Normalize rewrites both URLs in place; the base URL was normalized when the
+	// crawler was created, so hosts are compared in their normalized form
+	pageKey := c.normalizer.Normalize(page)
+	canonicalKey := c.normalizer.Normalize(canonical)
+
+	// Self-referencing canonicals and off-site canonicals are ignored
+	if canonicalKey == pageKey || canonical.Host != c.baseURL.Host {
+		return false
+	}
+
+	if !c.discoveredURLs.Add(canonicalKey) {
+		c.canonicalDupCnt.Add(1)
+		return true
+	}
+	c.discoveredCnt.Add(1)
+	return false
+}
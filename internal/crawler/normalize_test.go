@@ -0,0 +1,188 @@
+package crawler
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	defaults := domain.DefaultURLNormalization()
+
+	withSlash := func(policy string) domain.URLNormalization {
+		cfg := defaults
+		cfg.TrailingSlash = policy
+		return cfg
+	}
+
+	tests := []struct {
+		name     string
+		config   domain.URLNormalization
+		input    string
+		expected string
+	}{
+		{"lowercase scheme and host", defaults, "HTTP://Example.COM/Path", "http://example.com/Path"},
+		{"strip http default port", defaults, "http://example.com:80/a", "http://example.com/a"},
+		{"strip https default port", defaults, "https://example.com:443/a", "https://example.com/a"},
+		{"keep non-default port", defaults, "http://example.com:8080/a", "http://example.com:8080/a"},
+		{"empty path becomes root", defaults, "http://example.com", "http://example.com/"},
+		{"drop fragment", defaults, "http://example.com/a#top", "http://example.com/a"},
+		{"sort query", defaults, "http://example.com/a?y=2&x=1", "http://example.com/a?x=1&y=2"},
+		{"keep repeated key order", defaults, "http://example.com/a?b=2&a=1&b=1", "http://example.com/a?a=1&b=2&b=1"},
+		{"drop utm params", defaults, "http://example.com/a?utm_source=x&id=5&UTM_Medium=y", "http://example.com/a?id=5"},
+		{"drop fbclid", defaults, "http://example.com/a?fbclid=abc", "http://example.com/a"},
+		{"keep query encoding", defaults, "http://example.com/a?q=a%20b", "http://example.com/a?q=a%20b"},
+		{"trailing slash keep", withSlash(domain.TrailingSlashKeep), "http://example.com/page/", "http://example.com/page/"},
+		{"trailing slash strip", withSlash(domain.TrailingSlashStrip), "http://example.com/page/", "http://example.com/page"},
+		{"trailing slash strip keeps root", withSlash(domain.TrailingSlashStrip), "http://example.com/", "http://example.com/"},
+		{"trailing slash add", withSlash(domain.TrailingSlashAdd), "http://example.com/page", "http://example.com/page/"},
+		{"trailing slash add skips files", withSlash(domain.TrailingSlashAdd), "http://example.com/style.css", "http://example.com/style.css"},
+		{"disabled leaves host case", domain.URLNormalization{}, "http://Example.com:80/a?y=1&x=2", "http://Example.com:80/a?y=1&x=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("url.Parse(%q) error = %v", tt.input, err)
+			}
+			got := NewNormalizer(tt.config).Normalize(u)
+			if got != tt.expected {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizer_CustomTrackingParams(t *testing.T) {
+	cfg := domain.DefaultURLNormalization()
+	cfg.TrackingParams = []string{"ref", "session_*"}
+	n := NewNormalizer(cfg)
+
+	u, _ := url.Parse("http://example.com/a?ref=home&session_id=1&page=2")
+	if got := n.Normalize(u); got != "http://example.com/a?page=2" {
+		t.Errorf("Expected custom tracking params dropped, got %q", got)
+	}
+}
+
+func TestAddURL_NormalizationDeduplication(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+	urlQueue := make(chan domain.URLTask, 10)
+
+	c.AddURL("/a?x=1&y=2", 1, urlQueue)
+	variants := []string{
+		"/a?y=2&x=1",
+		"HTTP://EXAMPLE.COM:80/a?x=1&y=2",
+		"/a?x=1&y=2&utm_source=newsletter",
+	}
+	for _, variant := range variants {
+		result := c.AddURL(variant, 1, urlQueue)
+		if result.Added || result.Reason != domain.AddURLDuplicate {
+			t.Errorf("Expected %q to be a duplicate, got %+v", variant, result)
+		}
+	}
+
+	// An exact repeat is a duplicate but not attributable to normalization
+	c.AddURL("/a?x=1&y=2", 1, urlQueue)
+
+	if len(urlQueue) != 1 {
+		t.Errorf("Expected 1 URL in queue, got %d", len(urlQueue))
+	}
+	if got := c.GetNormalizedDuplicateCount(); got != len(variants) {
+		t.Errorf("Expected %d normalized duplicates, got %d", len(variants), got)
+	}
+}
+
+func TestAddURL_TrailingSlashPolicy(t *testing.T) {
+	cfg := domain.DefaultURLNormalization()
+	cfg.TrailingSlash = domain.TrailingSlashStrip
+	c, _ := NewWithOptions("http://example.com", 3, Options{Normalization: cfg})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	c.AddURL("/page/", 1, urlQueue)
+	if result := c.AddURL("/page", 1, urlQueue); result.Added {
+		t.Error("Expected /page to deduplicate against /page/ when stripping trailing slashes")
+	}
+
+	task := <-urlQueue
+	if task.URL != "http://example.com/page" {
+		t.Errorf("Expected queued URL 'http://example.com/page', got '%s'", task.URL)
+	}
+}
+
+func TestExtractCanonical(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"double quotes", `<head><link rel="canonical" href="/products/1"></head>`, "/products/1"},
+		{"href first", `<link href='http://example.com/x' rel='canonical' />`, "http://example.com/x"},
+		{"case insensitive", `<LINK REL="Canonical" HREF="/y">`, "/y"},
+		{"ignores other rels", `<link rel="stylesheet" href="/style.css">`, ""},
+		{"no canonical", `<html><body>hi</body></html>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ExtractCanonical(tt.body); got != tt.expected {
+				t.Errorf("ExtractCanonical() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterCanonical(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+	urlQueue := make(chan domain.URLTask, 10)
+
+	c.AddURL("/product?id=1", 1, urlQueue)
+
+	// Self-referencing canonical is not a duplicate
+	if c.RegisterCanonical("http://example.com/product?id=1", "/product?id=1") {
+		t.Error("Expected self-referencing canonical not to be a duplicate")
+	}
+
+	// A variant page pointing at an already-discovered canonical is a duplicate
+	if !c.RegisterCanonical("http://example.com/product?id=1&color=red", "/product?id=1") {
+		t.Error("Expected page with discovered canonical to be a duplicate")
+	}
+
+	// A new canonical is registered and suppresses later discovery of that URL
+	if c.RegisterCanonical("http://example.com/item-2", "/product?id=2") {
+		t.Error("Expected first sighting of canonical not to be a duplicate")
+	}
+	if result := c.AddURL("/product?id=2", 1, urlQueue); result.Added {
+		t.Error("Expected registered canonical URL not to be queued again")
+	}
+
+	// Off-site canonicals are ignored
+	if c.RegisterCanonical("http://example.com/z", "http://other.com/z") {
+		t.Error("Expected off-site canonical to be ignored")
+	}
+
+	if got := c.GetCanonicalDuplicateCount(); got != 1 {
+		t.Errorf("Expected 1 canonical duplicate, got %d", got)
+	}
+	// The registered canonical counts as discovered, like a queued URL
+	if got := c.GetDiscoveredCount(); got != 2 {
+		t.Errorf("Expected 2 discovered URLs, got %d", got)
+	}
+}
+
+func TestRegisterCanonical_NormalizesHosts(t *testing.T) {
+	for _, baseURL := range []string{"https://example.com", "HTTPS://Example.COM:443"} {
+		c, _ := New(baseURL, 3)
+
+		// Differently written forms of the base host are on-site
+		if c.RegisterCanonical("https://example.com/a", "https://Example.com:443/x") {
+			t.Errorf("Expected first sighting of canonical not to be a duplicate (base %s)", baseURL)
+		}
+		if !c.RegisterCanonical("https://example.com/b", "https://example.com/x") {
+			t.Errorf("Expected a canonical on a differently written base host to be registered (base %s)", baseURL)
+		}
+	}
+}
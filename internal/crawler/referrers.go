@@ -0,0 +1,63 @@
+package crawler
+
+import (
+	"sync"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// maxReferrersPerURL caps the referring pages kept per URL; further links are only counted.
+const maxReferrersPerURL = 20
+
+// referrerIndex records which pages link to each discovered URL.
+type referrerIndex struct {
+	mu sync.Mutex
+	m  map[string]*domain.ReferrerList
+}
+
+func newReferrerIndex() *referrerIndex {
+	return &referrerIndex{m: make(map[string]*domain.ReferrerList)}
+}
+
+// record notes that ref links to target. A page linking to the same target
+// several times is listed once.
+func (r *referrerIndex) record(target string, ref domain.Referrer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list, ok := r.m[target]
+	if !ok {
+		list = &domain.ReferrerList{}
+		r.m[target] = list
+	}
+	list.Links++
+
+	if len(list.Referrers) >= maxReferrersPerURL {
+		return
+	}
+	for _, existing := range list.Referrers {
+		if existing.SourceURL == ref.SourceURL {
+			return
+		}
+	}
+	list.Referrers = append(list.Referrers, ref)
+}
+
+// GetReferrers returns the pages linking to a discovered URL, identified by the
+// URL of its task. Returns an empty list unless the crawler tracks referrers.
+func (c *Crawler) GetReferrers(taskURL string) domain.ReferrerList {
+	if c.referrers == nil {
+		return domain.ReferrerList{}
+	}
+	c.referrers.mu.Lock()
+	defer c.referrers.mu.Unlock()
+
+	list, ok := c.referrers.m[taskURL]
+	if !ok {
+		return domain.ReferrerList{}
+	}
+	return domain.ReferrerList{
+		Referrers: append([]domain.Referrer(nil), list.Referrers...),
+		Links:     list.Links,
+	}
+}
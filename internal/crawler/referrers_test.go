@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestGetReferrers_RecordsDuplicates(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{TrackFrontier: true, TrackReferrers: true})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	c.AddLink(domain.Link{URL: "/target", SourceURL: "http://example.com/a", AnchorText: "First"}, 1, urlQueue)
+	c.AddLink(domain.Link{URL: "/target#top", SourceURL: "http://example.com/b"}, 1, urlQueue)
+	c.AddLink(domain.Link{URL: "/target", SourceURL: "http://example.com/a", AnchorText: "Again"}, 1, urlQueue)
+
+	list := c.GetReferrers("http://example.com/target")
+	if list.Links != 3 {
+		t.Errorf("Expected 3 links counted, got %d", list.Links)
+	}
+	expected := []domain.Referrer{
+		{SourceURL: "http://example.com/a", AnchorText: "First"},
+		{SourceURL: "http://example.com/b"},
+	}
+	if len(list.Referrers) != len(expected) {
+		t.Fatalf("Expected referrers %+v, got %+v", expected, list.Referrers)
+	}
+	for i, want := range expected {
+		if list.Referrers[i] != want {
+			t.Errorf("Referrer %d: expected %+v, got %+v", i, want, list.Referrers[i])
+		}
+	}
+}
+
+func TestGetReferrers_Capped(t *testing.T) {
+	c := newTestCrawler(t, 3, Options{TrackFrontier: true, TrackReferrers: true})
+	urlQueue := make(chan domain.URLTask, 10)
+
+	for i := 0; i < maxReferrersPerURL+5; i++ {
+		c.AddLink(domain.Link{URL: "/popular", SourceURL: fmt.Sprintf("http://example.com/p%d", i)}, 1, urlQueue)
+	}
+
+	list := c.GetReferrers("http://example.com/popular")
+	if len(list.Referrers) != maxReferrersPerURL {
+		t.Errorf("Expected %d referrers kept, got %d", maxReferrersPerURL, len(list.Referrers))
+	}
+	if list.Links != maxReferrersPerURL+5 {
+		t.Errorf("Expected %d links counted, got %d", maxReferrersPerURL+5, list.Links)
+	}
+}
+
+func TestGetReferrers_NotTracked(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+	urlQueue := make(chan domain.URLTask, 10)
+	c.AddLink(domain.Link{URL: "/target", SourceURL: "http://example.com/"}, 1, urlQueue)
+
+	if list := c.GetReferrers("http://example.com/target"); list.Links != 0 || len(list.Referrers) != 0 {
+		t.Errorf("Expected no referrers without tracking, got %+v", list)
+	}
+}
+
+func TestRestore_Referrers(t *testing.T) {
+	original := newTestCrawler(t, 3, Options{TrackFrontier: true, TrackReferrers: true})
+	urlQueue := make(chan domain.URLTask, 10)
+	original.AddLink(domain.Link{URL: "/next", SourceURL: "http://example.com/", AnchorText: "Next"}, 1, urlQueue)
+	state := snapshot(t, original)
+
+	if len(state.Frontier) != 1 || state.Frontier[0].SourceURL != "http://example.com/" || state.Frontier[0].AnchorText != "Next" {
+		t.Fatalf("Expected frontier task to keep its source, got %+v", state.Frontier)
+	}
+
+	resumed := newTestCrawler(t, 3, Options{TrackFrontier: true, TrackReferrers: true})
+	if err := resumed.Restore(state, make(chan domain.URLTask, 10)); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if list := resumed.GetReferrers("http://example.com/next"); list.Links != 1 || list.Referrers[0].AnchorText != "Next" {
+		t.Errorf("Expected restored referrer, got %+v", list)
+	}
+}
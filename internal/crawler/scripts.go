@@ -0,0 +1,104 @@
+package crawler
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+var (
+	// scriptSrcPattern captures the src attribute of a <script> tag
+	scriptSrcPattern = regexp.MustCompile(`(?is)<script\b[^>]*?\bsrc\s*=\s*["']([^"']+)["']`)
+	// callSitePattern captures the URL argument of fetch, axios, jQuery and XMLHttpRequest.open calls
+	callSitePattern = regexp.MustCompile(`(?:\bfetch|\baxios(?:\.(?:get|post|put|patch|delete|head|options|request))?|\$\.(?:get|post|getJSON|ajax)|\.open)\s*\(\s*(?:["'](?i:get|post|put|patch|delete|head|options)["']\s*,\s*)?["'` + "`" + `]([^"'` + "`" + `\s]+)["'` + "`" + `]`)
+	// pathLiteralPattern captures string literals holding an absolute path or http(s) URL
+	pathLiteralPattern = regexp.MustCompile(`["'` + "`" + `]((?:https?:)?/[^"'` + "`" + `\s<>{}\\|^*()\[\],;]*)["'` + "`" + `]`)
+	// apiPathPattern matches paths that look like API endpoints
+	apiPathPattern = regexp.MustCompile(`(?i)(?:^|/)(?:api|graphql|rest|v\d+)(?:[/?#]|$)|\.json(?:[?#]|$)`)
+	// assetPathPattern matches paths of static assets, which are not endpoints
+	assetPathPattern = regexp.MustCompile(`(?i)\.(?:m?js|css|map|png|jpe?g|gif|svg|ico|webp|avif|woff2?|ttf|otf|eot|mp[34]|webm|pdf)(?:[?#]|$)`)
+)
+
+// scriptMediaTypes are the media types JavaScript files are served with.
+var scriptMediaTypes = map[string]bool{
+	"application/javascript":   true,
+	"text/javascript":          true,
+	"application/x-javascript": true,
+	"application/ecmascript":   true,
+	"text/ecmascript":          true,
+}
+
+// confidenceRank orders confidence levels so the strongest evidence for a URL wins.
+var confidenceRank = map[string]int{
+	domain.ConfidenceLow:    1,
+	domain.ConfidenceMedium: 2,
+	domain.ConfidenceHigh:   3,
+}
+
+// ExtractScriptLinks returns the <script src> URLs of an HTML page, so their
+// files can be scanned for endpoints.
+func (c *Crawler) ExtractScriptLinks(body string) []domain.Link {
+	var links []domain.Link
+	for _, match := range scriptSrcPattern.FindAllStringSubmatch(body, -1) {
+		link := html.UnescapeString(strings.TrimSpace(match[1]))
+		if c.isValidLink(link) {
+			links = append(links, domain.Link{URL: link})
+		}
+	}
+	return links
+}
+
+// ExtractJSEndpoints returns API endpoint candidates found in a JavaScript file by a
+// static scan: URL arguments of fetch, axios, jQuery and XMLHttpRequest calls (high
+// confidence), and string literals holding a path, ranked by how much they look like
+// an API (medium or low). Only absolute paths and http(s) URLs are returned, since
+// relative paths resolve against the page running the script, which is unknown.
+// Template literals with substitutions and static assets are skipped.
+func (c *Crawler) ExtractJSEndpoints(body string) []domain.Link {
+	var links []domain.Link
+	seen := make(map[string]int) // URL -> index in links
+
+	add := func(candidate, confidence string) {
+		if strings.HasPrefix(candidate, "//") || strings.Contains(candidate, "${") || !c.isValidLink(candidate) {
+			return
+		}
+		if index, ok := seen[candidate]; ok {
+			if confidenceRank[confidence] > confidenceRank[links[index].Confidence] {
+				links[index].Confidence = confidence
+			}
+			return
+		}
+		seen[candidate] = len(links)
+		links = append(links, domain.Link{URL: candidate, Confidence: confidence})
+	}
+
+	for _, match := range callSitePattern.FindAllStringSubmatch(body, -1) {
+		if candidate := match[1]; strings.HasPrefix(candidate, "/") || isHTTPURL(candidate) {
+			add(candidate, domain.ConfidenceHigh)
+		}
+	}
+
+	for _, match := range pathLiteralPattern.FindAllStringSubmatch(body, -1) {
+		candidate := match[1]
+		if candidate == "/" || assetPathPattern.MatchString(candidate) {
+			continue
+		}
+		if strings.HasPrefix(candidate, "http") && !isHTTPURL(candidate) {
+			continue
+		}
+		if apiPathPattern.MatchString(candidate) {
+			add(candidate, domain.ConfidenceMedium)
+		} else {
+			add(candidate, domain.ConfidenceLow)
+		}
+	}
+
+	return links
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL.
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,90 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestExtractScriptLinks(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	body := `<head>
+		<script src="/static/app.js?v=1&amp;x=2" defer></script>
+		<script type="module" src='https://cdn.example.net/lib.js'></script>
+		<script>var inline = "/api/inline";</script>
+	</head>`
+
+	want := []domain.Link{
+		{URL: "/static/app.js?v=1&x=2"},
+		{URL: "https://cdn.example.net/lib.js"},
+	}
+	links := c.ExtractScriptLinks(body)
+	if len(links) != len(want) {
+		t.Fatalf("Expected %d links, got %+v", len(want), links)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("Link %d: expected %+v, got %+v", i, want[i], links[i])
+		}
+	}
+}
+
+func TestExtractJSEndpoints(t *testing.T) {
+	c, _ := New("http://example.com", 3)
+
+	tests := []struct {
+		name string
+		body string
+		want []domain.Link
+	}{
+		{
+			name: "call sites",
+			body: `fetch("/api/users").then(r=>r.json());
+				axios.get('/orders?status=open');
+				$.getJSON("/data/stats");
+				xhr.open("POST", "/session/ping", true);
+				fetch(` + "`/api/users/${id}`" + `);
+				fetch("relative/path");`,
+			want: []domain.Link{
+				{URL: "/api/users", Confidence: domain.ConfidenceHigh},
+				{URL: "/orders?status=open", Confidence: domain.ConfidenceHigh},
+				{URL: "/data/stats", Confidence: domain.ConfidenceHigh},
+				{URL: "/session/ping", Confidence: domain.ConfidenceHigh},
+			},
+		},
+		{
+			name: "path literals",
+			body: `var routes={list:"/v2/items",cfg:"/config.json",about:"/about",root:"/",
+				logo:"/img/logo.svg",cdn:"//cdn.example.com/x",re:"/\\s+/",
+				home:"https://example.com/account"};`,
+			want: []domain.Link{
+				{URL: "/v2/items", Confidence: domain.ConfidenceMedium},
+				{URL: "/config.json", Confidence: domain.ConfidenceMedium},
+				{URL: "/about", Confidence: domain.ConfidenceLow},
+				{URL: "https://example.com/account", Confidence: domain.ConfidenceLow},
+			},
+		},
+		{
+			name: "strongest evidence wins",
+			body: `const USERS = "/api/users"; fetch("/api/users");`,
+			want: []domain.Link{
+				{URL: "/api/users", Confidence: domain.ConfidenceHigh},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := c.ExtractJSEndpoints(tt.body)
+			if len(links) != len(tt.want) {
+				t.Fatalf("Expected %d links, got %+v", len(tt.want), links)
+			}
+			for i, want := range tt.want {
+				if links[i] != want {
+					t.Errorf("Link %d: expected %+v, got %+v", i, want, links[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// DefaultSessionParams lists query parameters treated as session IDs.
+// Configured session parameters are added to these.
+var DefaultSessionParams = []string{
+	"jsessionid", "phpsessid", "aspsessionid",
+	"sessionid", "session_id", "sessid", "sid",
+	"cfid", "cftoken",
+}
+
+// maxTrapExamples is the number of example URLs kept per trap.
+const maxTrapExamples = 5
+
+// digitRunPattern matches runs of digits, which path shapes replace with {n}
+var digitRunPattern = regexp.MustCompile(`[0-9]+`)
+
+// datePattern matches YYYY-MM and YYYY-MM-DD dates not embedded in longer numbers
+var datePattern = regexp.MustCompile(`(?:^|[^0-9])([0-9]{4})-(?:0[1-9]|1[0-2])(?:-(?:0[1-9]|[12][0-9]|3[01]))?(?:[^0-9]|$)`)
+
+// trapDetector suppresses URLs from spaces that generate endless distinct URLs.
+type trapDetector struct {
+	policy        domain.TrapPolicy
+	sessionParams map[string]bool
+	now           func() time.Time
+
+	mu       sync.Mutex
+	variants map[string]int             // host+path -> query-string variants queued
+	combos   map[string]map[string]bool // host+path -> parameter name sets queued
+	reports  map[string]*domain.CrawlTrap
+}
+
+func newTrapDetector(policy domain.TrapPolicy) *trapDetector {
+	d := &trapDetector{
+		policy:        policy,
+		sessionParams: make(map[string]bool),
+		now:           time.Now,
+		variants:      make(map[string]int),
+		combos:        make(map[string]map[string]bool),
+		reports:       make(map[string]*domain.CrawlTrap),
+	}
+	for _, param := range append(append([]string(nil), DefaultSessionParams...), policy.SessionParams...) {
+		if param = strings.ToLower(strings.TrimSpace(param)); param != "" {
+			d.sessionParams[param] = true
+		}
+	}
+	return d
+}
+
+// stripSessionIDs removes session-ID query parameters and ;jsessionid= path
+// parameters from u. Returns the name of a removed parameter, or "" if none was found.
+func (d *trapDetector) stripSessionIDs(u *url.URL) string {
+	removed := ""
+
+	if i := strings.Index(strings.ToLower(u.Path), ";jsessionid="); i >= 0 {
+		rest := u.Path[i+1:]
+		if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+			u.Path = u.Path[:i] + rest[slash:]
+		} else {
+			u.Path = u.Path[:i]
+		}
+		u.RawPath = ""
+		removed = "jsessionid"
+	}
+
+	if u.RawQuery != "" {
+		pairs := strings.Split(u.RawQuery, "&")
+		kept := pairs[:0]
+		for _, pair := range pairs {
+			if key := strings.ToLower(queryKey(pair)); d.sessionParams[key] {
+				removed = key
+				continue
+			}
+			kept = append(kept, pair)
+		}
+		u.RawQuery = strings.Join(kept, "&")
+		if u.RawQuery == "" {
+			u.ForceQuery = false
+		}
+	}
+
+	return removed
+}
+
+// check reports whether a newly discovered URL falls into a trap, returning the
+// reason and pattern, or empty strings. URLs that pass count toward the per-path limits.
+func (d *trapDetector) check(u *url.URL) (reason, pattern string) {
+	if d.policy.MaxSegmentRepeats > 0 && repeatsSegmentRun(u.Path, d.policy.MaxSegmentRepeats) {
+		return domain.TrapRepeatingSegments, u.Host + pathShape(u.Path)
+	}
+
+	if d.policy.CalendarYearsAhead > 0 {
+		limit := d.now().Year() + d.policy.CalendarYearsAhead
+		for _, year := range calendarYears(u) {
+			if year > limit {
+				return domain.TrapCalendar, u.Host + pathShape(u.Path)
+			}
+		}
+	}
+
+	if u.RawQuery == "" {
+		return "", ""
+	}
+
+	key := u.Host + u.Path
+	names := paramNames(u.RawQuery)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	combos := d.combos[key]
+	newCombo := !combos[names]
+	if d.policy.MaxParamCombinations > 0 && newCombo && len(combos) >= d.policy.MaxParamCombinations {
+		return domain.TrapParamCombinations, key
+	}
+	if d.policy.MaxPathVariants > 0 && d.variants[key] >= d.policy.MaxPathVariants {
+		return domain.TrapPathVariants, key
+	}
+
+	if newCombo {
+		if combos == nil {
+			combos = make(map[string]bool)
+			d.combos[key] = combos
+		}
+		combos[names] = true
+	}
+	d.variants[key]++
+	return "", ""
+}
+
+// record adds a suppressed URL to the trap report.
+func (d *trapDetector) record(reason, pattern, rawURL string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := reason + " " + pattern
+	report, ok := d.reports[key]
+	if !ok {
+		report = &domain.CrawlTrap{Reason: reason, Pattern: pattern}
+		d.reports[key] = report
+	}
+	report.Suppressed++
+	if len(report.Examples) < maxTrapExamples {
+		report.Examples = append(report.Examples, rawURL)
+	}
+}
+
+// paramNames returns the sorted, distinct query parameter names of rawQuery joined by commas.
+func paramNames(rawQuery string) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if name := queryKey(pair); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// pathShape replaces digit runs in a path with {n}, so /2031/05 becomes /{n}/{n}.
+func pathShape(path string) string {
+	return digitRunPattern.ReplaceAllString(path, "{n}")
+}
+
+// repeatsSegmentRun reports whether some run of consecutive path segments
+// repeats back to back more than limit times, as in /a/b/a/b/a/b. Repeated
+// values in different positions, such as /orgs/1/teams/1, do not count.
+func repeatsSegmentRun(path string, limit int) bool {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	for size := 1; size*(limit+1) <= len(segments); size++ {
+		for start := 0; start+size*(limit+1) <= len(segments); start++ {
+			repeats := 1
+			for next := start + size; next+size <= len(segments); next += size {
+				if !slices.Equal(segments[start:start+size], segments[next:next+size]) {
+					break
+				}
+				if repeats++; repeats > limit {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// calendarYears returns the years named by date-shaped parts of u: a year
+// segment followed by a month segment (/2031/05), a date such as 2031-05-01,
+// or a year query parameter (?year=2031).
+func calendarYears(u *url.URL) []int {
+	var years []int
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if year, ok := parseYear(segment); ok && i+1 < len(segments) && isMonth(segments[i+1]) {
+			years = append(years, year)
+		}
+		years = append(years, dateYears(segment)...)
+	}
+
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		name, value, _ := strings.Cut(pair, "=")
+		if value, err := url.QueryUnescape(value); err == nil {
+			if year, ok := parseYear(value); ok && strings.EqualFold(name, "year") {
+				years = append(years, year)
+			}
+			years = append(years, dateYears(value)...)
+		}
+	}
+
+	return years
+}
+
+// dateYears returns the years of the YYYY-MM and YYYY-MM-DD dates in s.
+func dateYears(s string) []int {
+	var years []int
+	for _, match := range datePattern.FindAllStringSubmatch(s, -1) {
+		if year, ok := parseYear(match[1]); ok {
+			years = append(years, year)
+		}
+	}
+	return years
+}
+
+// parseYear returns the year a four-digit token from 2000 to 2099 names.
+func parseYear(token string) (int, bool) {
+	if len(token) != 4 || !strings.HasPrefix(token, "20") {
+		return 0, false
+	}
+	year := 0
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+		year = year*10 + int(r-'0')
+	}
+	return year, true
+}
+
+// isMonth reports whether a path segment is a month number, 1 to 12.
+func isMonth(segment string) bool {
+	month, err := strconv.Atoi(segment)
+	return err == nil && len(segment) <= 2 && month >= 1 && month <= 12
+}
+
+// GetCrawlTraps returns the URLs suppressed by trap detection, grouped by trap,
+// most-suppressed first.
+func (c *Crawler) GetCrawlTraps() []domain.CrawlTrap {
+	if c.traps == nil {
+		return nil
+	}
+	return c.traps.snapshot().Suppressed
+}
+
+// snapshot returns the detector's state with reports sorted most-suppressed first.
+func (d *trapDetector) snapshot() domain.TrapState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := domain.TrapState{
+		PathVariants:      make(map[string]int, len(d.variants)),
+		ParamCombinations: make(map[string][]string, len(d.combos)),
+	}
+	for key, count := range d.variants {
+		state.PathVariants[key] = count
+	}
+	for key, combos := range d.combos {
+		names := make([]string, 0, len(combos))
+		for combo := range combos {
+			names = append(names, combo)
+		}
+		sort.Strings(names)
+		state.ParamCombinations[key] = names
+	}
+	for _, report := range d.reports {
+		copied := *report
+		copied.Examples = append([]string(nil), report.Examples...)
+		state.Suppressed = append(state.Suppressed, copied)
+	}
+
+	sort.Slice(state.Suppressed, func(i, j int) bool {
+		a, b := state.Suppressed[i], state.Suppressed[j]
+		if a.Suppressed != b.Suppressed {
+			return a.Suppressed > b.Suppressed
+		}
+		if a.Reason != b.Reason {
+			return a.Reason < b.Reason
+		}
+		return a.Pattern < b.Pattern
+	})
+
+	return state
+}
+
+// restore replaces the detector's state with a snapshot.
+func (d *trapDetector) restore(state domain.TrapState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, count := range state.PathVariants {
+		d.variants[key] = count
+	}
+	for key, names := range state.ParamCombinations {
+		combos := make(map[string]bool, len(names))
+		for _, combo := range names {
+			combos[combo] = true
+		}
+		d.combos[key] = combos
+	}
+	for _, report := range state.Suppressed {
+		restored := report
+		d.reports[report.Reason+" "+report.Pattern] = &restored
+	}
+}
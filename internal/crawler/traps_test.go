@@ -0,0 +1,224 @@
+package crawler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestAddLink_TrapDetection(t *testing.T) {
+	tests := []struct {
+		name       string
+		links      []string
+		modify     func(p *domain.TrapPolicy)
+		wantAdded  int
+		wantReason string
+		wantPat    string
+	}{
+		{
+			name:       "repeating path segments",
+			links:      []string{"/a/b/a/b/", "/a/b/a/b/a/b/"},
+			wantAdded:  1,
+			wantReason: domain.TrapRepeatingSegments,
+			wantPat:    "example.com/a/b/a/b/a/b/",
+		},
+		{
+			name:       "calendar beyond the horizon",
+			links:      []string{"/calendar/2027/05", "/calendar/2028/01", "/events?year=2031"},
+			wantAdded:  2,
+			wantReason: domain.TrapCalendar,
+			wantPat:    "example.com/events",
+		},
+		{
+			name:       "repeated values in different positions",
+			links:      []string{"/orgs/1/teams/1/members/1", "/x/1/1/y"},
+			wantAdded:  2,
+			wantReason: "",
+		},
+		{
+			name:       "calendar dates in the path",
+			links:      []string{"/log/2027-12-31", "/log/2031-05-01"},
+			wantAdded:  1,
+			wantReason: domain.TrapCalendar,
+			wantPat:    "example.com/log/{n}-{n}-{n}",
+		},
+		{
+			name:       "calendar dates and years in the query",
+			links:      []string{"/events?from=2027-12", "/events?from=2031-05", "/events?Year=2031"},
+			wantAdded:  1,
+			wantReason: domain.TrapCalendar,
+			wantPat:    "example.com/events",
+		},
+		{
+			name:       "numbers that are not dates",
+			links:      []string{"/products/2030", "/list?page=2031", "/sku/2031-99", "/build/20310501"},
+			wantAdded:  4,
+			wantReason: "",
+		},
+		{
+			name:       "past years are crawled",
+			links:      []string{"/archive/2001/01", "/archive/2019/12"},
+			wantAdded:  2,
+			wantReason: "",
+		},
+		{
+			name: "query-string variants per path",
+			links: []string{
+				"/search?q=1", "/search?q=2", "/search?q=3", "/search?q=4",
+			},
+			modify:     func(p *domain.TrapPolicy) { p.MaxPathVariants = 3 },
+			wantAdded:  3,
+			wantReason: domain.TrapPathVariants,
+			wantPat:    "example.com/search",
+		},
+		{
+			name: "parameter combinations per path",
+			links: []string{
+				"/list?a=1", "/list?b=1", "/list?a=2&b=2", "/list?a=3", "/list?c=1",
+			},
+			modify:     func(p *domain.TrapPolicy) { p.MaxParamCombinations = 3 },
+			wantAdded:  4,
+			wantReason: domain.TrapParamCombinations,
+			wantPat:    "example.com/list",
+		},
+		{
+			name:       "zero limit disables the check",
+			links:      []string{"/a/a/a/a"},
+			modify:     func(p *domain.TrapPolicy) { p.MaxSegmentRepeats = 0 },
+			wantAdded:  1,
+			wantReason: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := domain.DefaultTrapPolicy()
+			if tt.modify != nil {
+				tt.modify(&policy)
+			}
+			c := newTestCrawler(t, 5, Options{Traps: policy, TrackFrontier: true})
+			urlQueue := make(chan domain.URLTask, 20)
+
+			added := 0
+			for _, link := range tt.links {
+				result := c.AddLink(domain.Link{URL: link}, 1, urlQueue)
+				if result.Added {
+					added++
+				} else if result.Reason != domain.AddURLTrap {
+					t.Errorf("Expected %s to be added or trapped, got reason %q", link, result.Reason)
+				}
+			}
+			if added != tt.wantAdded {
+				t.Errorf("Expected %d URLs added, got %d", tt.wantAdded, added)
+			}
+
+			traps := c.GetCrawlTraps()
+			if tt.wantReason == "" {
+				if len(traps) != 0 {
+					t.Errorf("Expected no traps, got %+v", traps)
+				}
+				return
+			}
+			if len(traps) != 1 {
+				t.Fatalf("Expected 1 trap, got %+v", traps)
+			}
+			if traps[0].Reason != tt.wantReason || traps[0].Pattern != tt.wantPat {
+				t.Errorf("Expected %s trap on %s, got %+v", tt.wantReason, tt.wantPat, traps[0])
+			}
+			if traps[0].Suppressed != len(tt.links)-tt.wantAdded || len(traps[0].Examples) == 0 {
+				t.Errorf("Expected %d suppressed URL(s) with examples, got %+v", len(tt.links)-tt.wantAdded, traps[0])
+			}
+		})
+	}
+}
+
+func TestAddLink_TrapPatternReplacesDigits(t *testing.T) {
+	c := newTestCrawler(t, 5, Options{Traps: domain.DefaultTrapPolicy(), TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 20)
+
+	c.AddLink(domain.Link{URL: "/calendar/2040/01"}, 1, urlQueue)
+	c.AddLink(domain.Link{URL: "/calendar/2041/02"}, 1, urlQueue)
+
+	traps := c.GetCrawlTraps()
+	if len(traps) != 1 || traps[0].Pattern != "example.com/calendar/{n}/{n}" || traps[0].Suppressed != 2 {
+		t.Fatalf("Expected both months grouped under one pattern, got %+v", traps)
+	}
+}
+
+func TestAddLink_StripsSessionIDs(t *testing.T) {
+	c := newTestCrawler(t, 5, Options{Traps: domain.TrapPolicy{SessionParams: []string{"token"}}, TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 20)
+
+	links := []string{
+		"/page;jsessionid=ABC123",
+		"/page?PHPSESSID=xyz",
+		"/page?token=1",
+		"/page?sid=2&view=full",
+	}
+	for _, link := range links {
+		c.AddLink(domain.Link{URL: link}, 1, urlQueue)
+	}
+	close(urlQueue)
+
+	var queued []string
+	for task := range urlQueue {
+		queued = append(queued, task.URL)
+	}
+	expected := []string{"http://example.com/page", "http://example.com/page?view=full"}
+	if fmt.Sprint(queued) != fmt.Sprint(expected) {
+		t.Errorf("Expected session IDs stripped to %v, got %v", expected, queued)
+	}
+
+	suppressed := 0
+	for _, trap := range c.GetCrawlTraps() {
+		if trap.Reason != domain.TrapSessionID {
+			t.Errorf("Expected only session ID traps, got %+v", trap)
+		}
+		suppressed += trap.Suppressed
+	}
+	if suppressed != len(links) {
+		t.Errorf("Expected %d session IDs recorded, got %d", len(links), suppressed)
+	}
+}
+
+func TestAddLink_TrapDetectionDisabled(t *testing.T) {
+	c := newTestCrawler(t, 5, Options{Traps: domain.TrapPolicy{Disabled: true}})
+	urlQueue := make(chan domain.URLTask, 20)
+
+	for _, link := range []string{"/a/a/a/a", "/page?sid=1", "/calendar/2099/01"} {
+		if result := c.AddLink(domain.Link{URL: link}, 1, urlQueue); !result.Added {
+			t.Errorf("Expected %s added with trap detection disabled, got %q", link, result.Reason)
+		}
+	}
+	if traps := c.GetCrawlTraps(); len(traps) != 0 {
+		t.Errorf("Expected no traps, got %+v", traps)
+	}
+}
+
+func TestTrapState_SnapshotRestore(t *testing.T) {
+	policy := domain.DefaultTrapPolicy()
+	policy.MaxPathVariants = 2
+	c := newTestCrawler(t, 5, Options{Traps: policy, TrackFrontier: true})
+	urlQueue := make(chan domain.URLTask, 20)
+
+	c.AddLink(domain.Link{URL: "/search?q=1"}, 1, urlQueue)
+	c.AddLink(domain.Link{URL: "/search?q=2"}, 1, urlQueue)
+	c.AddLink(domain.Link{URL: "/search?q=3"}, 1, urlQueue)
+
+	state := snapshot(t, c)
+	if state.Traps == nil || state.Traps.PathVariants["example.com/search"] != 2 {
+		t.Fatalf("Expected path variants in snapshot, got %+v", state.Traps)
+	}
+
+	resumed := newTestCrawler(t, 5, Options{Traps: policy, TrackFrontier: true})
+	if err := resumed.Restore(state, make(chan domain.URLTask, 20)); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result := resumed.AddLink(domain.Link{URL: "/search?q=4"}, 1, urlQueue); result.Reason != domain.AddURLTrap {
+		t.Errorf("Expected variant limit to survive a resume, got %q", result.Reason)
+	}
+	if traps := resumed.GetCrawlTraps(); len(traps) != 1 || traps[0].Suppressed != 2 {
+		t.Errorf("Expected restored trap report to keep counting, got %+v", traps)
+	}
+}
@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// visitedSet records which normalized URLs have been discovered.
+type visitedSet interface {
+	// Add marks key as visited and returns true if it was not already present.
+	Add(key string) bool
+}
+
+// memorySet is an exact visited set backed by sync.Map. Memory grows with every URL.
+type memorySet struct {
+	m sync.Map
+}
+
+func (s *memorySet) Add(key string) bool {
+	_, exists := s.m.LoadOrStore(key, true)
+	return !exists
+}
+
+// bloomSet is a memory-bounded visited set backed by a Bloom filter.
+// False positives cause a small fraction of new URLs to be treated as duplicates
+// (never crawled); false negatives are impossible, so no URL is crawled twice.
+type bloomSet struct {
+	mu      sync.Mutex
+	bits    []uint64
+	numBits uint64
+	numHash uint64
+}
+
+// newBloomSet sizes a Bloom filter for capacity keys at the given false-positive rate.
+func newBloomSet(capacity int, falsePositiveRate float64) *bloomSet {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	n := float64(capacity)
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/n*math.Ln2))
+
+	numBits := uint64(m)
+	return &bloomSet{
+		bits:    make([]uint64, (numBits+63)/64),
+		numBits: numBits,
+		numHash: uint64(k),
+	}
+}
+
+func (s *bloomSet) Add(key string) bool {
+	h1, h2 := bloomHashes(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := false
+	for i := uint64(0); i < s.numHash; i++ {
+		// Kirsch-Mitzenmacher double hashing derives k indexes from two hashes
+		idx := (h1 + i*h2) % s.numBits
+		word, mask := idx/64, uint64(1)<<(idx%64)
+		if s.bits[word]&mask == 0 {
+			s.bits[word] |= mask
+			added = true
+		}
+	}
+	return added
+}
+
+// bloomHashes returns two independent 64-bit hashes of key.
+// FNV is used (rather than maphash) so hashes are stable across processes.
+func bloomHashes(key string) (h1, h2 uint64) {
+	a := fnv.New64a()
+	_, _ = a.Write([]byte(key))
+	b := fnv.New64()
+	_, _ = b.Write([]byte(key))
+	// Forcing the second hash odd avoids a zero stride
+	return a.Sum64(), b.Sum64() | 1
+}
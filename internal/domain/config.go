@@ -2,8 +2,14 @@
 package domain
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
 )
 
 // AuthConfig represents authentication configuration for HTTP requests.
@@ -33,16 +39,42 @@ type Config struct {
 	PerformanceTargets PerformanceTargets `json:"performance_targets"`
 	// Auth contains authentication configuration (optional).
 	Auth *AuthConfig `json:"auth,omitempty"`
+	// Normalization controls URL canonicalization for deduplication (defaults apply when omitted).
+	Normalization *URLNormalization `json:"normalization,omitempty"`
+	// Frontier controls queue overflow spilling and visited-set memory use.
+	Frontier FrontierConfig `json:"frontier"`
+	// Checkpoint controls periodic saving of crawl state for resuming.
+	Checkpoint CheckpointConfig `json:"checkpoint"`
+	// Latency controls histogram precision and raw response time sampling.
+	Latency LatencyConfig `json:"latency"`
+	// Redirects controls how redirects are followed and recorded (defaults apply when omitted).
+	Redirects *RedirectPolicy `json:"redirects,omitempty"`
+	// Traps controls crawler trap detection (defaults apply when omitted).
+	Traps *TrapPolicy `json:"traps,omitempty"`
+	// Denylist keeps the crawler away from destructive links (defaults apply when omitted).
+	Denylist *DenylistPolicy `json:"denylist,omitempty"`
+	// Forms controls how HTML forms are turned into requests (defaults apply when omitted).
+	Forms *FormPolicy `json:"forms,omitempty"`
+	// Routes controls how URLs are grouped into routes for per-route statistics (defaults apply when omitted).
+	Routes *RoutePolicy `json:"routes,omitempty"`
+	// Success decides which responses count as successful requests (2xx and 3xx when omitted).
+	Success *SuccessPolicy `json:"success,omitempty"`
 	// BaseURL is the starting URL for the stress test (required).
 	BaseURL string `json:"base_url"`
 	// Duration is the test duration as a Go duration string (e.g., "2m", "30s").
 	Duration string `json:"duration"`
 	// Timeout is the HTTP request timeout as a Go duration string.
 	Timeout string `json:"timeout"`
+	// MetricsInterval is the width of each time-series bucket as a Go duration string.
+	MetricsInterval string `json:"metrics_interval"`
 	// UserAgent is the User-Agent header sent with each request.
 	UserAgent string `json:"user_agent"`
 	// OutputFile is the path to write the report (HTML or JSON based on extension).
 	OutputFile string `json:"output_file"`
+	// GraphOutput is the path to export the link graph to; .dot/.gv, .graphml or .json selects the format.
+	GraphOutput string `json:"graph_output"`
+	// RawOutput is the path to stream one JSON line per request to while the test runs.
+	RawOutput string `json:"raw_output"`
 	// Rate is the maximum requests per second per worker (0 = unlimited).
 	Rate float64 `json:"rate"`
 	// Concurrency is the number of parallel workers making requests.
@@ -51,18 +83,32 @@ type Config struct {
 	MaxDepth int `json:"max_depth"`
 	// QueueSize is the maximum number of URLs to queue for testing.
 	QueueSize int `json:"queue_size"`
+	// MaxResponseSize caps how many response body bytes are read per request.
+	MaxResponseSize int64 `json:"max_response_size"`
+	// MaxURLValidations caps the per-URL results kept in the report; the first URLs tested are kept.
+	MaxURLValidations int `json:"max_url_validations"`
+	// NoURLValidations keeps no per-URL results; counters and statistics still cover every request.
+	NoURLValidations bool `json:"no_url_validations"`
 	// FollowLinks enables recursive link discovery from HTML pages.
 	FollowLinks bool `json:"follow_links"`
 	// Respect429 enables exponential backoff on HTTP 429 responses.
 	Respect429 bool `json:"respect_429"`
 	// DryRun discovers URLs without making test requests.
 	DryRun bool `json:"dry_run"`
+	// LinkCheck checks every discovered URL once and reports broken links instead of load testing.
+	LinkCheck bool `json:"link_check"`
+	// CheckExternal also checks off-site links in link-check mode, without crawling them.
+	CheckExternal bool `json:"check_external"`
 	// Verbose enables detailed logging output.
 	Verbose bool `json:"verbose"`
 	// InsecureSkipVerify skips TLS certificate validation (requires LOBSTER_INSECURE_TLS=true).
 	InsecureSkipVerify bool `json:"insecure_skip_verify"`
 	// IgnoreRobots bypasses robots.txt restrictions.
 	IgnoreRobots bool `json:"ignore_robots"`
+	// IgnoreNofollow follows links excluded by meta robots, X-Robots-Tag or rel=nofollow.
+	IgnoreNofollow bool `json:"ignore_nofollow"`
+	// LinkExtractors lists the link extractors to run: "html", "json", "feed", "link_header", "js".
+	LinkExtractors []string `json:"link_extractors,omitempty"`
 }
 
 // TesterConfig represents the internal configuration for the stress tester.
@@ -72,6 +118,32 @@ type TesterConfig struct {
 	RequestTimeout time.Duration
 	// Auth contains authentication settings.
 	Auth *AuthConfig
+	// Normalization controls URL canonicalization for deduplication.
+	Normalization URLNormalization
+	// Frontier controls queue overflow spilling and the visited-set implementation.
+	Frontier FrontierConfig
+	// Latency controls histogram precision and raw response time sampling.
+	Latency LatencyConfig
+	// Redirects controls how redirects are followed and recorded.
+	Redirects RedirectPolicy
+	// Traps controls crawler trap detection (zero limits disable the corresponding check).
+	Traps TrapPolicy
+	// Denylist controls which links are never followed.
+	Denylist DenylistPolicy
+	// Forms controls how HTML forms are turned into requests.
+	Forms FormPolicy
+	// Routes controls how URLs are grouped into routes for per-route statistics.
+	Routes RoutePolicy
+	// Success decides which responses count as successful requests.
+	Success SuccessPolicy
+	// LinkExtractors lists the link extractors to run (empty = DefaultLinkExtractors).
+	LinkExtractors []string
+	// CheckpointPath is the file crawl state is saved to ("" = no checkpoints).
+	CheckpointPath string
+	// GraphPath is the file the link graph is exported to ("" = no graph is kept).
+	GraphPath string
+	// RawOutputPath is the file every request is streamed to as NDJSON ("" = none).
+	RawOutputPath string
 	// BaseURL is the starting URL for the stress test.
 	BaseURL string
 	// UserAgent is the User-Agent header value.
@@ -84,44 +156,492 @@ type TesterConfig struct {
 	MaxDepth int
 	// QueueSize is the URL queue capacity.
 	QueueSize int
-	// MaxResponseSize is the maximum response body to read (default 10MB).
+	// MaxResponseSize is the maximum response body to read (0 = DefaultMaxResponseSize).
 	MaxResponseSize int64
+	// MaxURLValidations is the most per-URL results kept (0 = DefaultMaxURLValidations).
+	MaxURLValidations int
+	// NoURLValidations keeps no per-URL results.
+	NoURLValidations bool
+	// CheckpointInterval is how often crawl state is saved to CheckpointPath.
+	CheckpointInterval time.Duration
+	// MetricsInterval is the width of each time-series bucket (0 = DefaultMetricsInterval).
+	MetricsInterval time.Duration
 	// FollowLinks enables link discovery from responses.
 	FollowLinks bool
 	// Respect429 enables backoff on rate limit responses.
 	Respect429 bool
 	// DryRun discovers URLs without stress testing.
 	DryRun bool
+	// LinkCheck checks each URL once (HEAD with GET fallback) and reports broken links.
+	LinkCheck bool
+	// CheckExternal checks off-site links in link-check mode without crawling them.
+	CheckExternal bool
 	// InsecureSkipVerify skips TLS certificate validation.
 	InsecureSkipVerify bool
 	// IgnoreRobots bypasses robots.txt restrictions.
 	IgnoreRobots bool
+	// IgnoreNofollow follows links excluded by meta robots, X-Robots-Tag or rel=nofollow.
+	IgnoreNofollow bool
 	// Verbose enables detailed logging.
 	Verbose bool
 	// NoProgress disables the progress bar.
 	NoProgress bool
+	// Resume continues the crawl saved at CheckpointPath instead of starting from BaseURL.
+	Resume bool
+}
+
+// Trailing-slash policies for URL normalization.
+const (
+	TrailingSlashKeep  = "keep"
+	TrailingSlashStrip = "strip"
+	TrailingSlashAdd   = "add"
+)
+
+// URLNormalization controls how discovered URLs are canonicalized before deduplication.
+// URLs that normalize to the same string are crawled only once.
+type URLNormalization struct {
+	// TrailingSlash is the trailing-slash policy: "keep" (default), "strip", or "add".
+	TrailingSlash string `json:"trailing_slash"`
+	// TrackingParams lists extra query parameters to drop (case-insensitive, "prefix*" allowed).
+	TrackingParams []string `json:"tracking_params,omitempty"`
+	// LowercaseHost lowercases the scheme and host.
+	LowercaseHost bool `json:"lowercase_host"`
+	// StripDefaultPort removes :80 from http URLs and :443 from https URLs.
+	StripDefaultPort bool `json:"strip_default_port"`
+	// SortQuery orders query parameters by name.
+	SortQuery bool `json:"sort_query"`
+	// StripTracking drops tracking parameters such as utm_* and fbclid.
+	StripTracking bool `json:"strip_tracking"`
+	// HonorCanonical treats a page as a duplicate of its <link rel="canonical"> target.
+	HonorCanonical bool `json:"honor_canonical"`
+}
+
+// DefaultURLNormalization returns the normalization applied when none is configured.
+// Only transformations that preserve the resource being addressed are enabled.
+func DefaultURLNormalization() URLNormalization {
+	return URLNormalization{
+		TrailingSlash:    TrailingSlashKeep,
+		LowercaseHost:    true,
+		StripDefaultPort: true,
+		SortQuery:        true,
+		StripTracking:    true,
+		HonorCanonical:   false,
+	}
+}
+
+// UnmarshalJSON decodes a normalization block, keeping the defaults for fields
+// the JSON omits so enabling one option does not turn the others off.
+func (n *URLNormalization) UnmarshalJSON(data []byte) error {
+	type plain URLNormalization
+	normalization := plain(DefaultURLNormalization())
+	if err := json.Unmarshal(data, &normalization); err != nil {
+		return err
+	}
+	*n = URLNormalization(normalization)
+	return nil
+}
+
+// Validate checks that normalization values are valid.
+func (n *URLNormalization) Validate() error {
+	switch n.TrailingSlash {
+	case "", TrailingSlashKeep, TrailingSlashStrip, TrailingSlashAdd:
+		return nil
+	default:
+		return fmt.Errorf("invalid trailing_slash %q: must be one of keep, strip, add", n.TrailingSlash)
+	}
+}
+
+// Visited-set implementations for crawl deduplication.
+const (
+	VisitedSetMemory = "memory"
+	VisitedSetBloom  = "bloom"
+)
+
+// FrontierConfig controls how the crawl frontier and visited set use memory.
+type FrontierConfig struct {
+	// SpillDir enables spilling queue overflow to disk under this directory ("" = drop overflow).
+	SpillDir string `json:"spill_dir"`
+	// VisitedSet selects deduplication storage: "memory" (exact) or "bloom" (bounded memory).
+	VisitedSet string `json:"visited_set"`
+	// BloomCapacity is the expected number of unique URLs the Bloom filter is sized for.
+	BloomCapacity int `json:"bloom_capacity"`
+	// BloomFalsePositiveRate is the target rate of new URLs wrongly treated as seen (0-1).
+	BloomFalsePositiveRate float64 `json:"bloom_false_positive_rate"`
+}
+
+// LatencyConfig controls how latency measurements are kept.
+type LatencyConfig struct {
+	// Precision is the number of significant digits latency histograms keep (1-5).
+	Precision int `json:"precision"`
+	// Samples is how many raw response times are kept, picked by reservoir sampling.
+	Samples int `json:"samples"`
+	// NoSamples keeps no raw response times; statistics come from the histograms alone.
+	NoSamples bool `json:"no_samples"`
+}
+
+// DefaultLatencyConfig returns the default latency configuration.
+func DefaultLatencyConfig() LatencyConfig {
+	return LatencyConfig{
+		Precision: histogram.DefaultPrecision,
+		Samples:   DefaultLatencySamples,
+	}
+}
+
+// Validate checks that latency values are valid.
+func (l *LatencyConfig) Validate() error {
+	if l.Precision < histogram.MinPrecision || l.Precision > histogram.MaxPrecision {
+		return fmt.Errorf("precision must be between %d and %d, got %d", histogram.MinPrecision, histogram.MaxPrecision, l.Precision)
+	}
+	if l.Samples < 0 {
+		return fmt.Errorf("samples cannot be negative, got %d", l.Samples)
+	}
+	return nil
+}
+
+// DefaultFrontierConfig returns the default frontier configuration.
+func DefaultFrontierConfig() FrontierConfig {
+	return FrontierConfig{
+		SpillDir:               "",
+		VisitedSet:             VisitedSetMemory,
+		BloomCapacity:          1000000, // ~1.8MB at a 0.1% false-positive rate
+		BloomFalsePositiveRate: 0.001,
+	}
+}
+
+// Validate checks that frontier values are valid.
+func (f *FrontierConfig) Validate() error {
+	if f.VisitedSet != "" && f.VisitedSet != VisitedSetMemory && f.VisitedSet != VisitedSetBloom {
+		return fmt.Errorf("invalid visited_set %q: must be one of memory, bloom", f.VisitedSet)
+	}
+	if f.BloomCapacity < 0 {
+		return fmt.Errorf("bloom_capacity cannot be negative, got %d", f.BloomCapacity)
+	}
+	if f.BloomFalsePositiveRate < 0 || f.BloomFalsePositiveRate >= 1 {
+		return fmt.Errorf("bloom_false_positive_rate must be between 0 and 1, got %g", f.BloomFalsePositiveRate)
+	}
+	return nil
+}
+
+// CheckpointConfig controls periodic saving of crawl state.
+type CheckpointConfig struct {
+	// Path is the checkpoint file ("" = checkpointing disabled).
+	Path string `json:"path"`
+	// Interval is how often to save as a Go duration string (e.g., "30s").
+	Interval string `json:"interval"`
+}
+
+// DefaultCheckpointConfig returns the default checkpoint configuration.
+func DefaultCheckpointConfig() CheckpointConfig {
+	return CheckpointConfig{
+		Path:     "",
+		Interval: "30s",
+	}
+}
+
+// Validate checks that checkpoint values are valid.
+func (c *CheckpointConfig) Validate() error {
+	if c.Interval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint interval %q: %w", c.Interval, err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("checkpoint interval must be > 0, got %s", c.Interval)
+	}
+	return nil
+}
+
+// RedirectPolicy controls how redirects are followed and recorded.
+// Scope is the base URL's host; redirects to other hosts are cross-scope.
+type RedirectPolicy struct {
+	// MaxHops is the maximum number of redirects followed per request; 0 follows none.
+	MaxHops int `json:"max_hops"`
+	// LongChainHops flags chains with at least this many hops in the report.
+	LongChainHops int `json:"long_chain_hops"`
+	// FollowCrossScope follows redirects that leave the base URL's host.
+	FollowCrossScope bool `json:"follow_cross_scope"`
+	// SeparateResults records each redirect response as its own result and
+	// queues its target as a new URL instead of following it inline.
+	SeparateResults bool `json:"separate_results"`
+}
+
+// DefaultRedirectPolicy returns the redirect policy applied when none is configured.
+// It matches net/http's default of following up to 10 redirects anywhere.
+func DefaultRedirectPolicy() RedirectPolicy {
+	return RedirectPolicy{
+		MaxHops:          10,
+		LongChainHops:    3,
+		FollowCrossScope: true,
+		SeparateResults:  false,
+	}
+}
+
+// UnmarshalJSON decodes a redirect policy, keeping the defaults for fields the
+// JSON omits so an explicit 0 or false can be told apart from a missing one.
+func (r *RedirectPolicy) UnmarshalJSON(data []byte) error {
+	type plain RedirectPolicy
+	policy := plain(DefaultRedirectPolicy())
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return err
+	}
+	*r = RedirectPolicy(policy)
+	return nil
+}
+
+// Validate checks that redirect policy values are valid.
+func (r *RedirectPolicy) Validate() error {
+	if r.MaxHops < 0 {
+		return fmt.Errorf("max_hops cannot be negative, got %d", r.MaxHops)
+	}
+	if r.LongChainHops < 0 {
+		return fmt.Errorf("long_chain_hops cannot be negative, got %d", r.LongChainHops)
+	}
+	return nil
+}
+
+// TrapPolicy configures detection of crawler traps: URL spaces that generate
+// endless distinct URLs, such as faceted navigation, calendars and session IDs.
+type TrapPolicy struct {
+	// SessionParams lists extra query parameters carrying session IDs (case-insensitive).
+	SessionParams []string `json:"session_params,omitempty"`
+	// MaxPathVariants caps the distinct URLs queued per path (query-string variants).
+	// Zero disables the limit, as it does for the limits below.
+	MaxPathVariants int `json:"max_path_variants"`
+	// MaxParamCombinations caps the distinct sets of query parameter names queued per path.
+	MaxParamCombinations int `json:"max_param_combinations"`
+	// MaxSegmentRepeats is the most times a run of path segments may repeat back
+	// to back, as a/b does in /a/b/a/b.
+	MaxSegmentRepeats int `json:"max_segment_repeats"`
+	// CalendarYearsAhead flags URLs with a date (/2031/05, 2031-05-01, year=2031)
+	// further than this many years in the future.
+	CalendarYearsAhead int `json:"calendar_years_ahead"`
+	// Disabled turns trap detection off entirely.
+	Disabled bool `json:"disabled"`
+}
+
+// DefaultTrapPolicy returns the trap detection applied when none is configured.
+func DefaultTrapPolicy() TrapPolicy {
+	return TrapPolicy{
+		MaxPathVariants:      100,
+		MaxParamCombinations: 25,
+		MaxSegmentRepeats:    2,
+		CalendarYearsAhead:   2,
+	}
+}
+
+// UnmarshalJSON decodes a trap policy, keeping the default limits for fields
+// the JSON omits so an explicit 0 disables a limit instead of restoring it.
+func (p *TrapPolicy) UnmarshalJSON(data []byte) error {
+	type plain TrapPolicy
+	policy := plain(DefaultTrapPolicy())
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return err
+	}
+	*p = TrapPolicy(policy)
+	return nil
+}
+
+// Validate checks that trap policy values are valid.
+func (p *TrapPolicy) Validate() error {
+	if p.MaxPathVariants < 0 {
+		return fmt.Errorf("max_path_variants cannot be negative, got %d", p.MaxPathVariants)
+	}
+	if p.MaxParamCombinations < 0 {
+		return fmt.Errorf("max_param_combinations cannot be negative, got %d", p.MaxParamCombinations)
+	}
+	if p.MaxSegmentRepeats < 0 {
+		return fmt.Errorf("max_segment_repeats cannot be negative, got %d", p.MaxSegmentRepeats)
+	}
+	if p.CalendarYearsAhead < 0 {
+		return fmt.Errorf("calendar_years_ahead cannot be negative, got %d", p.CalendarYearsAhead)
+	}
+	return nil
+}
+
+// Link extractors, each run on the responses whose content type it handles.
+const (
+	// ExtractorHTML extracts href links from text/html and XHTML bodies.
+	ExtractorHTML = "html"
+	// ExtractorJSON extracts HAL _links and JSON:API links from JSON bodies.
+	ExtractorJSON = "json"
+	// ExtractorFeed extracts item links from RSS and Atom feeds and feed
+	// autodiscovery links from HTML pages.
+	ExtractorFeed = "feed"
+	// ExtractorLinkHeader extracts RFC 8288 Link header links from any response.
+	ExtractorLinkHeader = "link_header"
+	// ExtractorJS follows same-origin <script src> files and extracts API endpoint
+	// candidates from them. Its guesses can be wrong, so it only runs when requested.
+	ExtractorJS = "js"
+)
+
+// LinkExtractors lists every link extractor, in the order they run.
+var LinkExtractors = []string{ExtractorHTML, ExtractorJSON, ExtractorFeed, ExtractorLinkHeader, ExtractorJS}
+
+// DefaultLinkExtractors lists the link extractors that run when none are configured.
+var DefaultLinkExtractors = []string{ExtractorHTML, ExtractorJSON, ExtractorFeed, ExtractorLinkHeader}
+
+// ValidateLinkExtractors checks that every extractor name is known.
+func ValidateLinkExtractors(extractors []string) error {
+	for _, extractor := range extractors {
+		if !slices.Contains(LinkExtractors, extractor) {
+			return fmt.Errorf("invalid link extractor %q: must be one of html, json, feed, link_header, js", extractor)
+		}
+	}
+	return nil
+}
+
+// DenylistPolicy configures the links the crawler never follows because requesting
+// them could end the session or change data, such as logout or delete links.
+type DenylistPolicy struct {
+	// Patterns are extra regular expressions matched case-insensitively against
+	// a link's path and query string.
+	Patterns []string `json:"patterns,omitempty"`
+	// NoDefaults drops the built-in patterns, keeping only Patterns.
+	NoDefaults bool `json:"no_defaults"`
+	// Disabled turns the denylist and session-invalidation detection off.
+	Disabled bool `json:"disabled"`
+}
+
+// Validate checks that denylist patterns compile.
+func (p *DenylistPolicy) Validate() error {
+	for _, pattern := range p.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Sort orders for the per-route and per-URL statistics tables.
+const (
+	// RouteSortRequests puts the most requested routes first.
+	RouteSortRequests = "requests"
+	// RouteSortErrors puts the routes with the most errors first.
+	RouteSortErrors = "errors"
+	// RouteSortP50, RouteSortP95 and RouteSortP99 put the slowest routes at that percentile first.
+	RouteSortP50 = "p50"
+	RouteSortP95 = "p95"
+	RouteSortP99 = "p99"
+	// RouteSortName orders routes alphabetically.
+	RouteSortName = "name"
+)
+
+// RouteSorts lists every sort order for the route tables.
+var RouteSorts = []string{RouteSortRequests, RouteSortErrors, RouteSortP50, RouteSortP95, RouteSortP99, RouteSortName}
+
+// RoutePolicy configures how request URLs are grouped into routes, such as
+// /users/123 and /users/456 into /users/:id, for per-route statistics.
+type RoutePolicy struct {
+	// Templates are path templates tried in order before automatic detection.
+	// A ":name" segment matches any single segment and a final "*" matches the
+	// rest of the path, e.g. "/users/:id" or "/static/*".
+	Templates []string `json:"templates,omitempty"`
+	// Sort orders the route tables: "requests" (default), "errors", "p50", "p95", "p99" or "name".
+	Sort string `json:"sort,omitempty"`
+	// NoAutoDetect keeps numeric and UUID path segments as they are.
+	NoAutoDetect bool `json:"no_auto_detect"`
+}
+
+// Validate checks that route templates are well formed and the sort order is known.
+func (p *RoutePolicy) Validate() error {
+	for _, template := range p.Templates {
+		if !strings.HasPrefix(template, "/") {
+			return fmt.Errorf("invalid template %q: must start with /", template)
+		}
+		segments := strings.Split(template[1:], "/")
+		for i, segment := range segments {
+			if segment == ":" {
+				return fmt.Errorf("invalid template %q: parameters need a name", template)
+			}
+			if segment == "*" && i != len(segments)-1 {
+				return fmt.Errorf("invalid template %q: * must be the last segment", template)
+			}
+		}
+	}
+	if p.Sort != "" && !slices.Contains(RouteSorts, p.Sort) {
+		return fmt.Errorf("invalid sort %q: must be one of requests, errors, p50, p95, p99, name", p.Sort)
+	}
+	return nil
 }
 
+// FormPolicy configures form discovery: requests generated from the fields of
+// HTML forms, so search pages and filters are exercised like linked pages.
+type FormPolicy struct {
+	// TextValues are the values tried in text inputs that have no default value.
+	TextValues []string `json:"text_values,omitempty"`
+	// MaxCombinations caps the requests generated per form.
+	MaxCombinations int `json:"max_combinations"`
+	// SubmitPost also submits method="post" forms, which can change data.
+	SubmitPost bool `json:"submit_post"`
+	// Disabled turns form discovery off.
+	Disabled bool `json:"disabled"`
+}
+
+// DefaultFormPolicy returns the form discovery applied when none is configured.
+func DefaultFormPolicy() FormPolicy {
+	return FormPolicy{
+		TextValues:      []string{"test"},
+		MaxCombinations: 10,
+	}
+}
+
+// Validate checks that form policy values are valid.
+func (p *FormPolicy) Validate() error {
+	if p.MaxCombinations < 0 {
+		return fmt.Errorf("max_combinations cannot be negative, got %d", p.MaxCombinations)
+	}
+	return nil
+}
+
+// DefaultMaxResponseSize is the default cap on response body bytes read per request.
+const DefaultMaxResponseSize = 10 * 1024 * 1024
+
+// DefaultMaxURLValidations is the default number of per-URL results kept in the report.
+const DefaultMaxURLValidations = 10000
+
 // DefaultConfig returns a sensible default configuration
 func DefaultConfig() Config {
+	normalization := DefaultURLNormalization()
+	redirects := DefaultRedirectPolicy()
+	traps := DefaultTrapPolicy()
+	forms := DefaultFormPolicy()
 	return Config{
 		BaseURL:            "http://localhost:3000",
 		Concurrency:        5,
 		Duration:           "2m",
 		Timeout:            "30s",
+		MetricsInterval:    DefaultMetricsInterval.String(),
 		Rate:               2.0,
 		UserAgent:          "Lobster/1.0",
 		FollowLinks:        true,
 		MaxDepth:           3,
 		QueueSize:          10000, // ~80KB per 10K queue (assuming 8 bytes per URLTask)
+		MaxResponseSize:    DefaultMaxResponseSize,
+		MaxURLValidations:  DefaultMaxURLValidations,
 		Respect429:         true,  // Respect rate limiting by default
 		DryRun:             false, // Perform actual tests by default
 		OutputFile:         "",
 		Verbose:            false,
+		Normalization:      &normalization,
+		Frontier:           DefaultFrontierConfig(),
+		Checkpoint:         DefaultCheckpointConfig(),
+		Latency:            DefaultLatencyConfig(),
+		Redirects:          &redirects,
+		Traps:              &traps,
+		Denylist:           &DenylistPolicy{},
+		Forms:              &forms,
+		Routes:             &RoutePolicy{Sort: RouteSortRequests},
+		Success:            &SuccessPolicy{},
+		LinkExtractors:     slices.Clone(DefaultLinkExtractors),
 		PerformanceTargets: DefaultPerformanceTargets(),
 	}
 }
 
+// DefaultMetricsInterval is the default width of each time-series bucket.
+const DefaultMetricsInterval = time.Second
+
 // Validate checks that all configuration values are valid.
 // Returns an error describing the first invalid value found.
 func (c *Config) Validate() error {
@@ -137,6 +657,13 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("queue-size must be > 0, got %d", c.QueueSize)
 	}
 
+	if c.MaxResponseSize < 0 {
+		return fmt.Errorf("max-response-size cannot be negative, got %d", c.MaxResponseSize)
+	}
+	if c.MaxURLValidations < 0 {
+		return fmt.Errorf("max-url-validations cannot be negative, got %d", c.MaxURLValidations)
+	}
+
 	if c.Rate < 0 {
 		return fmt.Errorf("rate cannot be negative, got %.2f", c.Rate)
 	}
@@ -153,10 +680,27 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.MetricsInterval != "" {
+		interval, err := time.ParseDuration(c.MetricsInterval)
+		if err != nil {
+			return fmt.Errorf("invalid metrics interval %q: %w", c.MetricsInterval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("metrics interval must be > 0, got %s", c.MetricsInterval)
+		}
+	}
+
 	if c.BaseURL == "" {
 		return fmt.Errorf("base URL is required")
 	}
 
+	if c.LinkCheck && c.DryRun {
+		return fmt.Errorf("link-check and dry-run cannot be combined")
+	}
+	if c.CheckExternal && !c.LinkCheck {
+		return fmt.Errorf("check-external requires link-check mode")
+	}
+
 	// Validate auth config if present
 	if c.Auth != nil {
 		if err := c.Auth.Validate(); err != nil {
@@ -164,6 +708,81 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Normalization != nil {
+		if err := c.Normalization.Validate(); err != nil {
+			return fmt.Errorf("normalization config: %w", err)
+		}
+	}
+
+	if err := c.Frontier.Validate(); err != nil {
+		return fmt.Errorf("frontier config: %w", err)
+	}
+
+	if c.Redirects != nil {
+		if err := c.Redirects.Validate(); err != nil {
+			return fmt.Errorf("redirects config: %w", err)
+		}
+	}
+
+	if c.Traps != nil {
+		if err := c.Traps.Validate(); err != nil {
+			return fmt.Errorf("traps config: %w", err)
+		}
+	}
+
+	if err := ValidateLinkExtractors(c.LinkExtractors); err != nil {
+		return err
+	}
+
+	if c.Denylist != nil {
+		if err := c.Denylist.Validate(); err != nil {
+			return fmt.Errorf("denylist config: %w", err)
+		}
+	}
+
+	if c.Forms != nil {
+		if err := c.Forms.Validate(); err != nil {
+			return fmt.Errorf("forms config: %w", err)
+		}
+	}
+
+	if c.Routes != nil {
+		if err := c.Routes.Validate(); err != nil {
+			return fmt.Errorf("routes config: %w", err)
+		}
+	}
+
+	if c.Success != nil {
+		if err := c.Success.Validate(); err != nil {
+			return fmt.Errorf("success config: %w", err)
+		}
+	}
+
+	if err := c.Checkpoint.Validate(); err != nil {
+		return fmt.Errorf("checkpoint config: %w", err)
+	}
+
+	if err := c.Latency.Validate(); err != nil {
+		return fmt.Errorf("latency config: %w", err)
+	}
+
+	if err := c.PerformanceTargets.Validate(); err != nil {
+		return fmt.Errorf("performance targets: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks that error class caps name known classes and are percentages.
+func (p *PerformanceTargets) Validate() error {
+	for class, limit := range p.ErrorClasses {
+		if !class.Valid() {
+			return fmt.Errorf("invalid error class %q", class)
+		}
+		if limit < 0 || limit > 100 {
+			return fmt.Errorf("error class %s: limit must be between 0 and 100, got %.2f", class, limit)
+		}
+	}
 	return nil
 }
 
@@ -174,6 +174,26 @@ func TestConfig_Validate_Invalid(t *testing.T) {
 			modify:  func(c *Config) { c.Duration = "not-a-duration" },
 			wantErr: "invalid duration",
 		},
+		{
+			name:    "invalid metrics interval",
+			modify:  func(c *Config) { c.MetricsInterval = "often" },
+			wantErr: "invalid metrics interval",
+		},
+		{
+			name:    "unknown error class target",
+			modify:  func(c *Config) { c.PerformanceTargets.ErrorClasses = map[ErrorClass]float64{"transport": 1} },
+			wantErr: "invalid error class",
+		},
+		{
+			name:    "error class target above 100%",
+			modify:  func(c *Config) { c.PerformanceTargets.ErrorClasses = map[ErrorClass]float64{ErrorClassHTTP5xx: 150} },
+			wantErr: "limit must be between 0 and 100",
+		},
+		{
+			name:    "zero metrics interval",
+			modify:  func(c *Config) { c.MetricsInterval = "0s" },
+			wantErr: "metrics interval must be > 0",
+		},
 		{
 			name:    "invalid timeout",
 			modify:  func(c *Config) { c.Timeout = "bad" },
@@ -184,6 +204,86 @@ func TestConfig_Validate_Invalid(t *testing.T) {
 			modify:  func(c *Config) { c.BaseURL = "" },
 			wantErr: "base URL is required",
 		},
+		{
+			name:    "invalid trailing slash policy",
+			modify:  func(c *Config) { c.Normalization.TrailingSlash = "always" },
+			wantErr: "invalid trailing_slash",
+		},
+		{
+			name:    "invalid visited set",
+			modify:  func(c *Config) { c.Frontier.VisitedSet = "disk" },
+			wantErr: "invalid visited_set",
+		},
+		{
+			name:    "invalid bloom false-positive rate",
+			modify:  func(c *Config) { c.Frontier.BloomFalsePositiveRate = 1.5 },
+			wantErr: "bloom_false_positive_rate",
+		},
+		{
+			name:    "negative redirect max hops",
+			modify:  func(c *Config) { c.Redirects.MaxHops = -1 },
+			wantErr: "max_hops cannot be negative",
+		},
+		{
+			name:    "invalid checkpoint interval",
+			modify:  func(c *Config) { c.Checkpoint.Interval = "soon" },
+			wantErr: "invalid checkpoint interval",
+		},
+		{
+			name:    "non-positive checkpoint interval",
+			modify:  func(c *Config) { c.Checkpoint.Interval = "0s" },
+			wantErr: "checkpoint interval must be > 0",
+		},
+		{
+			name:    "negative trap path variants",
+			modify:  func(c *Config) { c.Traps.MaxPathVariants = -1 },
+			wantErr: "max_path_variants cannot be negative",
+		},
+		{
+			name:    "negative form combinations",
+			modify:  func(c *Config) { c.Forms.MaxCombinations = -1 },
+			wantErr: "forms config: max_combinations cannot be negative",
+		},
+		{
+			name:    "unknown link extractor",
+			modify:  func(c *Config) { c.LinkExtractors = []string{"html", "xml"} },
+			wantErr: `invalid link extractor "xml"`,
+		},
+		{
+			name:    "invalid denylist pattern",
+			modify:  func(c *Config) { c.Denylist.Patterns = []string{"/logout", "(unclosed"} },
+			wantErr: `denylist config: invalid pattern "(unclosed"`,
+		},
+		{
+			name:    "route template without leading slash",
+			modify:  func(c *Config) { c.Routes.Templates = []string{"users/:id"} },
+			wantErr: `routes config: invalid template "users/:id"`,
+		},
+		{
+			name:    "route template with inner wildcard",
+			modify:  func(c *Config) { c.Routes.Templates = []string{"/static/*/img"} },
+			wantErr: "* must be the last segment",
+		},
+		{
+			name:    "unknown route sort",
+			modify:  func(c *Config) { c.Routes.Sort = "latency" },
+			wantErr: `routes config: invalid sort "latency"`,
+		},
+		{
+			name:    "invalid success status",
+			modify:  func(c *Config) { c.Success.Status = []string{"2xx", "600"} },
+			wantErr: `success config: invalid status "600"`,
+		},
+		{
+			name:    "link check with dry run",
+			modify:  func(c *Config) { c.LinkCheck, c.DryRun = true, true },
+			wantErr: "link-check and dry-run cannot be combined",
+		},
+		{
+			name:    "check external without link check",
+			modify:  func(c *Config) { c.CheckExternal = true },
+			wantErr: "check-external requires link-check mode",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+// EndpointStats aggregate the requests made to one URL or route.
+type EndpointStats struct {
+	// StatusCodes counts the responses by HTTP status code.
+	StatusCodes map[int]int64 `json:"status_codes"`
+	// Histogram holds the response times, so statistics can be resumed and merged.
+	Histogram *histogram.Histogram `json:"histogram"`
+	// Name is the URL or route, prefixed with the method when it is not GET.
+	Name string `json:"name"`
+	// Latency summarizes the response times.
+	Latency LatencyStats `json:"latency"`
+	// Requests counts every request, including those that got no response.
+	Requests int64 `json:"requests"`
+	// Errors counts the requests that got no response or an invalid status.
+	Errors int64 `json:"errors"`
+	// ErrorClasses counts the errors by class.
+	ErrorClasses map[ErrorClass]int64 `json:"error_classes,omitempty"`
+}
+
+// NewEndpointStats returns empty statistics whose histogram keeps precision significant digits.
+func NewEndpointStats(name string, precision int) *EndpointStats {
+	return &EndpointStats{
+		StatusCodes: make(map[int]int64),
+		Histogram:   histogram.New(precision),
+		Name:        name,
+	}
+}
+
+// Record adds a request that got a response.
+func (e *EndpointStats) Record(validation URLValidation) {
+	e.Requests++
+	e.StatusCodes[validation.StatusCode]++
+	if !validation.IsValid {
+		e.recordClass(validation.FailureClass())
+	}
+	if validation.ResponseTime > 0 {
+		e.Histogram.Record(validation.ResponseTime)
+	}
+}
+
+// RecordError adds a request that got no response, failing with class.
+func (e *EndpointStats) RecordError(class ErrorClass) {
+	e.Requests++
+	e.recordClass(class)
+}
+
+// recordClass counts an error of class.
+func (e *EndpointStats) recordClass(class ErrorClass) {
+	e.Errors++
+	if e.ErrorClasses == nil {
+		e.ErrorClasses = make(map[ErrorClass]int64)
+	}
+	e.ErrorClasses[class]++
+}
+
+// TotalErrorClasses sums the errors of endpoint statistics by class, or returns
+// nil if there were none.
+func TotalErrorClasses(stats []EndpointStats) map[ErrorClass]int64 {
+	var totals map[ErrorClass]int64
+	for _, endpoint := range stats {
+		for class, count := range endpoint.ErrorClasses {
+			if totals == nil {
+				totals = make(map[ErrorClass]int64)
+			}
+			totals[class] += count
+		}
+	}
+	return totals
+}
+
+// ErrorRate returns the percentage of requests that were errors.
+func (e *EndpointStats) ErrorRate() float64 {
+	if e.Requests == 0 {
+		return 0
+	}
+	return float64(e.Errors) / float64(e.Requests) * 100
+}
+
+// SortEndpoints orders endpoint statistics by one of the RouteSorts, largest
+// first except by name. Ties are broken by name.
+func SortEndpoints(stats []EndpointStats, by string) {
+	slices.SortFunc(stats, func(a, b EndpointStats) int {
+		var order int
+		switch by {
+		case RouteSortErrors:
+			order = cmp.Compare(b.Errors, a.Errors)
+		case RouteSortP50:
+			order = cmp.Compare(b.Latency.P50, a.Latency.P50)
+		case RouteSortP95:
+			order = cmp.Compare(b.Latency.P95, a.Latency.P95)
+		case RouteSortP99:
+			order = cmp.Compare(b.Latency.P99, a.Latency.P99)
+		case RouteSortName:
+		default:
+			order = cmp.Compare(b.Requests, a.Requests)
+		}
+		if order != 0 {
+			return order
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+}
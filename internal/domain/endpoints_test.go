@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEndpointStats_Record(t *testing.T) {
+	stats := NewEndpointStats("/users/:id", 2)
+	stats.Record(URLValidation{StatusCode: 200, ResponseTime: 10 * time.Millisecond, IsValid: true})
+	stats.Record(URLValidation{StatusCode: 404, ResponseTime: 5 * time.Millisecond})
+	stats.RecordError(ErrorClassConnectionRefused)
+	stats.RecordError(ErrorClassConnectionRefused)
+
+	if stats.Requests != 4 || stats.Errors != 3 {
+		t.Errorf("Expected 4 requests and 3 errors, got %d and %d", stats.Requests, stats.Errors)
+	}
+	if stats.StatusCodes[200] != 1 || stats.StatusCodes[404] != 1 || len(stats.StatusCodes) != 2 {
+		t.Errorf("Expected one 200 and one 404, got %v", stats.StatusCodes)
+	}
+	if stats.ErrorClasses[ErrorClassHTTP4xx] != 1 || stats.ErrorClasses[ErrorClassConnectionRefused] != 2 {
+		t.Errorf("Expected one 4xx and two refused connections, got %v", stats.ErrorClasses)
+	}
+	if stats.Histogram.Count() != 2 {
+		t.Errorf("Expected only responses to be timed, got %d", stats.Histogram.Count())
+	}
+	if rate := stats.ErrorRate(); rate != 75 {
+		t.Errorf("Expected a 75%% error rate, got %.1f", rate)
+	}
+}
+
+func TestSortEndpoints(t *testing.T) {
+	endpoints := func() []EndpointStats {
+		return []EndpointStats{
+			{Name: "/b", Requests: 10, Errors: 1, Latency: LatencyStats{P50: 5 * time.Millisecond, P95: 90 * time.Millisecond, P99: 95 * time.Millisecond}},
+			{Name: "/a", Requests: 10, Errors: 0, Latency: LatencyStats{P50: 9 * time.Millisecond, P95: 20 * time.Millisecond, P99: 99 * time.Millisecond}},
+			{Name: "/c", Requests: 30, Errors: 5, Latency: LatencyStats{P50: 1 * time.Millisecond, P95: 10 * time.Millisecond, P99: 10 * time.Millisecond}},
+		}
+	}
+
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{RouteSortRequests, "/c /a /b"},
+		{"", "/c /a /b"},
+		{RouteSortErrors, "/c /b /a"},
+		{RouteSortP50, "/a /b /c"},
+		{RouteSortP95, "/b /a /c"},
+		{RouteSortP99, "/a /b /c"},
+		{RouteSortName, "/a /b /c"},
+	}
+	for _, tt := range tests {
+		stats := endpoints()
+		SortEndpoints(stats, tt.by)
+		got := stats[0].Name + " " + stats[1].Name + " " + stats[2].Name
+		if got != tt.want {
+			t.Errorf("SortEndpoints(%q) = %s, want %s", tt.by, got, tt.want)
+		}
+	}
+}
+
+func TestTotalErrorClasses(t *testing.T) {
+	if totals := TotalErrorClasses([]EndpointStats{{Name: "/"}}); totals != nil {
+		t.Errorf("Expected no totals without errors, got %v", totals)
+	}
+
+	totals := TotalErrorClasses([]EndpointStats{
+		{Name: "/a", ErrorClasses: map[ErrorClass]int64{ErrorClassHTTP5xx: 2, ErrorClassDNS: 1}},
+		{Name: "/b", ErrorClasses: map[ErrorClass]int64{ErrorClassHTTP5xx: 3}},
+	})
+	if totals[ErrorClassHTTP5xx] != 5 || totals[ErrorClassDNS] != 1 || len(totals) != 2 {
+		t.Errorf("Expected 5 server errors and 1 DNS failure, got %v", totals)
+	}
+}
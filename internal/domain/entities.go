@@ -7,23 +7,119 @@ import "time"
 // used to enforce MaxDepth limits during recursive link discovery.
 type URLTask struct {
 	// URL is the fully-qualified URL to request.
-	URL string
+	URL string `json:"url"`
+	// Method is the HTTP method to request the URL with ("" = GET).
+	Method string `json:"method,omitempty"`
+	// Body is the form-encoded request body of a POST form submission.
+	Body string `json:"body,omitempty"`
+	// SourceURL is the page the URL was first discovered on ("" for the base URL).
+	SourceURL string `json:"source_url,omitempty"`
+	// AnchorText is the text of the link on SourceURL, if it was an <a> element.
+	AnchorText string `json:"anchor_text,omitempty"`
+	// Feed is the RSS or Atom feed the URL was listed in, if it was found in one.
+	Feed string `json:"feed,omitempty"`
+	// Script is the JavaScript file the URL was extracted from, if it was found in one.
+	Script string `json:"script,omitempty"`
+	// Confidence is how likely a URL extracted from Script is a real endpoint.
+	Confidence string `json:"confidence,omitempty"`
 	// Depth is the crawl depth (0 = base URL, 1 = linked from base, etc.)
-	Depth int
+	Depth int `json:"depth"`
+	// Worker is the ID of the worker processing the task; it is not persisted.
+	Worker int `json:"-"`
+	// External marks an off-site URL that is checked but never crawled.
+	External bool `json:"external,omitempty"`
+}
+
+// Link is a link found on a page, before resolution and deduplication.
+type Link struct {
+	// URL is the href as written, possibly relative to SourceURL.
+	URL string
+	// SourceURL is the page the link was found on ("" = relative to the base URL).
+	SourceURL string
+	// AnchorText is the visible text of an <a> element, whitespace-collapsed.
+	AnchorText string
+	// NoFollow is set when the link's rel attribute includes nofollow.
+	NoFollow bool
+	// FromFeed is set when the link is an item of the RSS or Atom feed at SourceURL.
+	FromFeed bool
+	// Method is the HTTP method of a form submission ("" = GET).
+	Method string
+	// Body is the form-encoded request body of a POST form submission.
+	Body string
+	// Confidence is set for endpoint candidates extracted from the JavaScript file
+	// at SourceURL: one of ConfidenceHigh, ConfidenceMedium or ConfidenceLow.
+	Confidence string
+}
+
+// Confidence levels of API endpoint candidates extracted from JavaScript.
+const (
+	// ConfidenceHigh marks a path passed to fetch, axios, jQuery or XMLHttpRequest.
+	ConfidenceHigh = "high"
+	// ConfidenceMedium marks a string literal that looks like an API path (/api/, /v1/, .json).
+	ConfidenceMedium = "medium"
+	// ConfidenceLow marks any other string literal that looks like a same-origin path.
+	ConfidenceLow = "low"
+)
+
+// Referrer identifies a page linking to a URL.
+type Referrer struct {
+	// SourceURL is the linking page.
+	SourceURL string `json:"source_url"`
+	// AnchorText is the text of the link on that page.
+	AnchorText string `json:"anchor_text,omitempty"`
+}
+
+// ReferrerList holds the pages linking to a URL. Referrers is capped,
+// while Links counts every link seen, including those beyond the cap.
+type ReferrerList struct {
+	Referrers []Referrer `json:"referrers"`
+	Links     int        `json:"links"`
 }
 
 // TestResults contains comprehensive results from a stress test execution.
 // This is the main output structure containing all metrics, validations,
 // and performance data collected during the test run.
 type TestResults struct {
-	// URLValidations contains individual validation results for each URL tested.
+	// URLValidations contains the individual results of the first URLs tested, up to
+	// the URL validations limit. They are not saved in checkpoints.
 	URLValidations []URLValidation `json:"url_validations"`
+	// StatusCounts counts every URL tested by response status (0 when there was no response).
+	StatusCounts map[int]int64 `json:"status_counts,omitempty"`
 	// Errors contains all errors encountered during testing.
 	Errors []ErrorInfo `json:"errors"`
 	// SlowRequests contains requests that exceeded the slow threshold (default 2s).
 	SlowRequests []SlowRequest `json:"slow_requests"`
-	// ResponseTimes contains individual response time measurements for analysis.
+	// ResponseTimes is a uniform random sample of the response time measurements,
+	// bounded by the latency samples setting (empty when samples are turned off).
 	ResponseTimes []ResponseTimeEntry `json:"response_times"`
+	// Latency summarizes the response times of every request (nil when none was recorded).
+	Latency *LatencyStats `json:"latency,omitempty"`
+	// TimeSeries holds the requests completed in each metrics interval, oldest first.
+	TimeSeries []TimeBucket `json:"time_series,omitempty"`
+	// RouteStats aggregate the requests per route, such as /users/:id.
+	RouteStats []EndpointStats `json:"route_stats,omitempty"`
+	// URLStats aggregate the requests per URL.
+	URLStats []EndpointStats `json:"url_stats,omitempty"`
+	// ErrorClasses counts the failed requests by class.
+	ErrorClasses map[ErrorClass]int64 `json:"error_classes,omitempty"`
+	// Histograms hold the distribution of every latency measurement, for percentiles.
+	Histograms *LatencyHistograms `json:"histograms,omitempty"`
+	// RedirectIssues flags redirect loops, long chains and HTTPS-to-HTTP downgrades.
+	RedirectIssues []RedirectIssue `json:"redirect_issues,omitempty"`
+	// BrokenLinks lists failing link targets and the pages linking to them (link-check mode only).
+	BrokenLinks []BrokenLink `json:"broken_links,omitempty"`
+	// LinkGraph summarizes the site's link structure (only when a graph export was requested).
+	LinkGraph *LinkGraphStats `json:"link_graph,omitempty"`
+	// CrawlTraps lists URLs suppressed by crawler trap detection, grouped by trap.
+	CrawlTraps []CrawlTrap `json:"crawl_traps,omitempty"`
+	// DeniedLinks lists links not followed because they matched the denylist, grouped by rule.
+	DeniedLinks []DeniedLink `json:"denied_links,omitempty"`
+	// NofollowSkips counts links not followed because of nofollow directives.
+	NofollowSkips NofollowSkips `json:"nofollow_skips"`
+	// Robots summarizes the robots.txt rules applied and the URLs they blocked (nil when ignored).
+	Robots *RobotsReport `json:"robots,omitempty"`
+	// Timing breaks request latency down by phase (nil when no request was timed).
+	Timing *TimingBreakdown `json:"timing,omitempty"`
 	// PerformanceValidation contains pass/fail status for each performance target.
 	PerformanceValidation map[string]any `json:"performance_validation,omitempty"`
 	// Duration is the total test execution time as a human-readable string.
@@ -36,42 +132,155 @@ type TestResults struct {
 	MaxResponseTime string `json:"max_response_time"`
 	// TotalRequests is the total number of HTTP requests made.
 	TotalRequests int64 `json:"total_requests"`
-	// SuccessfulRequests is the count of requests with 2xx/3xx status codes.
+	// SuccessfulRequests is the count of responses meeting the success criteria (2xx/3xx by default).
 	SuccessfulRequests int64 `json:"successful_requests"`
-	// FailedRequests is the count of requests that failed or returned 4xx/5xx.
+	// FailedRequests is the count of requests that failed or whose response did not meet the success criteria.
 	FailedRequests int64 `json:"failed_requests"`
 	// RequestsPerSecond is the average throughput during the test.
 	RequestsPerSecond float64 `json:"requests_per_second"`
+	// BytesReceived is the total response body bytes downloaded.
+	BytesReceived int64 `json:"bytes_received"`
+	// BytesPerSecond is the average download bandwidth during the test.
+	BytesPerSecond float64 `json:"bytes_per_second"`
 	// SuccessRate is the percentage of successful requests (0-100).
 	SuccessRate float64 `json:"success_rate"`
 	// URLsDiscovered is the count of unique URLs found during link discovery.
 	URLsDiscovered int `json:"urls_discovered"`
+	// DuplicatesByNormalization counts links that matched a discovered URL only after normalization.
+	DuplicatesByNormalization int `json:"duplicates_by_normalization"`
+	// DuplicatesByCanonical counts pages whose rel=canonical target had already been crawled.
+	DuplicatesByCanonical int `json:"duplicates_by_canonical"`
 }
 
 // URLValidation represents the validation result for a single URL request.
-// A request is considered valid if it completes without error and returns
-// a successful HTTP status code (2xx or 3xx).
+// A request is considered valid if it completes without error and its response
+// meets the success criteria (by default, a 2xx or 3xx status).
 type URLValidation struct {
 	// ResponseTime is how long the request took to complete.
 	ResponseTime time.Duration `json:"response_time"`
 	// ContentLength is the size of the response body in bytes.
 	ContentLength int64 `json:"content_length"`
+	// BytesReceived is how much of the response body was downloaded (up to the size cap).
+	BytesReceived int64 `json:"bytes_received"`
 	// URL is the fully-qualified URL that was requested.
 	URL string `json:"url"`
+	// Method is the HTTP method used, when it was not GET.
+	Method string `json:"method,omitempty"`
 	// ContentType is the Content-Type header from the response.
 	ContentType string `json:"content_type"`
+	// SourceURL is the page this URL was first discovered on.
+	SourceURL string `json:"source_url,omitempty"`
+	// AnchorText is the text of the link on SourceURL.
+	AnchorText string `json:"anchor_text,omitempty"`
+	// Feed is the RSS or Atom feed this URL was listed in, if any.
+	Feed string `json:"feed,omitempty"`
+	// Script is the JavaScript file this URL was extracted from, if any.
+	Script string `json:"script,omitempty"`
+	// Confidence is how likely a URL extracted from Script is a real endpoint.
+	Confidence string `json:"confidence,omitempty"`
 	// Error contains the error message if the request failed, empty otherwise.
 	Error string `json:"error,omitempty"`
+	// ErrorClass is why the request failed, empty if it succeeded.
+	ErrorClass ErrorClass `json:"error_class,omitempty"`
 	// StatusCode is the HTTP status code returned (0 if request failed).
 	StatusCode int `json:"status_code"`
 	// LinksFound is the count of valid links extracted from the response body.
 	LinksFound int `json:"links_found"`
 	// Depth is how deep in the crawl tree this URL was discovered.
 	Depth int `json:"depth"`
-	// IsValid is true if the request succeeded with a 2xx/3xx status.
+	// RedirectChain lists the redirect hops taken before the final response.
+	RedirectChain []RedirectHop `json:"redirect_chain,omitempty"`
+	// FinalURL is the URL of the final response when redirects were followed.
+	FinalURL string `json:"final_url,omitempty"`
+	// RedirectStopped explains why redirects stopped early: "loop", "max_hops",
+	// "cross_scope" or "separate_result". Empty when the chain ended normally.
+	RedirectStopped string `json:"redirect_stopped,omitempty"`
+	// Timing breaks the request down by phase (load-test and link-check requests).
+	Timing *RequestTiming `json:"timing,omitempty"`
+	// IsValid is true if the response met the success criteria.
 	IsValid bool `json:"is_valid"`
 }
 
+// BrokenLink is a link target that failed or did not meet the success criteria.
+type BrokenLink struct {
+	// URL is the broken link target.
+	URL string `json:"url"`
+	// Error is the request error when no response was received.
+	Error string `json:"error,omitempty"`
+	// Referrers lists pages linking to the target (capped; see Links for the total).
+	Referrers []Referrer `json:"referrers"`
+	// StatusCode is the final HTTP status (0 if the request failed).
+	StatusCode int `json:"status_code"`
+	// Links is the total number of links to the target found during the crawl.
+	Links int `json:"links"`
+	// External is true for off-site targets.
+	External bool `json:"external,omitempty"`
+}
+
+// RedirectHop represents a single redirect response in a chain.
+type RedirectHop struct {
+	// Duration is how long this hop's request took.
+	Duration time.Duration `json:"duration"`
+	// URL is the URL that returned the redirect.
+	URL string `json:"url"`
+	// Location is the resolved redirect target.
+	Location string `json:"location"`
+	// StatusCode is the redirect status (301, 302, 303, 307, 308).
+	StatusCode int `json:"status_code"`
+}
+
+// Redirect stop reasons recorded in URLValidation.RedirectStopped.
+const (
+	RedirectStoppedLoop     = "loop"
+	RedirectStoppedMaxHops  = "max_hops"
+	RedirectStoppedScope    = "cross_scope"
+	RedirectStoppedSeparate = "separate_result"
+)
+
+// Redirect issue kinds flagged in reports.
+const (
+	RedirectIssueLoop      = "loop"
+	RedirectIssueTooMany   = "too_many_hops"
+	RedirectIssueLongChain = "long_chain"
+	RedirectIssueDowngrade = "https_downgrade"
+)
+
+// RedirectIssue flags a problematic redirect chain for the report.
+type RedirectIssue struct {
+	// URL is the URL whose redirect chain has the issue.
+	URL string `json:"url"`
+	// Kind is the issue type: "loop", "too_many_hops", "long_chain" or "https_downgrade".
+	Kind string `json:"kind"`
+	// Detail describes the issue, e.g. the hop that downgraded to HTTP.
+	Detail string `json:"detail"`
+	// Hops is the number of redirect hops in the chain.
+	Hops int `json:"hops"`
+}
+
+// RequestRecord is one request as streamed to the raw output file, one JSON line each.
+type RequestRecord struct {
+	// Timestamp is when the request finished.
+	Timestamp time.Time `json:"timestamp"`
+	// URL is the URL that was requested.
+	URL string `json:"url"`
+	// Method is the HTTP method used.
+	Method string `json:"method"`
+	// ErrorClass is why the request failed ("" = success).
+	ErrorClass ErrorClass `json:"error_class,omitempty"`
+	// Error is the error message of a request that failed without an error status.
+	Error string `json:"error,omitempty"`
+	// Timing breaks the request down by phase, when it was traced.
+	Timing *RequestTiming `json:"timing,omitempty"`
+	// ResponseTime is how long the request took to complete.
+	ResponseTime time.Duration `json:"response_time"`
+	// BytesReceived is how much of the response body was downloaded.
+	BytesReceived int64 `json:"bytes_received"`
+	// StatusCode is the HTTP status code returned (0 if the request failed).
+	StatusCode int `json:"status_code"`
+	// Worker is the ID of the worker that made the request.
+	Worker int `json:"worker_id"`
+}
+
 // ErrorInfo represents an error encountered during stress testing.
 // Errors are collected for reporting and debugging purposes.
 type ErrorInfo struct {
@@ -79,8 +288,12 @@ type ErrorInfo struct {
 	Timestamp time.Time `json:"timestamp"`
 	// URL is the URL that caused the error.
 	URL string `json:"url"`
+	// Method is the HTTP method used, when it was not GET.
+	Method string `json:"method,omitempty"`
 	// Error is the error message (may be sanitized to hide internal details).
 	Error string `json:"error"`
+	// Class is why the request failed.
+	Class ErrorClass `json:"class"`
 	// Depth is how deep in the crawl tree this URL was discovered.
 	Depth int `json:"depth"`
 }
@@ -105,6 +318,87 @@ type ResponseTimeEntry struct {
 	ResponseTime time.Duration `json:"response_time"`
 	// URL is the endpoint that was requested.
 	URL string `json:"url"`
+	// Timing breaks the request down by phase, when it was traced.
+	Timing *RequestTiming `json:"timing,omitempty"`
+}
+
+// RequestTiming breaks one request down by phase, as seen by an httptrace.ClientTrace.
+// Phases that did not happen, such as DNS and connect on a reused connection, are zero.
+// Redirect hops add up, except TTFB and Transfer, which are those of the final response.
+type RequestTiming struct {
+	// DNS is the time spent resolving the host name.
+	DNS time.Duration `json:"dns"`
+	// Connect is the time spent establishing the TCP connection.
+	Connect time.Duration `json:"connect"`
+	// TLS is the time spent on the TLS handshake.
+	TLS time.Duration `json:"tls"`
+	// TTFB is the time from the request being written to the first response byte.
+	TTFB time.Duration `json:"ttfb"`
+	// Transfer is the time from the first response byte to the last body byte read.
+	Transfer time.Duration `json:"transfer"`
+	// TTLB is the time from the request starting to the last body byte read.
+	TTLB time.Duration `json:"ttlb"`
+	// ConnReused is true if the final response came over a reused keep-alive connection.
+	ConnReused bool `json:"conn_reused"`
+}
+
+// TimingBreakdown summarizes the request phases of a run.
+type TimingBreakdown struct {
+	// DNS, Connect and TLS cover only the requests that went through the phase.
+	DNS      PhaseStats `json:"dns"`
+	Connect  PhaseStats `json:"connect"`
+	TLS      PhaseStats `json:"tls"`
+	TTFB     PhaseStats `json:"ttfb"`
+	Transfer PhaseStats `json:"transfer"`
+	// TTLB is the whole request, from start to the last body byte.
+	TTLB PhaseStats `json:"ttlb"`
+	// ReusedConnections and NewConnections count the requests by how they were sent.
+	ReusedConnections int64 `json:"reused_connections"`
+	NewConnections    int64 `json:"new_connections"`
+}
+
+// TimeBucket holds the requests completed during one interval of a run, so
+// changes in throughput, errors and latency over the run can be seen.
+type TimeBucket struct {
+	// Start is when the interval began.
+	Start time.Time `json:"start"`
+	// Failures counts the failed requests by class.
+	Failures map[ErrorClass]int64 `json:"failures,omitempty"`
+	// Offset is how far into the run the interval began, counting resumed runs.
+	Offset time.Duration `json:"offset"`
+	// Duration is the interval's length; the last one may be cut short.
+	Duration time.Duration `json:"duration"`
+	// P50, P95 and P99 are the response time percentiles of the interval's requests.
+	P50 time.Duration `json:"p50"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+	// Requests counts the requests completed, Successes those with a valid response.
+	Requests  int64 `json:"requests"`
+	Successes int64 `json:"successes"`
+	// BytesReceived is the response body bytes downloaded.
+	BytesReceived int64 `json:"bytes_received"`
+	// InFlight is how many requests were in progress when the interval ended.
+	InFlight int64 `json:"in_flight"`
+}
+
+// FailureCount returns the number of failed requests of every class.
+func (b TimeBucket) FailureCount() int64 {
+	var total int64
+	for _, count := range b.Failures {
+		total += count
+	}
+	return total
+}
+
+// PhaseStats are the latency statistics of one request phase.
+type PhaseStats struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+	// Count is the number of requests the statistics cover.
+	Count int `json:"count"`
 }
 
 // PerformanceTarget represents the result of validating a performance criterion.
@@ -137,6 +431,9 @@ type PerformanceTargets struct {
 	SuccessRate float64 `json:"success_rate"`
 	// ErrorRate is the maximum acceptable error percentage (0-100).
 	ErrorRate float64 `json:"error_rate"`
+	// ErrorClasses caps the percentage of requests (0-100) failing with each
+	// listed class; unlisted classes are not checked.
+	ErrorClasses map[ErrorClass]float64 `json:"error_classes,omitempty"`
 }
 
 // DefaultPerformanceTargets returns sensible default performance targets
@@ -156,9 +453,12 @@ const (
 	AddURLSuccess       = "success"
 	AddURLDuplicate     = "duplicate"
 	AddURLQueueFull     = "queue_full"
+	AddURLSpilled       = "spilled"
 	AddURLDepthExceeded = "depth_exceeded"
 	AddURLInvalidHost   = "invalid_host"
 	AddURLParseError    = "parse_error"
+	AddURLTrap          = "trap"
+	AddURLDenied        = "denied"
 )
 
 // AddURLResult represents the result of attempting to add a URL to the crawl queue.
@@ -167,6 +467,254 @@ type AddURLResult struct {
 	// Added is true if the URL was successfully added to the queue.
 	Added bool
 	// Reason explains why the URL was or wasn't added.
-	// Values: "success", "duplicate", "queue_full", "depth_exceeded", "invalid_host", "parse_error"
+	// Values: "success", "spilled", "duplicate", "queue_full", "depth_exceeded", "invalid_host", "parse_error", "trap", "denied"
 	Reason string
+	// URL is the normalized target, set whenever the link was in scope (even if not queued).
+	URL string
+}
+
+// CrawlCheckpoint is the persisted state of an interrupted run, used to resume it.
+// It is written periodically to the checkpoint file as JSON.
+type CrawlCheckpoint struct {
+	// SavedAt is when the checkpoint was written.
+	SavedAt time.Time `json:"saved_at"`
+	// Results holds per-URL results and counters collected so far.
+	Results *TestResults `json:"results"`
+	// Robots holds the robots.txt rules in effect (nil when robots.txt is ignored).
+	Robots *RobotsState `json:"robots,omitempty"`
+	// RobotsOrigins holds the robots.txt rules of other origins crawled, keyed by origin.
+	RobotsOrigins map[string]RobotsState `json:"robots_origins,omitempty"`
+	// Graph holds the link graph collected so far (nil when no graph export was requested).
+	Graph *LinkGraph `json:"graph,omitempty"`
+	// BaseURL is the base URL of the checkpointed run; resuming requires the same URL.
+	BaseURL string `json:"base_url"`
+	// Crawl holds the frontier, visited set and crawler counters.
+	Crawl CrawlState `json:"crawl"`
+	// Elapsed is the total run time across all sessions up to this checkpoint.
+	Elapsed time.Duration `json:"elapsed"`
+	// Version is the checkpoint format version.
+	Version int `json:"version"`
+	// DryRun records whether the run was a discovery-only crawl.
+	DryRun bool `json:"dry_run"`
+}
+
+// CrawlState is a snapshot of the crawler's frontier and deduplication state.
+type CrawlState struct {
+	// Frontier lists URLs discovered but not yet processed, including in-flight ones.
+	// URLs spilled to disk are referenced by Spilled instead.
+	Frontier []URLTask `json:"frontier"`
+	// Spilled references the frontier URLs waiting in disk overflow files.
+	Spilled []SpillSegment `json:"spilled,omitempty"`
+	// Visited lists normalized URLs already discovered (memory visited set).
+	Visited []string `json:"visited,omitempty"`
+	// VisitedBloom holds the Bloom filter bits (bloom visited set).
+	VisitedBloom []uint64 `json:"visited_bloom,omitempty"`
+	// Referrers maps discovered URLs to the pages linking to them (link-check mode).
+	Referrers map[string]ReferrerList `json:"referrers,omitempty"`
+	// Traps holds trap detection counters and suppressed URLs.
+	Traps *TrapState `json:"traps,omitempty"`
+	// DeniedPaths lists host+paths denylisted during the run because they ended the session.
+	DeniedPaths []string `json:"denied_paths,omitempty"`
+	// DeniedLinks lists the links not followed so far, grouped by denylist rule.
+	DeniedLinks []DeniedLink `json:"denied_links,omitempty"`
+	// Discovered is the count of unique URLs discovered.
+	Discovered int `json:"discovered"`
+	// Dropped is the count of URLs dropped due to queue overflow.
+	Dropped int `json:"dropped"`
+	// NormalizedDuplicates is the count of links deduplicated only after normalization.
+	NormalizedDuplicates int `json:"normalized_duplicates"`
+	// CanonicalDuplicates is the count of pages deduplicated by rel=canonical.
+	CanonicalDuplicates int `json:"canonical_duplicates"`
+}
+
+// FrontierSize returns the number of frontier URLs, including spilled ones.
+func (s CrawlState) FrontierSize() int {
+	size := len(s.Frontier)
+	for _, segment := range s.Spilled {
+		size += segment.Count
+	}
+	return size
+}
+
+// SpillSegment references the unread tasks of a frontier overflow file.
+type SpillSegment struct {
+	// Path is the overflow file, holding one JSON-encoded URL task per line.
+	Path string `json:"path"`
+	// Offset is the byte offset of the first unread task.
+	Offset int64 `json:"offset"`
+	// End is the byte offset where the checkpointed tasks end; later lines are not part of it.
+	End int64 `json:"end"`
+	// Count is the number of tasks between Offset and End.
+	Count int `json:"count"`
+}
+
+// RobotsReport summarizes how robots.txt shaped a run.
+type RobotsReport struct {
+	// Disallow lists the disallowed path patterns in effect.
+	Disallow []string `json:"disallow,omitempty"`
+	// Allow lists the allowed path patterns in effect.
+	Allow []string `json:"allow,omitempty"`
+	// BlockedSample lists the first URLs blocked, up to a fixed cap.
+	BlockedSample []string `json:"blocked_sample,omitempty"`
+	// CrawlDelay is the Crawl-delay enforced between requests, as a human-readable string.
+	CrawlDelay string `json:"crawl_delay,omitempty"`
+	// BlockedURLs counts the URLs not requested because robots.txt disallows them.
+	BlockedURLs int64 `json:"blocked_urls"`
+	// Found is true if robots.txt was found and parsed.
+	Found bool `json:"found"`
+	// Unreachable is true if robots.txt could not be fetched, so every path was disallowed (RFC 9309).
+	Unreachable bool `json:"unreachable,omitempty"`
+	// Origins lists the robots.txt rules of other origins crawled, such as the
+	// base host over the other scheme, sorted by origin. The fields above are the base URL's.
+	Origins []RobotsOrigin `json:"origins,omitempty"`
+}
+
+// RobotsOrigin is the robots.txt rules in effect for one origin (scheme and host).
+type RobotsOrigin struct {
+	// Origin is the scheme and host the rules apply to.
+	Origin string `json:"origin"`
+	// Disallow lists the disallowed path patterns in effect.
+	Disallow []string `json:"disallow,omitempty"`
+	// Allow lists the allowed path patterns in effect.
+	Allow []string `json:"allow,omitempty"`
+	// CrawlDelay is the Crawl-delay enforced between requests, as a human-readable string.
+	CrawlDelay string `json:"crawl_delay,omitempty"`
+	// Found is true if robots.txt was found and parsed.
+	Found bool `json:"found"`
+	// Unreachable is true if robots.txt could not be fetched, so every path was disallowed (RFC 9309).
+	Unreachable bool `json:"unreachable,omitempty"`
+}
+
+// RobotsState is a snapshot of parsed robots.txt rules.
+type RobotsState struct {
+	// Disallow lists the disallowed path patterns.
+	Disallow []string `json:"disallow"`
+	// Allow lists the allowed path patterns.
+	Allow []string `json:"allow"`
+	// CrawlDelay is the Crawl-delay directive.
+	CrawlDelay time.Duration `json:"crawl_delay"`
+	// Found is true if robots.txt was found and parsed.
+	Found bool `json:"found"`
+	// Unreachable is true if robots.txt could not be fetched, disallowing every path.
+	Unreachable bool `json:"unreachable,omitempty"`
+}
+
+// LinkGraph is the directed graph of crawled pages and the links between them.
+type LinkGraph struct {
+	// Nodes lists every page seen, crawled or only linked to, sorted by URL.
+	Nodes []GraphNode `json:"nodes"`
+	// Edges lists links between pages, sorted by source then target.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a page in the link graph.
+type GraphNode struct {
+	// URL is the normalized page URL.
+	URL string `json:"url"`
+	// Depth is the crawl depth the page was discovered at.
+	Depth int `json:"depth"`
+	// StatusCode is the page's HTTP status (0 if it was not requested).
+	StatusCode int `json:"status_code"`
+	// Crawled is true if the page's links were extracted.
+	Crawled bool `json:"crawled"`
+}
+
+// GraphEdge is a link from one page to another.
+type GraphEdge struct {
+	// From is the linking page.
+	From string `json:"from"`
+	// To is the linked page.
+	To string `json:"to"`
+	// Count is how many times From links to To.
+	Count int `json:"count"`
+}
+
+// LinkGraphStats summarizes a link graph.
+type LinkGraphStats struct {
+	// PagesByClickDepth counts pages by the fewest clicks needed to reach them from the start page.
+	PagesByClickDepth map[int]int `json:"pages_by_click_depth"`
+	// MostLinked lists the pages linked from the most other pages, most-linked first.
+	MostLinked []PageLinks `json:"most_linked"`
+	// DeadEnds lists crawled pages with no outgoing links to other pages.
+	DeadEnds []string `json:"dead_ends"`
+	// Pages is the number of nodes in the graph.
+	Pages int `json:"pages"`
+	// Links is the number of distinct page-to-page links.
+	Links int `json:"links"`
+	// MaxClickDepth is the largest click depth of any reachable page.
+	MaxClickDepth int `json:"max_click_depth"`
+	// Unreachable counts pages that cannot be reached from the start page by following links.
+	Unreachable int `json:"unreachable"`
+}
+
+// PageLinks is a page with its count of inbound links.
+type PageLinks struct {
+	// URL is the page URL.
+	URL string `json:"url"`
+	// Inbound is the number of distinct other pages linking to it.
+	Inbound int `json:"inbound"`
+}
+
+// Crawler trap reasons recorded in CrawlTrap.Reason.
+const (
+	TrapPathVariants      = "path_variants"
+	TrapParamCombinations = "param_combinations"
+	TrapRepeatingSegments = "repeating_segments"
+	TrapCalendar          = "calendar"
+	TrapSessionID         = "session_id"
+)
+
+// CrawlTrap groups the URLs suppressed by one trap heuristic on one pattern.
+type CrawlTrap struct {
+	// Reason is the heuristic: "path_variants", "param_combinations",
+	// "repeating_segments", "calendar" or "session_id".
+	Reason string `json:"reason"`
+	// Pattern is the host and path the trap was detected on, with digit runs shown
+	// as {n} for path-shape traps, or the session parameter name for session IDs.
+	Pattern string `json:"pattern"`
+	// Examples lists a few of the suppressed URLs.
+	Examples []string `json:"examples"`
+	// Suppressed is the number of URLs suppressed. For session IDs it counts links
+	// whose ID was removed; the URL without it is still crawled.
+	Suppressed int `json:"suppressed"`
+}
+
+// TrapState is a snapshot of trap detection state for checkpointing.
+type TrapState struct {
+	// PathVariants counts the query-string variants queued per host and path.
+	PathVariants map[string]int `json:"path_variants"`
+	// ParamCombinations lists the query parameter name sets queued per host and path.
+	ParamCombinations map[string][]string `json:"param_combinations"`
+	// Suppressed lists the traps found so far.
+	Suppressed []CrawlTrap `json:"suppressed"`
+}
+
+// NofollowSkips counts links not followed, by the directive that excluded them.
+// Counters are updated atomically while a test runs.
+type NofollowSkips struct {
+	// MetaRobots counts links on pages with a <meta name="robots"> nofollow directive.
+	MetaRobots int64 `json:"meta_robots"`
+	// XRobotsTag counts links on pages sent with an X-Robots-Tag nofollow header.
+	XRobotsTag int64 `json:"x_robots_tag"`
+	// RelNofollow counts links marked rel="nofollow".
+	RelNofollow int64 `json:"rel_nofollow"`
+}
+
+// Total returns the number of links skipped for any nofollow directive.
+func (s NofollowSkips) Total() int64 {
+	return s.MetaRobots + s.XRobotsTag + s.RelNofollow
+}
+
+// DenyRuleSessionInvalidated is the DeniedLink rule for links denylisted during
+// a run because following them ended the authenticated session.
+const DenyRuleSessionInvalidated = "session_invalidated"
+
+// DeniedLink groups the links not followed because they matched one denylist rule.
+type DeniedLink struct {
+	// Rule is the matching denylist pattern, or "session_invalidated".
+	Rule string `json:"rule"`
+	// Examples lists a few of the denied URLs.
+	Examples []string `json:"examples"`
+	// Links is the number of links denied by the rule.
+	Links int `json:"links"`
 }
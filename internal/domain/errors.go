@@ -0,0 +1,98 @@
+package domain
+
+// ErrorClass classifies why a request failed, so failures can be counted and
+// compared by cause rather than by message.
+type ErrorClass string
+
+// Error classes, from the network up.
+const (
+	// ErrorClassDNS is a host name that could not be resolved.
+	ErrorClassDNS ErrorClass = "dns"
+	// ErrorClassConnectionRefused is a connection the server refused.
+	ErrorClassConnectionRefused ErrorClass = "connection_refused"
+	// ErrorClassConnectionReset is a connection closed or reset by the server mid-request.
+	ErrorClassConnectionReset ErrorClass = "connection_reset"
+	// ErrorClassTLS is a failed TLS handshake or an untrusted certificate.
+	ErrorClassTLS ErrorClass = "tls"
+	// ErrorClassTimeoutDNS to ErrorClassTimeoutBody are timeouts, by the phase the
+	// request had reached: resolving, connecting (or waiting for a pooled
+	// connection), the TLS handshake, waiting for the response, or reading its body.
+	ErrorClassTimeoutDNS     ErrorClass = "timeout_dns"
+	ErrorClassTimeoutConnect ErrorClass = "timeout_connect"
+	ErrorClassTimeoutTLS     ErrorClass = "timeout_tls"
+	ErrorClassTimeoutTTFB    ErrorClass = "timeout_ttfb"
+	ErrorClassTimeoutBody    ErrorClass = "timeout_body"
+	// ErrorClassBodyRead is a response whose body could not be read to the end.
+	ErrorClassBodyRead ErrorClass = "body_read"
+	// ErrorClassHTTP4xx and ErrorClassHTTP5xx are client and server error responses.
+	ErrorClassHTTP4xx ErrorClass = "http_4xx"
+	ErrorClassHTTP5xx ErrorClass = "http_5xx"
+	// ErrorClassHTTPOther is any other status the success criteria reject,
+	// such as a 1xx, or a 2xx or 3xx outside the configured codes.
+	ErrorClassHTTPOther ErrorClass = "http_other"
+	// ErrorClassAssertion is a response whose body or headers failed the success criteria.
+	ErrorClassAssertion ErrorClass = "assertion"
+	// ErrorClassCancelled is a request cut short because the run ended or was interrupted.
+	ErrorClassCancelled ErrorClass = "cancelled"
+	// ErrorClassOther is any other failure, such as a request that could not be built.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// ErrorClasses lists every error class, in the order reports show them.
+var ErrorClasses = []ErrorClass{
+	ErrorClassDNS,
+	ErrorClassConnectionRefused,
+	ErrorClassConnectionReset,
+	ErrorClassTLS,
+	ErrorClassTimeoutDNS,
+	ErrorClassTimeoutConnect,
+	ErrorClassTimeoutTLS,
+	ErrorClassTimeoutTTFB,
+	ErrorClassTimeoutBody,
+	ErrorClassBodyRead,
+	ErrorClassHTTP4xx,
+	ErrorClassHTTP5xx,
+	ErrorClassHTTPOther,
+	ErrorClassAssertion,
+	ErrorClassCancelled,
+	ErrorClassOther,
+}
+
+// Valid reports whether c is one of the ErrorClasses.
+func (c ErrorClass) Valid() bool {
+	for _, class := range ErrorClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusErrorClass returns the error class of a response status, or "" for
+// a 2xx or 3xx status.
+func StatusErrorClass(statusCode int) ErrorClass {
+	switch {
+	case statusCode >= 500:
+		return ErrorClassHTTP5xx
+	case statusCode >= 400:
+		return ErrorClassHTTP4xx
+	case statusCode >= 200 && statusCode < 400:
+		return ""
+	default:
+		return ErrorClassHTTPOther
+	}
+}
+
+// FailureClass returns the error class of a failed request, or "" if it succeeded.
+func (v URLValidation) FailureClass() ErrorClass {
+	if v.IsValid {
+		return ""
+	}
+	if v.ErrorClass != "" {
+		return v.ErrorClass
+	}
+	if class := StatusErrorClass(v.StatusCode); class != "" && v.StatusCode != 0 {
+		return class
+	}
+	return ErrorClassOther
+}
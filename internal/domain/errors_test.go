@@ -0,0 +1,50 @@
+package domain
+
+import "testing"
+
+func TestStatusErrorClass(t *testing.T) {
+	tests := []struct {
+		want   ErrorClass
+		status int
+	}{
+		{status: 200, want: ""},
+		{status: 304, want: ""},
+		{status: 404, want: ErrorClassHTTP4xx},
+		{status: 429, want: ErrorClassHTTP4xx},
+		{status: 500, want: ErrorClassHTTP5xx},
+		{status: 101, want: ErrorClassHTTPOther},
+	}
+	for _, tt := range tests {
+		if got := StatusErrorClass(tt.status); got != tt.want {
+			t.Errorf("StatusErrorClass(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestURLValidation_FailureClass(t *testing.T) {
+	tests := []struct {
+		name       string
+		want       ErrorClass
+		validation URLValidation
+	}{
+		{"success", "", URLValidation{StatusCode: 200, IsValid: true}},
+		{"error status", ErrorClassHTTP5xx, URLValidation{StatusCode: 503}},
+		{"explicit class", ErrorClassBodyRead, URLValidation{StatusCode: 200, ErrorClass: ErrorClassBodyRead}},
+		{"invalid 2xx without class", ErrorClassOther, URLValidation{StatusCode: 200}},
+		{"no response", ErrorClassOther, URLValidation{}},
+	}
+	for _, tt := range tests {
+		if got := tt.validation.FailureClass(); got != tt.want {
+			t.Errorf("%s: FailureClass() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestErrorClass_Valid(t *testing.T) {
+	if !ErrorClassTimeoutTTFB.Valid() {
+		t.Error("Expected timeout_ttfb to be valid")
+	}
+	if ErrorClass("transport").Valid() {
+		t.Error("Expected an unknown class to be invalid")
+	}
+}
@@ -1,7 +1,10 @@
 // Package domain defines core domain types and interfaces for the load testing tool.
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // URLCrawler defines the interface for URL discovery and link extraction.
 // Implementations handle URL validation, deduplication, and queue management.
@@ -9,22 +12,91 @@ type URLCrawler interface {
 	// ExtractLinks parses HTML body and returns valid links found.
 	ExtractLinks(body string) []string
 
+	// ExtractLinksWithText parses HTML body and returns valid links with their anchor text.
+	ExtractLinksWithText(body string) []Link
+
+	// ExtractJSONLinks parses a JSON body and returns its HAL and JSON:API links.
+	ExtractJSONLinks(body string) []Link
+
+	// ExtractFeedLinks returns the RSS and Atom feeds an HTML page advertises.
+	ExtractFeedLinks(body string) []Link
+	// ExtractFeedItems parses an RSS or Atom feed and returns its item links.
+	ExtractFeedItems(body string) []Link
+	// ExtractFormLinks returns the requests generated from a page's HTML forms.
+	ExtractFormLinks(body string) []Link
+	// ExtractScriptLinks returns the <script src> URLs of an HTML page.
+	ExtractScriptLinks(body string) []Link
+	// ExtractJSEndpoints returns API endpoint candidates found in a JavaScript file.
+	ExtractJSEndpoints(body string) []Link
+	// ParseLinkHeader returns the navigational links in Link header values.
+	ParseLinkHeader(values []string) []Link
+
 	// AddURL adds a URL to the discovery queue if valid and not already discovered.
 	// Returns an AddURLResult with the outcome and reason.
 	AddURL(rawURL string, depth int, queue chan<- URLTask) AddURLResult
 
+	// AddLink adds a link found on a page, resolving it against the page and
+	// recording the page as a referrer of the target.
+	AddLink(link Link, depth int, queue chan<- URLTask) AddURLResult
+
+	// GetReferrers returns the pages linking to a discovered URL (requires referrer tracking).
+	GetReferrers(taskURL string) ReferrerList
+
+	// DenyURL stops links to a URL's path from being followed for the rest of the run.
+	DenyURL(taskURL string)
+
+	// GetDeniedLinks returns the links not followed because of the denylist, grouped by rule.
+	GetDeniedLinks() []DeniedLink
+
+	// GetCrawlTraps returns the URLs suppressed by crawler trap detection, grouped by trap.
+	GetCrawlTraps() []CrawlTrap
+
 	// GetDiscoveredCount returns the total number of unique URLs discovered.
 	GetDiscoveredCount() int
 
 	// GetDroppedCount returns the number of URLs dropped due to queue overflow.
 	GetDroppedCount() int
+
+	// ExtractCanonical returns the href of the <link rel="canonical"> tag in an HTML body, if any.
+	ExtractCanonical(body string) string
+
+	// RegisterCanonical records that pageURL declared canonicalURL as its canonical form.
+	// Returns true if the canonical URL had already been discovered, meaning the page is a duplicate.
+	RegisterCanonical(pageURL, canonicalURL string) bool
+
+	// GetNormalizedDuplicateCount returns the number of links deduplicated only by normalization.
+	GetNormalizedDuplicateCount() int
+
+	// GetCanonicalDuplicateCount returns the number of pages deduplicated by rel=canonical.
+	GetCanonicalDuplicateCount() int
+
+	// DrainSpilled moves URLs from the disk overflow back into the queue while it has room.
+	// Returns the number of URLs moved.
+	DrainSpilled(ctx context.Context, queue chan<- URLTask) int
+
+	// GetSpilledCount returns the number of URLs waiting in the disk overflow.
+	GetSpilledCount() int
+
+	// Close releases resources such as frontier spill files.
+	Close() error
+
+	// Complete marks a queued task as processed so it is no longer part of the frontier.
+	Complete(task URLTask)
+
+	// Snapshot returns the frontier and deduplication state for checkpointing.
+	// Callers must ensure no URLs are added or completed concurrently.
+	Snapshot() (CrawlState, error)
+
+	// Restore loads a snapshot taken by Snapshot and queues its frontier.
+	Restore(state CrawlState, queue chan<- URLTask) error
 }
 
 // RobotsChecker defines the interface for robots.txt compliance checking.
 // Implementations parse robots.txt and enforce path-based access rules.
 type RobotsChecker interface {
 	// FetchAndParse fetches and parses robots.txt from the given base URL.
-	// Returns an error if the robots.txt cannot be fetched or parsed.
+	// Returns an error if robots.txt is unreachable (a 5xx status or network
+	// failure), in which case every path is disallowed.
 	FetchAndParse(ctx context.Context, baseURL string) error
 
 	// IsAllowed returns true if the given URL path is allowed by robots.txt rules.
@@ -32,6 +104,18 @@ type RobotsChecker interface {
 
 	// RobotsTxtFound returns true if robots.txt was found and parsed successfully.
 	RobotsTxtFound() bool
+
+	// Unreachable returns true if robots.txt could not be fetched, so every path is disallowed.
+	Unreachable() bool
+
+	// GetCrawlDelay returns the Crawl-delay for our user agent (0 = none).
+	GetCrawlDelay() time.Duration
+
+	// Snapshot returns the parsed rules for checkpointing.
+	Snapshot() RobotsState
+
+	// Restore replaces the parsed rules with a snapshot, skipping the fetch.
+	Restore(state RobotsState)
 }
 
 // RateLimiter defines the interface for rate limiting concurrent requests.
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+// DefaultLatencySamples is the default number of raw response time samples kept.
+const DefaultLatencySamples = 10000
+
+// LatencyBucketBounds are the upper bounds of the latency distribution buckets.
+var LatencyBucketBounds = []time.Duration{
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// LatencyStats summarize a latency distribution. Count, Min, Max and Mean are exact;
+// the percentiles and StdDev are accurate to the histogram precision.
+type LatencyStats struct {
+	// Distribution counts the measurements per LatencyBucketBounds range, from the
+	// first to the last non-empty range.
+	Distribution []LatencyBucket `json:"distribution"`
+	Min          time.Duration   `json:"min"`
+	Max          time.Duration   `json:"max"`
+	Mean         time.Duration   `json:"mean"`
+	StdDev       time.Duration   `json:"stddev"`
+	P50          time.Duration   `json:"p50"`
+	P75          time.Duration   `json:"p75"`
+	P90          time.Duration   `json:"p90"`
+	P95          time.Duration   `json:"p95"`
+	P99          time.Duration   `json:"p99"`
+	P999         time.Duration   `json:"p99_9"`
+	// Count is the number of measurements summarized.
+	Count int64 `json:"count"`
+}
+
+// LatencyBucket counts the measurements above the previous bucket's bound, up to UpperBound.
+type LatencyBucket struct {
+	// UpperBound is the bucket's inclusive upper bound; 0 for the open-ended last bucket.
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"`
+}
+
+// SummarizeLatency computes the statistics of a latency histogram. It is the one
+// place latency statistics are computed; reports and validation read its results.
+func SummarizeLatency(h *histogram.Histogram) LatencyStats {
+	if h.Count() == 0 {
+		return LatencyStats{}
+	}
+
+	counts := h.Distribution(LatencyBucketBounds)
+	first := slices.IndexFunc(counts, func(count int64) bool { return count > 0 })
+	last := len(counts) - 1
+	for counts[last] == 0 {
+		last--
+	}
+	distribution := make([]LatencyBucket, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		bucket := LatencyBucket{Count: counts[i]}
+		if i < len(LatencyBucketBounds) {
+			bucket.UpperBound = LatencyBucketBounds[i]
+		}
+		distribution = append(distribution, bucket)
+	}
+
+	return LatencyStats{
+		Count:        h.Count(),
+		Min:          h.Min(),
+		Max:          h.Max(),
+		Mean:         h.Mean(),
+		StdDev:       h.StdDev(),
+		P50:          h.Quantile(0.50),
+		P75:          h.Quantile(0.75),
+		P90:          h.Quantile(0.90),
+		P95:          h.Quantile(0.95),
+		P99:          h.Quantile(0.99),
+		P999:         h.Quantile(0.999),
+		Distribution: distribution,
+	}
+}
+
+// LatencyHistograms hold the latency distributions of a run. Every latency statistic
+// is computed from them, so they are complete even when raw samples are not kept.
+// They are saved with results so that runs can be resumed and merged.
+type LatencyHistograms struct {
+	// ResponseTime covers every recorded request.
+	ResponseTime *histogram.Histogram `json:"response_time"`
+	// DNS, Connect and TLS cover only the traced requests that went through the phase.
+	DNS      *histogram.Histogram `json:"dns"`
+	Connect  *histogram.Histogram `json:"connect"`
+	TLS      *histogram.Histogram `json:"tls"`
+	TTFB     *histogram.Histogram `json:"ttfb"`
+	Transfer *histogram.Histogram `json:"transfer"`
+	TTLB     *histogram.Histogram `json:"ttlb"`
+	// ReusedConnections and NewConnections count the traced requests by how they were sent.
+	ReusedConnections int64 `json:"reused_connections"`
+	NewConnections    int64 `json:"new_connections"`
+}
+
+// NewLatencyHistograms returns empty histograms keeping precision significant digits.
+func NewLatencyHistograms(precision int) *LatencyHistograms {
+	return &LatencyHistograms{
+		ResponseTime: histogram.New(precision),
+		DNS:          histogram.New(precision),
+		Connect:      histogram.New(precision),
+		TLS:          histogram.New(precision),
+		TTFB:         histogram.New(precision),
+		Transfer:     histogram.New(precision),
+		TTLB:         histogram.New(precision),
+	}
+}
+
+// Record adds a response time measurement and its phase timing, if it was traced.
+func (l *LatencyHistograms) Record(entry ResponseTimeEntry) {
+	l.ResponseTime.Record(entry.ResponseTime)
+
+	timing := entry.Timing
+	if timing == nil {
+		return
+	}
+	if timing.ConnReused {
+		l.ReusedConnections++
+	} else {
+		l.NewConnections++
+	}
+	if timing.DNS > 0 {
+		l.DNS.Record(timing.DNS)
+	}
+	if timing.Connect > 0 {
+		l.Connect.Record(timing.Connect)
+	}
+	if timing.TLS > 0 {
+		l.TLS.Record(timing.TLS)
+	}
+	l.TTFB.Record(timing.TTFB)
+	l.Transfer.Record(timing.Transfer)
+	l.TTLB.Record(timing.TTLB)
+}
+
+// Merge adds the measurements of other, such as those of another run.
+func (l *LatencyHistograms) Merge(other *LatencyHistograms) {
+	if other == nil {
+		return
+	}
+	l.ResponseTime.Merge(other.ResponseTime)
+	l.DNS.Merge(other.DNS)
+	l.Connect.Merge(other.Connect)
+	l.TLS.Merge(other.TLS)
+	l.TTFB.Merge(other.TTFB)
+	l.Transfer.Merge(other.Transfer)
+	l.TTLB.Merge(other.TTLB)
+	l.ReusedConnections += other.ReusedConnections
+	l.NewConnections += other.NewConnections
+}
+
+// LatencyHistograms returns the results' histograms, or, for results recorded
+// without them, histograms built from the raw response time samples.
+func (r *TestResults) LatencyHistograms() *LatencyHistograms {
+	if r.Histograms != nil {
+		return r.Histograms
+	}
+	histograms := NewLatencyHistograms(histogram.DefaultPrecision)
+	for _, entry := range r.ResponseTimes {
+		histograms.Record(entry)
+	}
+	return histograms
+}
+
+// LatencyStats returns the results' response time statistics, computing them from
+// the latency histograms when the results do not carry them yet.
+func (r *TestResults) LatencyStats() LatencyStats {
+	if r.Latency != nil {
+		return *r.Latency
+	}
+	return SummarizeLatency(r.LatencyHistograms().ResponseTime)
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+func TestLatencyHistograms_Record(t *testing.T) {
+	histograms := NewLatencyHistograms(3)
+	histograms.Record(ResponseTimeEntry{ResponseTime: 10 * time.Millisecond})
+	histograms.Record(ResponseTimeEntry{
+		ResponseTime: 20 * time.Millisecond,
+		Timing:       &RequestTiming{DNS: time.Millisecond, Connect: 2 * time.Millisecond, TTFB: 15 * time.Millisecond},
+	})
+	histograms.Record(ResponseTimeEntry{
+		ResponseTime: 30 * time.Millisecond,
+		Timing:       &RequestTiming{TTFB: 25 * time.Millisecond, ConnReused: true},
+	})
+
+	if got := histograms.ResponseTime.Count(); got != 3 {
+		t.Errorf("Expected 3 response times, got %d", got)
+	}
+	if histograms.TTFB.Count() != 2 || histograms.DNS.Count() != 1 || histograms.TLS.Count() != 0 {
+		t.Errorf("Expected 2 TTFB, 1 DNS and 0 TLS measurements, got %d, %d and %d",
+			histograms.TTFB.Count(), histograms.DNS.Count(), histograms.TLS.Count())
+	}
+	if histograms.ReusedConnections != 1 || histograms.NewConnections != 1 {
+		t.Errorf("Expected 1 reused and 1 new connection, got %d and %d", histograms.ReusedConnections, histograms.NewConnections)
+	}
+}
+
+func TestLatencyHistograms_Merge(t *testing.T) {
+	first, second := NewLatencyHistograms(3), NewLatencyHistograms(3)
+	first.Record(ResponseTimeEntry{ResponseTime: time.Second, Timing: &RequestTiming{TTFB: time.Second}})
+	second.Record(ResponseTimeEntry{ResponseTime: 3 * time.Second, Timing: &RequestTiming{TTFB: time.Second, ConnReused: true}})
+
+	first.Merge(second)
+	first.Merge(nil)
+
+	if first.ResponseTime.Count() != 2 || first.ResponseTime.Mean() != 2*time.Second {
+		t.Errorf("Expected 2 response times averaging 2s, got %d averaging %v", first.ResponseTime.Count(), first.ResponseTime.Mean())
+	}
+	if first.ReusedConnections != 1 || first.NewConnections != 1 {
+		t.Errorf("Expected connection counts to add up, got %d reused and %d new", first.ReusedConnections, first.NewConnections)
+	}
+}
+
+func TestTestResults_LatencyHistograms(t *testing.T) {
+	results := &TestResults{ResponseTimes: []ResponseTimeEntry{{ResponseTime: time.Second}, {ResponseTime: 2 * time.Second}}}
+	if got := results.LatencyHistograms().ResponseTime.Count(); got != 2 {
+		t.Errorf("Expected histograms built from the 2 raw samples, got %d measurements", got)
+	}
+
+	results.Histograms = NewLatencyHistograms(3)
+	if got := results.LatencyHistograms(); got != results.Histograms {
+		t.Error("Expected the recorded histograms to be returned")
+	}
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	h := histogram.New(3)
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	h.Record(time.Minute)
+
+	stats := SummarizeLatency(h)
+	if stats.Count != 1001 || stats.Min != time.Millisecond || stats.Max != time.Minute {
+		t.Errorf("Expected 1001 measurements from 1ms to 1m, got %d from %v to %v", stats.Count, stats.Min, stats.Max)
+	}
+	percentiles := []struct {
+		name      string
+		got, want time.Duration
+	}{
+		{"p50", stats.P50, 501 * time.Millisecond},
+		{"p75", stats.P75, 751 * time.Millisecond},
+		{"p90", stats.P90, 901 * time.Millisecond},
+		{"p95", stats.P95, 951 * time.Millisecond},
+		{"p99", stats.P99, 991 * time.Millisecond},
+		{"p99.9", stats.P999, 1000 * time.Millisecond},
+	}
+	for _, p := range percentiles {
+		if diff := p.got - p.want; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("Expected %s near %v, got %v", p.name, p.want, p.got)
+		}
+	}
+	if stats.StdDev <= 0 {
+		t.Errorf("Expected a positive standard deviation, got %v", stats.StdDev)
+	}
+
+	// 1ms falls in the first bucket; buckets run up to the open-ended one holding 1m
+	if first := stats.Distribution[0]; first.UpperBound != time.Millisecond || first.Count != 1 {
+		t.Errorf("Expected the first bucket to hold 1 measurement up to 1ms, got %d up to %v", first.Count, first.UpperBound)
+	}
+	if last := stats.Distribution[len(stats.Distribution)-1]; last.UpperBound != 0 || last.Count != 1 {
+		t.Errorf("Expected the open-ended last bucket to hold 1 measurement, got %d up to %v", last.Count, last.UpperBound)
+	}
+	var total int64
+	for _, bucket := range stats.Distribution {
+		total += bucket.Count
+	}
+	if total != stats.Count {
+		t.Errorf("Expected the distribution to hold %d measurements, got %d", stats.Count, total)
+	}
+
+	if empty := SummarizeLatency(histogram.New(3)); empty.Count != 0 || empty.Distribution != nil {
+		t.Errorf("Expected empty statistics for an empty histogram, got %+v", empty)
+	}
+}
+
+func TestSummarizeLatency_TrimsEmptyBuckets(t *testing.T) {
+	h := histogram.New(3)
+	h.Record(30 * time.Millisecond)
+	h.Record(200 * time.Millisecond)
+
+	distribution := SummarizeLatency(h).Distribution
+	if len(distribution) != 3 || distribution[0].UpperBound != 50*time.Millisecond || distribution[2].UpperBound != 250*time.Millisecond {
+		t.Errorf("Expected buckets from 50ms to 250ms, got %+v", distribution)
+	}
+}
+
+func TestTestResults_LatencyStats(t *testing.T) {
+	results := &TestResults{ResponseTimes: []ResponseTimeEntry{{ResponseTime: time.Second}, {ResponseTime: 3 * time.Second}}}
+	if got := results.LatencyStats(); got.Count != 2 || got.Mean != 2*time.Second {
+		t.Errorf("Expected statistics of the 2 raw samples, got %d with mean %v", got.Count, got.Mean)
+	}
+
+	results.Latency = &LatencyStats{Count: 7}
+	if got := results.LatencyStats(); got.Count != 7 {
+		t.Errorf("Expected the recorded statistics to be returned, got count %d", got.Count)
+	}
+}
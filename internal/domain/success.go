@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SuccessCriteria decides which responses count as successful requests.
+type SuccessCriteria struct {
+	// Status lists the acceptable status codes as codes ("204"), ranges
+	// ("200-299") or classes ("2xx"). Empty accepts 2xx and 3xx.
+	Status []string `json:"status,omitempty"`
+	// BodyContains lists strings the response body must all contain.
+	BodyContains []string `json:"body_contains,omitempty"`
+	// BodyNotContains lists strings the response body must not contain,
+	// such as the marker of an error page served with a 200.
+	BodyNotContains []string `json:"body_not_contains,omitempty"`
+	// Headers maps response header names to a value the header must contain;
+	// an empty value only requires the header to be present.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// SuccessPolicy configures which responses count as successful requests, for
+// every request and per route. Requests without a response always fail.
+type SuccessPolicy struct {
+	SuccessCriteria
+	// Routes overrides the criteria of routes, keyed by route as shown in the
+	// route tables, e.g. "/users/:id" or "POST /search". Fields a route leaves
+	// empty are inherited from the criteria above.
+	Routes map[string]SuccessCriteria `json:"routes,omitempty"`
+}
+
+// StatusRange is an inclusive range of acceptable status codes.
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+// Contains reports whether code is within the range.
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// DefaultSuccessStatus is the status range accepted when none is configured.
+var DefaultSuccessStatus = []StatusRange{{Min: 200, Max: 399}}
+
+// ParseStatusRanges parses status codes, ranges and classes such as "204",
+// "200-299" and "2xx". No specs yields DefaultSuccessStatus.
+func ParseStatusRanges(specs []string) ([]StatusRange, error) {
+	if len(specs) == 0 {
+		return DefaultSuccessStatus, nil
+	}
+	ranges := make([]StatusRange, 0, len(specs))
+	for _, spec := range specs {
+		r, err := parseStatusRange(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseStatusRange parses a single status code, range or class.
+func parseStatusRange(spec string) (StatusRange, error) {
+	lower := strings.ToLower(spec)
+	if len(lower) == 3 && strings.HasSuffix(lower, "xx") && lower[0] >= '1' && lower[0] <= '5' {
+		base := int(lower[0]-'0') * 100
+		return StatusRange{Min: base, Max: base + 99}, nil
+	}
+
+	from, to, isRange := strings.Cut(spec, "-")
+	if !isRange {
+		to = from
+	}
+	low, lowErr := strconv.Atoi(strings.TrimSpace(from))
+	high, highErr := strconv.Atoi(strings.TrimSpace(to))
+	if lowErr != nil || highErr != nil {
+		return StatusRange{}, fmt.Errorf("invalid status %q: use a code, a range like 200-299 or a class like 2xx", spec)
+	}
+	if low < 100 || high > 599 {
+		return StatusRange{}, fmt.Errorf("invalid status %q: codes must be between 100 and 599", spec)
+	}
+	if low > high {
+		return StatusRange{}, fmt.Errorf("invalid status %q: range is reversed", spec)
+	}
+	return StatusRange{Min: low, Max: high}, nil
+}
+
+// Validate checks that status codes parse and assertions are not empty.
+func (c *SuccessCriteria) Validate() error {
+	if _, err := ParseStatusRanges(c.Status); err != nil {
+		return err
+	}
+	for _, s := range slices.Concat(c.BodyContains, c.BodyNotContains) {
+		if s == "" {
+			return fmt.Errorf("body assertions cannot be empty")
+		}
+	}
+	for name := range c.Headers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("header assertions need a header name")
+		}
+	}
+	return nil
+}
+
+// Validate checks the criteria and that routes are keyed by path.
+func (p *SuccessPolicy) Validate() error {
+	if err := p.SuccessCriteria.Validate(); err != nil {
+		return err
+	}
+	for route, criteria := range p.Routes {
+		path := route
+		if _, rest, ok := strings.Cut(route, " "); ok {
+			path = rest
+		}
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid route %q: must be a path, optionally after a method", route)
+		}
+		if err := criteria.Validate(); err != nil {
+			return fmt.Errorf("route %s: %w", route, err)
+		}
+	}
+	return nil
+}
+
+// ForRoute returns the criteria of route: its own fields, falling back to p's.
+func (p *SuccessPolicy) ForRoute(route string) SuccessCriteria {
+	criteria := p.SuccessCriteria
+	override, ok := p.Routes[route]
+	if !ok {
+		return criteria
+	}
+	if len(override.Status) > 0 {
+		criteria.Status = override.Status
+	}
+	if len(override.BodyContains) > 0 {
+		criteria.BodyContains = override.BodyContains
+	}
+	if len(override.BodyNotContains) > 0 {
+		criteria.BodyNotContains = override.BodyNotContains
+	}
+	if len(override.Headers) > 0 {
+		criteria.Headers = override.Headers
+	}
+	return criteria
+}
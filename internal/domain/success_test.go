@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatusRanges(t *testing.T) {
+	got, err := ParseStatusRanges([]string{"2xx", "304", " 400-404 "})
+	if err != nil {
+		t.Fatalf("ParseStatusRanges() error = %v", err)
+	}
+	want := []StatusRange{{200, 299}, {304, 304}, {400, 404}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStatusRanges() = %v, want %v", got, want)
+	}
+
+	if got, _ := ParseStatusRanges(nil); !reflect.DeepEqual(got, DefaultSuccessStatus) {
+		t.Errorf("Expected 2xx and 3xx by default, got %v", got)
+	}
+
+	for _, spec := range []string{"ok", "6xx", "99", "600", "299-200", "200-"} {
+		if _, err := ParseStatusRanges([]string{spec}); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestSuccessPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+		policy  SuccessPolicy
+	}{
+		{name: "empty"},
+		{name: "route with method", policy: SuccessPolicy{Routes: map[string]SuccessCriteria{"POST /search": {Status: []string{"201"}}}}},
+		{name: "empty body assertion", policy: SuccessPolicy{SuccessCriteria: SuccessCriteria{BodyContains: []string{""}}}, wantErr: "body assertions cannot be empty"},
+		{name: "route without path", policy: SuccessPolicy{Routes: map[string]SuccessCriteria{"users": {}}}, wantErr: `invalid route "users"`},
+		{name: "invalid route status", policy: SuccessPolicy{Routes: map[string]SuccessCriteria{"/health": {Status: []string{"up"}}}}, wantErr: "route /health: invalid status"},
+	}
+	for _, tt := range tests {
+		err := tt.policy.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSuccessPolicy_ForRoute(t *testing.T) {
+	policy := SuccessPolicy{
+		SuccessCriteria: SuccessCriteria{Status: []string{"2xx"}, BodyContains: []string{"<html"}},
+		Routes: map[string]SuccessCriteria{
+			"/api/:id": {Status: []string{"200", "404"}},
+		},
+	}
+
+	route := policy.ForRoute("/api/:id")
+	if !reflect.DeepEqual(route.Status, []string{"200", "404"}) || !reflect.DeepEqual(route.BodyContains, []string{"<html"}) {
+		t.Errorf("Expected the route's status with the inherited body assertion, got %+v", route)
+	}
+	if other := policy.ForRoute("/about"); !reflect.DeepEqual(other, policy.SuccessCriteria) {
+		t.Errorf("Expected other routes to get the default criteria, got %+v", other)
+	}
+}
@@ -0,0 +1,294 @@
+// Package histogram records latencies in a high-dynamic-range (HDR) histogram.
+//
+// Values are counted in buckets whose width grows with the value, so every value
+// is kept to a fixed number of significant decimal digits while memory depends
+// only on the value range, never on how many values were recorded. Durations from
+// about 1µs to 1h are tracked; longer ones are counted as 1h.
+package histogram
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultPrecision is the default number of significant decimal digits kept.
+	DefaultPrecision = 3
+	// MinPrecision and MaxPrecision bound the configurable precision.
+	MinPrecision = 1
+	MaxPrecision = 5
+
+	// HighestTrackable is the longest duration told apart from longer ones.
+	HighestTrackable = time.Hour
+
+	// unitMagnitude makes the finest bucket 2^10 ns (about 1µs) wide.
+	unitMagnitude = 10
+)
+
+// Histogram counts durations in logarithmic buckets of linear sub-buckets.
+// Count, sum, minimum and maximum are exact; quantiles are accurate to the precision.
+// A Histogram is not safe for concurrent use.
+type Histogram struct {
+	// counts grows up to the bucket of the largest value recorded.
+	counts []int64
+
+	precision                   int
+	subBucketCount              int
+	subBucketHalfCount          int
+	subBucketHalfCountMagnitude int
+	subBucketMask               int64
+
+	total int64
+	sum   int64
+	min   int64
+	max   int64
+}
+
+// New returns an empty histogram keeping precision significant decimal digits,
+// clamped to MinPrecision-MaxPrecision.
+func New(precision int) *Histogram {
+	precision = max(MinPrecision, min(precision, MaxPrecision))
+
+	// Sub-buckets must resolve 1 part in 10^precision across a power-of-two range
+	largestSingleUnit := 2 * int64(math.Pow10(precision))
+	subBucketCountMagnitude := bits.Len64(uint64(largestSingleUnit - 1))
+	subBucketCount := 1 << subBucketCountMagnitude
+
+	return &Histogram{
+		precision:                   precision,
+		subBucketCount:              subBucketCount,
+		subBucketHalfCount:          subBucketCount / 2,
+		subBucketHalfCountMagnitude: subBucketCountMagnitude - 1,
+		subBucketMask:               int64(subBucketCount-1) << unitMagnitude,
+	}
+}
+
+// Precision returns the number of significant decimal digits kept.
+func (h *Histogram) Precision() int {
+	return h.precision
+}
+
+// Record counts one occurrence of d.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordN(d, 1)
+}
+
+// RecordN counts n occurrences of d. Negative durations count as zero.
+func (h *Histogram) RecordN(d time.Duration, n int64) {
+	if n <= 0 {
+		return
+	}
+	value := max(int64(0), min(int64(d), int64(HighestTrackable)))
+
+	index := h.countsIndex(value)
+	if index >= len(h.counts) {
+		// Grow a whole bucket at a time
+		grown := make([]int64, (index>>h.subBucketHalfCountMagnitude+1)<<h.subBucketHalfCountMagnitude)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[index] += n
+
+	if h.total == 0 || value < h.min {
+		h.min = value
+	}
+	if value > h.max {
+		h.max = value
+	}
+	h.total += n
+	h.sum += value * n
+}
+
+// Merge adds the values recorded in other, which may have a different precision.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.total == 0 {
+		return
+	}
+	minimum, maximum := h.min, h.max
+	empty := h.total == 0
+
+	for index, count := range other.counts {
+		if count > 0 {
+			h.RecordN(time.Duration(other.representativeValue(index)), count)
+		}
+	}
+
+	// Keep the exact extremes and sum rather than the bucketed ones
+	h.sum += other.sum - other.bucketedSum()
+	h.min, h.max = other.min, other.max
+	if !empty {
+		h.min, h.max = min(minimum, other.min), max(maximum, other.max)
+	}
+}
+
+// Count returns the number of values recorded.
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Min returns the smallest value recorded, or 0 if none was.
+func (h *Histogram) Min() time.Duration {
+	return time.Duration(h.min)
+}
+
+// Max returns the largest value recorded, or 0 if none was.
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max)
+}
+
+// Mean returns the average of the values recorded, or 0 if none was.
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.sum / h.total)
+}
+
+// StdDev returns the standard deviation of the values recorded, computed from
+// their sub-buckets, or 0 if none was.
+func (h *Histogram) StdDev() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	mean := float64(h.sum) / float64(h.total)
+	var squares float64
+	for index, count := range h.counts {
+		if count > 0 {
+			deviation := float64(max(h.min, min(h.representativeValue(index), h.max))) - mean
+			squares += deviation * deviation * float64(count)
+		}
+	}
+	return time.Duration(math.Sqrt(squares / float64(h.total)))
+}
+
+// Distribution counts the values in the ranges delimited by ascending bounds:
+// counts[i] is the number of values above bounds[i-1] and at most bounds[i], and
+// the extra last count is the number above the last bound.
+func (h *Histogram) Distribution(bounds []time.Duration) []int64 {
+	counts := make([]int64, len(bounds)+1)
+	for index, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+		value := time.Duration(h.representativeValue(index))
+		i, _ := slices.BinarySearch(bounds, value)
+		counts[i] += count
+	}
+	return counts
+}
+
+// Quantile returns the q-th quantile (0-1) by nearest rank: the value below which
+// a fraction q of the recorded values fall. It returns 0 if nothing was recorded.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := min(int64(float64(h.total)*q)+1, h.total)
+
+	var seen int64
+	for index, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			value := max(h.min, min(h.representativeValue(index), h.max))
+			return time.Duration(value)
+		}
+	}
+	return time.Duration(h.max)
+}
+
+// countsIndex returns the index of the sub-bucket value falls in.
+func (h *Histogram) countsIndex(value int64) int {
+	bucket, subBucket := h.bucketOf(value)
+	return (bucket+1)<<h.subBucketHalfCountMagnitude + subBucket - h.subBucketHalfCount
+}
+
+// bucketOf returns the bucket and sub-bucket indexes of value.
+func (h *Histogram) bucketOf(value int64) (int, int) {
+	pow2Ceiling := bits.Len64(uint64(value | h.subBucketMask))
+	bucket := pow2Ceiling - unitMagnitude - (h.subBucketHalfCountMagnitude + 1)
+	return bucket, int(value >> (bucket + unitMagnitude))
+}
+
+// representativeValue returns the middle of the value range counted at index.
+func (h *Histogram) representativeValue(index int) int64 {
+	bucket := index>>h.subBucketHalfCountMagnitude - 1
+	subBucket := index&(h.subBucketHalfCount-1) + h.subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= h.subBucketHalfCount
+		bucket = 0
+	}
+	lowest := int64(subBucket) << (bucket + unitMagnitude)
+	width := int64(1) << (bucket + unitMagnitude)
+	return lowest + width/2
+}
+
+// bucketedSum returns the sum of the values as their sub-buckets represent them.
+func (h *Histogram) bucketedSum() int64 {
+	var sum int64
+	for index, count := range h.counts {
+		if count > 0 {
+			sum += h.representativeValue(index) * count
+		}
+	}
+	return sum
+}
+
+// histogramJSON is the serialized form of a Histogram. Values are listed by the
+// middle of their sub-bucket, so histograms of any precision can read them back.
+type histogramJSON struct {
+	// Values are [value in ns, count] pairs, smallest value first.
+	Values    [][2]int64 `json:"values"`
+	Precision int        `json:"precision"`
+	Count     int64      `json:"count"`
+	Sum       int64      `json:"sum"`
+	Min       int64      `json:"min"`
+	Max       int64      `json:"max"`
+}
+
+// MarshalJSON encodes the non-empty sub-buckets and the exact count, sum and extremes.
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	out := histogramJSON{
+		Values:    [][2]int64{},
+		Precision: h.precision,
+		Count:     h.total,
+		Sum:       h.sum,
+		Min:       h.min,
+		Max:       h.max,
+	}
+	for index, count := range h.counts {
+		if count > 0 {
+			out.Values = append(out.Values, [2]int64{h.representativeValue(index), count})
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a histogram written by MarshalJSON.
+func (h *Histogram) UnmarshalJSON(data []byte) error {
+	var in histogramJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	decoded := New(in.Precision)
+	var count int64
+	for _, pair := range in.Values {
+		if pair[1] < 0 {
+			return fmt.Errorf("negative count %d for value %d", pair[1], pair[0])
+		}
+		decoded.RecordN(time.Duration(pair[0]), pair[1])
+		count += pair[1]
+	}
+	if count != in.Count {
+		return fmt.Errorf("histogram count %d does not match its values (%d)", in.Count, count)
+	}
+	if count > 0 {
+		decoded.sum, decoded.min, decoded.max = in.Sum, in.Min, in.Max
+	}
+	*h = *decoded
+	return nil
+}
@@ -0,0 +1,180 @@
+package histogram
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+// withinPrecision reports whether got is within the relative error of a histogram
+// keeping precision significant digits, or within the 1µs finest bucket.
+func withinPrecision(got, want time.Duration, precision int) bool {
+	diff := got - want
+	if diff < 0 {
+		diff = -diff
+	}
+	tolerance := float64(want)
+	for range precision {
+		tolerance /= 10
+	}
+	return diff <= time.Duration(tolerance) || diff <= time.Microsecond
+}
+
+func TestHistogram_Empty(t *testing.T) {
+	h := New(DefaultPrecision)
+	if h.Count() != 0 || h.Min() != 0 || h.Max() != 0 || h.Mean() != 0 || h.Quantile(0.99) != 0 {
+		t.Errorf("Expected an empty histogram to report zeros, got count %d, min %v, max %v, mean %v, p99 %v",
+			h.Count(), h.Min(), h.Max(), h.Mean(), h.Quantile(0.99))
+	}
+}
+
+func TestHistogram_ExactStatistics(t *testing.T) {
+	h := New(DefaultPrecision)
+	for _, d := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond} {
+		h.Record(d)
+	}
+
+	if h.Count() != 3 {
+		t.Errorf("Expected count 3, got %d", h.Count())
+	}
+	if h.Min() != 100*time.Millisecond || h.Max() != 300*time.Millisecond {
+		t.Errorf("Expected exact min 100ms and max 300ms, got %v and %v", h.Min(), h.Max())
+	}
+	if h.Mean() != 200*time.Millisecond {
+		t.Errorf("Expected exact mean 200ms, got %v", h.Mean())
+	}
+	if h.Quantile(1) != 300*time.Millisecond || h.Quantile(0) != 100*time.Millisecond {
+		t.Errorf("Expected the extreme quantiles to be the exact extremes, got %v and %v", h.Quantile(0), h.Quantile(1))
+	}
+}
+
+func TestHistogram_QuantilesMatchNearestRank(t *testing.T) {
+	for _, precision := range []int{2, 3, 4} {
+		h := New(precision)
+		samples := make([]time.Duration, 0, 10000)
+		rng := rand.New(rand.NewPCG(1, 2))
+		for range 10000 {
+			// Log-uniform from 100µs to 10s
+			d := time.Duration(100e3 * float64(uint64(1)<<rng.IntN(17)) * (1 + rng.Float64()))
+			samples = append(samples, d)
+			h.Record(d)
+		}
+		slices.Sort(samples)
+
+		for _, q := range []float64{0.5, 0.75, 0.9, 0.95, 0.99, 0.999} {
+			want := samples[min(int(float64(len(samples))*q), len(samples)-1)]
+			if got := h.Quantile(q); !withinPrecision(got, want, precision) {
+				t.Errorf("precision %d: expected p%g within precision of %v, got %v", precision, q*100, want, got)
+			}
+		}
+	}
+}
+
+func TestHistogram_BoundedMemory(t *testing.T) {
+	h := New(DefaultPrecision)
+	for i := range 1000000 {
+		h.Record(time.Duration(i%5000) * time.Millisecond)
+	}
+	// 5s needs 23 buckets of 1024 sub-buckets, whatever the number of values
+	if len(h.counts) > 23*1024 {
+		t.Errorf("Expected at most %d counters, got %d", 23*1024, len(h.counts))
+	}
+
+	h.Record(10 * HighestTrackable)
+	if h.Max() != HighestTrackable {
+		t.Errorf("Expected values beyond the range to count as %v, got %v", HighestTrackable, h.Max())
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a, b, all := New(3), New(2), New(3)
+	for i := 1; i <= 1000; i++ {
+		d := time.Duration(i) * time.Millisecond
+		if i%2 == 0 {
+			a.Record(d)
+		} else {
+			b.Record(d)
+		}
+		all.Record(d)
+	}
+
+	a.Merge(b)
+	a.Merge(nil)
+	if a.Count() != 1000 || a.Min() != time.Millisecond || a.Max() != time.Second {
+		t.Errorf("Expected 1000 values from 1ms to 1s, got %d from %v to %v", a.Count(), a.Min(), a.Max())
+	}
+	if a.Mean() != all.Mean() {
+		t.Errorf("Expected the exact mean %v to survive merging, got %v", all.Mean(), a.Mean())
+	}
+	for _, q := range []float64{0.5, 0.95, 0.99} {
+		if got, want := a.Quantile(q), all.Quantile(q); !withinPrecision(got, want, 2) {
+			t.Errorf("Expected merged p%g near %v, got %v", q*100, want, got)
+		}
+	}
+
+	empty := New(3)
+	empty.Merge(b)
+	if empty.Min() != b.Min() || empty.Max() != b.Max() || empty.Count() != b.Count() {
+		t.Errorf("Expected merging into an empty histogram to copy it, got %d from %v to %v", empty.Count(), empty.Min(), empty.Max())
+	}
+}
+
+func TestHistogram_JSONRoundTrip(t *testing.T) {
+	h := New(3)
+	for i := 1; i <= 500; i++ {
+		h.Record(time.Duration(i*i) * time.Microsecond)
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Histogram
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.Count() != h.Count() || decoded.Mean() != h.Mean() || decoded.Min() != h.Min() || decoded.Max() != h.Max() {
+		t.Errorf("Expected exact statistics to round-trip, got %d/%v/%v/%v", decoded.Count(), decoded.Mean(), decoded.Min(), decoded.Max())
+	}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		if decoded.Quantile(q) != h.Quantile(q) {
+			t.Errorf("Expected p%g %v after round-trip, got %v", q*100, h.Quantile(q), decoded.Quantile(q))
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"values":[[1000,2]],"precision":3,"count":3}`), &decoded); err == nil {
+		t.Error("Expected an error for a count that does not match the values")
+	}
+}
+
+func TestHistogram_StdDev(t *testing.T) {
+	h := New(3)
+	for _, d := range []time.Duration{2, 4, 4, 4, 5, 5, 7, 9} {
+		h.Record(d * time.Millisecond)
+	}
+	if got := h.StdDev(); !withinPrecision(got, 2*time.Millisecond, 2) {
+		t.Errorf("Expected a standard deviation of 2ms, got %v", got)
+	}
+
+	constant := New(3)
+	constant.RecordN(time.Second, 10)
+	if got := constant.StdDev(); got != 0 {
+		t.Errorf("Expected no deviation for identical values, got %v", got)
+	}
+}
+
+func TestHistogram_Distribution(t *testing.T) {
+	h := New(3)
+	for _, d := range []time.Duration{500 * time.Microsecond, 3 * time.Millisecond, 8 * time.Millisecond, 10 * time.Millisecond, 2 * time.Second} {
+		h.Record(d)
+	}
+
+	got := h.Distribution([]time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond})
+	want := []int64{1, 3, 0, 1}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected distribution %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,217 @@
+package linkgraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// Export formats.
+const (
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+	FormatJSON    = "json"
+)
+
+// FormatFromPath returns the export format implied by a file's extension.
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		return FormatDOT, nil
+	case ".graphml":
+		return FormatGraphML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported graph file %q: use a .dot, .gv, .graphml or .json extension", path)
+	}
+}
+
+// WriteFile exports the graph to path in the format implied by its extension.
+func WriteFile(path string, graph domain.LinkGraph) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) //nolint:gosec // Graph path is provided by the user
+	if err != nil {
+		return fmt.Errorf("cannot create graph file %s: %w", path, err)
+	}
+
+	if err := Write(file, format, graph); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing graph file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Write exports the graph to w in the given format.
+func Write(w io.Writer, format string, graph domain.LinkGraph) error {
+	buf := bufio.NewWriter(w)
+
+	var err error
+	switch format {
+	case FormatDOT:
+		err = writeDOT(buf, graph)
+	case FormatGraphML:
+		err = writeGraphML(buf, graph)
+	case FormatJSON:
+		err = writeJSON(buf, graph)
+	default:
+		return fmt.Errorf("unsupported graph format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s graph: %w", format, err)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("writing %s graph: %w", format, err)
+	}
+	return nil
+}
+
+// clickDepthOf returns a page's click depth, or -1 if it is unreachable.
+func (a analysis) clickDepthOf(pageURL string) int {
+	if depth, ok := a.clickDepth[pageURL]; ok {
+		return depth
+	}
+	return -1
+}
+
+// writeDOT writes a Graphviz digraph. Nodes are labeled with their URL;
+// pages that returned an error status are drawn in red.
+func writeDOT(w *bufio.Writer, graph domain.LinkGraph) error {
+	a := analyze(graph)
+	ids := make(map[string]string, len(graph.Nodes))
+
+	fmt.Fprintf(w, "digraph crawl {\n")
+	fmt.Fprintf(w, "  // %d pages, %d links\n", len(graph.Nodes), len(graph.Edges))
+	fmt.Fprintf(w, "  node [shape=box];\n")
+	for i, n := range graph.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.URL] = id
+		attrs := fmt.Sprintf("label=%s, depth=%d, click_depth=%d, status=%d, inbound=%d",
+			dotQuote(n.URL), n.Depth, a.clickDepthOf(n.URL), n.StatusCode, a.inbound[n.URL])
+		if n.StatusCode >= 400 {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", id, attrs)
+	}
+	for _, e := range graph.Edges {
+		if e.Count > 1 {
+			fmt.Fprintf(w, "  %s -> %s [count=%d];\n", ids[e.From], ids[e.To], e.Count)
+		} else {
+			fmt.Fprintf(w, "  %s -> %s;\n", ids[e.From], ids[e.To])
+		}
+	}
+	_, err := fmt.Fprintf(w, "}\n")
+	return err
+}
+
+// dotQuote returns s as a DOT double-quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// writeGraphML writes a GraphML document with page metrics as node data.
+func writeGraphML(w *bufio.Writer, graph domain.LinkGraph) error {
+	a := analyze(graph)
+	ids := make(map[string]string, len(graph.Nodes))
+
+	fmt.Fprint(w, xml.Header)
+	fmt.Fprintf(w, "<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	fmt.Fprintf(w, "  <key id=\"url\" for=\"node\" attr.name=\"url\" attr.type=\"string\"/>\n")
+	fmt.Fprintf(w, "  <key id=\"depth\" for=\"node\" attr.name=\"depth\" attr.type=\"int\"/>\n")
+	fmt.Fprintf(w, "  <key id=\"click_depth\" for=\"node\" attr.name=\"click_depth\" attr.type=\"int\"/>\n")
+	fmt.Fprintf(w, "  <key id=\"status\" for=\"node\" attr.name=\"status\" attr.type=\"int\"/>\n")
+	fmt.Fprintf(w, "  <key id=\"crawled\" for=\"node\" attr.name=\"crawled\" attr.type=\"boolean\"/>\n")
+	fmt.Fprintf(w, "  <key id=\"inbound\" for=\"node\" attr.name=\"inbound\" attr.type=\"int\"/>\n")
+	fmt.Fprintf(w, "  <key id=\"count\" for=\"edge\" attr.name=\"count\" attr.type=\"int\"/>\n")
+	fmt.Fprintf(w, "  <graph id=\"crawl\" edgedefault=\"directed\">\n")
+	for i, n := range graph.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.URL] = id
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", id)
+		fmt.Fprintf(w, "      <data key=\"url\">%s</data>\n", xmlEscape(n.URL))
+		fmt.Fprintf(w, "      <data key=\"depth\">%d</data>\n", n.Depth)
+		fmt.Fprintf(w, "      <data key=\"click_depth\">%d</data>\n", a.clickDepthOf(n.URL))
+		fmt.Fprintf(w, "      <data key=\"status\">%d</data>\n", n.StatusCode)
+		fmt.Fprintf(w, "      <data key=\"crawled\">%t</data>\n", n.Crawled)
+		fmt.Fprintf(w, "      <data key=\"inbound\">%d</data>\n", a.inbound[n.URL])
+		fmt.Fprintf(w, "    </node>\n")
+	}
+	for i, e := range graph.Edges {
+		fmt.Fprintf(w, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, ids[e.From], ids[e.To])
+		fmt.Fprintf(w, "      <data key=\"count\">%d</data>\n", e.Count)
+		fmt.Fprintf(w, "    </edge>\n")
+	}
+	fmt.Fprintf(w, "  </graph>\n")
+	_, err := fmt.Fprintf(w, "</graphml>\n")
+	return err
+}
+
+// xmlEscape returns s escaped for XML character data.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s)) // strings.Builder never fails
+	return b.String()
+}
+
+// jsonPage is a page in the JSON adjacency export.
+type jsonPage struct {
+	URL        string   `json:"url"`
+	LinksTo    []string `json:"links_to"`
+	Depth      int      `json:"depth"`
+	ClickDepth int      `json:"click_depth"`
+	StatusCode int      `json:"status_code"`
+	Inbound    int      `json:"inbound"`
+	Crawled    bool     `json:"crawled"`
+}
+
+// writeJSON writes the graph as an adjacency list together with its stats.
+func writeJSON(w *bufio.Writer, graph domain.LinkGraph) error {
+	a := analyze(graph)
+
+	linksTo := make(map[string][]string, len(graph.Nodes))
+	for _, e := range graph.Edges {
+		linksTo[e.From] = append(linksTo[e.From], e.To)
+	}
+
+	doc := struct {
+		Pages []jsonPage            `json:"pages"`
+		Stats domain.LinkGraphStats `json:"stats"`
+	}{
+		Pages: make([]jsonPage, 0, len(graph.Nodes)),
+		Stats: Stats(graph),
+	}
+	for _, n := range graph.Nodes {
+		targets := linksTo[n.URL]
+		if targets == nil {
+			targets = []string{}
+		}
+		doc.Pages = append(doc.Pages, jsonPage{
+			URL:        n.URL,
+			LinksTo:    targets,
+			Depth:      n.Depth,
+			ClickDepth: a.clickDepthOf(n.URL),
+			StatusCode: n.StatusCode,
+			Inbound:    a.inbound[n.URL],
+			Crawled:    n.Crawled,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
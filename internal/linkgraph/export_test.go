@@ -0,0 +1,144 @@
+package linkgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"site.dot", FormatDOT, false},
+		{"site.GV", FormatDOT, false},
+		{"out/site.graphml", FormatGraphML, false},
+		{"site.json", FormatJSON, false},
+		{"site.png", "", true},
+		{"site", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := FormatFromPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FormatFromPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("FormatFromPath(%q) = %q, expected %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWrite_DOT(t *testing.T) {
+	g := sampleGraph()
+	g.AddLink("/", 0, `/q?x="1"`, 1)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatDOT, g.Snapshot()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"digraph crawl {",
+		`label="/q?x=\"1\""`,
+		"n1 -> n2 [count=2];",
+		`label="/lonely", depth=3, click_depth=-1, status=404, inbound=0, color=red`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected DOT output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWrite_GraphML(t *testing.T) {
+	g := sampleGraph()
+	g.AddLink("/", 0, "/search?a=1&b=<2>", 1)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatGraphML, g.Snapshot()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected valid GraphML, got error %v:\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 6 || len(doc.Graph.Edges) != 6 {
+		t.Fatalf("Expected 6 nodes and 6 edges, got %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+
+	found := false
+	for _, n := range doc.Graph.Nodes {
+		for _, d := range n.Data {
+			if d.Key == "url" && d.Value == "/search?a=1&b=<2>" {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected URL with XML special characters to round-trip")
+	}
+}
+
+func TestWriteFile_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := WriteFile(path, sampleGraph().Snapshot()); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read graph file: %v", err)
+	}
+	var doc struct {
+		Pages []jsonPage `json:"pages"`
+		Stats struct {
+			DeadEnds []string `json:"dead_ends"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Expected valid JSON, got error %v", err)
+	}
+
+	if len(doc.Pages) != 5 {
+		t.Fatalf("Expected 5 pages, got %d", len(doc.Pages))
+	}
+	home := doc.Pages[0]
+	if home.URL != "/" || len(home.LinksTo) != 2 || home.ClickDepth != 0 {
+		t.Errorf("Expected / linking to 2 pages at click depth 0, got %+v", home)
+	}
+	if len(doc.Stats.DeadEnds) != 1 {
+		t.Errorf("Expected stats with 1 dead end, got %v", doc.Stats.DeadEnds)
+	}
+}
+
+func TestWriteFile_UnsupportedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.svg")
+	if err := WriteFile(path, sampleGraph().Snapshot()); err == nil {
+		t.Error("Expected error for unsupported extension, got nil")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no file to be created for an unsupported extension")
+	}
+}
@@ -0,0 +1,198 @@
+// Package linkgraph records the directed link graph of a crawl and exports it
+// in Graphviz DOT, GraphML and JSON adjacency formats.
+package linkgraph
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// mostLinkedLimit is the number of pages listed in LinkGraphStats.MostLinked.
+const mostLinkedLimit = 10
+
+// Graph collects pages and links as they are discovered. It is safe for concurrent use.
+type Graph struct {
+	mu    sync.Mutex
+	nodes map[string]*domain.GraphNode
+	edges map[edgeKey]int
+}
+
+// edgeKey identifies a link between two pages.
+type edgeKey struct {
+	from, to string
+}
+
+// New creates an empty link graph
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[string]*domain.GraphNode),
+		edges: make(map[edgeKey]int),
+	}
+}
+
+// node returns the node for pageURL, creating it at depth if missing.
+// A page seen again at a shallower depth keeps the shallower one. Callers hold g.mu.
+func (g *Graph) node(pageURL string, depth int) *domain.GraphNode {
+	n, ok := g.nodes[pageURL]
+	if !ok {
+		n = &domain.GraphNode{URL: pageURL, Depth: depth}
+		g.nodes[pageURL] = n
+	} else if depth < n.Depth {
+		n.Depth = depth
+	}
+	return n
+}
+
+// MarkCrawled records that the links of a page at the given depth were extracted.
+func (g *Graph) MarkCrawled(pageURL string, depth int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.node(pageURL, depth).Crawled = true
+}
+
+// AddLink records a link from a page at fromDepth to a page discovered at toDepth.
+func (g *Graph) AddLink(from string, fromDepth int, to string, toDepth int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.node(from, fromDepth)
+	g.node(to, toDepth)
+	g.edges[edgeKey{from: from, to: to}]++
+}
+
+// SetStatus records the HTTP status of a requested page.
+func (g *Graph) SetStatus(pageURL string, depth, statusCode int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.node(pageURL, depth).StatusCode = statusCode
+}
+
+// Snapshot returns the graph with nodes sorted by URL and edges by source then target.
+func (g *Graph) Snapshot() domain.LinkGraph {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	graph := domain.LinkGraph{
+		Nodes: make([]domain.GraphNode, 0, len(g.nodes)),
+		Edges: make([]domain.GraphEdge, 0, len(g.edges)),
+	}
+	for _, n := range g.nodes {
+		graph.Nodes = append(graph.Nodes, *n)
+	}
+	for key, count := range g.edges {
+		graph.Edges = append(graph.Edges, domain.GraphEdge{From: key.from, To: key.to, Count: count})
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].URL < graph.Nodes[j].URL
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	return graph
+}
+
+// Restore replaces the graph with a snapshot taken by Snapshot.
+func (g *Graph) Restore(graph domain.LinkGraph) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.nodes = make(map[string]*domain.GraphNode, len(graph.Nodes))
+	g.edges = make(map[edgeKey]int, len(graph.Edges))
+	for _, n := range graph.Nodes {
+		restored := n
+		g.nodes[n.URL] = &restored
+	}
+	for _, e := range graph.Edges {
+		g.edges[edgeKey{from: e.From, to: e.To}] = e.Count
+	}
+}
+
+// analysis holds per-page metrics derived from a graph.
+type analysis struct {
+	clickDepth map[string]int // Fewest clicks from a start page; missing = unreachable
+	inbound    map[string]int // Distinct other pages linking to the page
+	outbound   map[string][]string
+}
+
+// analyze computes click depths and link counts. Self-links are ignored.
+// Start pages are those discovered at depth 0.
+func analyze(graph domain.LinkGraph) analysis {
+	a := analysis{
+		clickDepth: make(map[string]int, len(graph.Nodes)),
+		inbound:    make(map[string]int, len(graph.Nodes)),
+		outbound:   make(map[string][]string, len(graph.Nodes)),
+	}
+
+	for _, e := range graph.Edges {
+		if e.From == e.To {
+			continue
+		}
+		a.inbound[e.To]++
+		a.outbound[e.From] = append(a.outbound[e.From], e.To)
+	}
+
+	// Breadth-first search from the start pages
+	var queue []string
+	for _, n := range graph.Nodes {
+		if n.Depth == 0 {
+			a.clickDepth[n.URL] = 0
+			queue = append(queue, n.URL)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range a.outbound[current] {
+			if _, seen := a.clickDepth[next]; !seen {
+				a.clickDepth[next] = a.clickDepth[current] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return a
+}
+
+// Stats computes summary statistics for a graph.
+func Stats(graph domain.LinkGraph) domain.LinkGraphStats {
+	a := analyze(graph)
+
+	stats := domain.LinkGraphStats{
+		PagesByClickDepth: make(map[int]int),
+		DeadEnds:          []string{},
+		Pages:             len(graph.Nodes),
+		Links:             len(graph.Edges),
+	}
+
+	var linked []domain.PageLinks
+	for _, n := range graph.Nodes {
+		if depth, ok := a.clickDepth[n.URL]; ok {
+			stats.PagesByClickDepth[depth]++
+			stats.MaxClickDepth = max(stats.MaxClickDepth, depth)
+		} else {
+			stats.Unreachable++
+		}
+		if n.Crawled && len(a.outbound[n.URL]) == 0 {
+			stats.DeadEnds = append(stats.DeadEnds, n.URL)
+		}
+		if a.inbound[n.URL] > 0 {
+			linked = append(linked, domain.PageLinks{URL: n.URL, Inbound: a.inbound[n.URL]})
+		}
+	}
+
+	sort.SliceStable(linked, func(i, j int) bool {
+		return linked[i].Inbound > linked[j].Inbound
+	})
+	if len(linked) > mostLinkedLimit {
+		linked = linked[:mostLinkedLimit]
+	}
+	stats.MostLinked = linked
+
+	return stats
+}
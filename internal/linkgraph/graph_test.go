@@ -0,0 +1,101 @@
+package linkgraph
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// sampleGraph builds: / -> /a, / -> /b, /a -> /b (twice), /a -> /a, /b -> /deep,
+// plus /lonely (discovered but not linked) and / crawled with no status.
+func sampleGraph() *Graph {
+	g := New()
+	g.MarkCrawled("/", 0)
+	g.AddLink("/", 0, "/a", 1)
+	g.AddLink("/", 0, "/b", 1)
+	g.MarkCrawled("/a", 1)
+	g.AddLink("/a", 1, "/b", 2)
+	g.AddLink("/a", 1, "/b", 2)
+	g.AddLink("/a", 1, "/a", 2)
+	g.MarkCrawled("/b", 1)
+	g.AddLink("/b", 1, "/deep", 2)
+	g.MarkCrawled("/deep", 2)
+	g.SetStatus("/lonely", 3, 404)
+	return g
+}
+
+func TestSnapshot_SortedAndMerged(t *testing.T) {
+	graph := sampleGraph().Snapshot()
+
+	var urls []string
+	for _, n := range graph.Nodes {
+		urls = append(urls, n.URL)
+	}
+	if expected := []string{"/", "/a", "/b", "/deep", "/lonely"}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected nodes %v, got %v", expected, urls)
+	}
+
+	// /b was linked at depth 2 but discovered at depth 1 first; the shallower depth wins
+	if graph.Nodes[2].Depth != 1 {
+		t.Errorf("Expected /b at depth 1, got %d", graph.Nodes[2].Depth)
+	}
+
+	expected := domain.GraphEdge{From: "/a", To: "/b", Count: 2}
+	if graph.Edges[3] != expected {
+		t.Errorf("Expected repeated link merged as %+v, got %+v", expected, graph.Edges[3])
+	}
+}
+
+func TestStats(t *testing.T) {
+	stats := Stats(sampleGraph().Snapshot())
+
+	if stats.Pages != 5 || stats.Links != 5 {
+		t.Errorf("Expected 5 pages and 5 links, got %d and %d", stats.Pages, stats.Links)
+	}
+	if !reflect.DeepEqual(stats.DeadEnds, []string{"/deep"}) {
+		t.Errorf("Expected /deep to be the only dead end, got %v", stats.DeadEnds)
+	}
+	if len(stats.MostLinked) == 0 || stats.MostLinked[0] != (domain.PageLinks{URL: "/b", Inbound: 2}) {
+		t.Errorf("Expected /b most linked with 2 inbound, got %+v", stats.MostLinked)
+	}
+	if stats.MaxClickDepth != 2 || stats.Unreachable != 1 {
+		t.Errorf("Expected max click depth 2 and 1 unreachable, got %d and %d", stats.MaxClickDepth, stats.Unreachable)
+	}
+	if expected := map[int]int{0: 1, 1: 2, 2: 1}; !reflect.DeepEqual(stats.PagesByClickDepth, expected) {
+		t.Errorf("Expected pages by click depth %v, got %v", expected, stats.PagesByClickDepth)
+	}
+}
+
+func TestRestore_RoundTrip(t *testing.T) {
+	original := sampleGraph().Snapshot()
+
+	restored := New()
+	restored.Restore(original)
+
+	if got := restored.Snapshot(); !reflect.DeepEqual(got, original) {
+		t.Errorf("Expected restored graph %+v, got %+v", original, got)
+	}
+}
+
+func TestGraph_ConcurrentUse(t *testing.T) {
+	g := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				g.AddLink("/", 0, "/page", 1)
+				g.SetStatus("/page", 1, 200)
+			}
+		}()
+	}
+	wg.Wait()
+
+	graph := g.Snapshot()
+	if len(graph.Edges) != 1 || graph.Edges[0].Count != 800 {
+		t.Errorf("Expected one edge with count 800, got %+v", graph.Edges)
+	}
+}
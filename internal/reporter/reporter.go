@@ -17,6 +17,9 @@ import (
 //go:embed templates/report.html
 var reportTemplate string
 
+// maxConsoleRoutes is the number of routes listed in the console summary.
+const maxConsoleRoutes = 20
+
 // Reporter generates test reports in various formats
 type Reporter struct {
 	results *domain.TestResults
@@ -49,6 +52,51 @@ type SlowRequestEntry struct {
 	StatusGroup  string
 }
 
+// TimingPhaseEntry is one request phase of the timing breakdown, for rendering.
+type TimingPhaseEntry struct {
+	Name  string
+	Stats domain.PhaseStats
+}
+
+// LatencyBucketEntry is one bucket of the latency distribution, for rendering.
+type LatencyBucketEntry struct {
+	Label string
+	Count int64
+}
+
+// RouteEntry is one row of the per-route table, for rendering.
+type RouteEntry struct {
+	Name      string
+	StatusMix string
+	ClassMix  string
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	Requests  int64
+	Errors    int64
+	ErrorRate float64
+}
+
+// ErrorClassEntry is the count of one error class, with an example message if
+// any request failing with it got no response.
+type ErrorClassEntry struct {
+	Class   domain.ErrorClass
+	Example string
+	Count   int64
+	Percent float64
+}
+
+// TimeSeriesEntry is one time-series bucket, with rates and milliseconds for charting.
+type TimeSeriesEntry struct {
+	Label             string
+	RequestsPerSecond float64
+	FailuresPerSecond float64
+	P50Ms             float64
+	P95Ms             float64
+	P99Ms             float64
+	InFlight          int64
+}
+
 // TemplateData contains all data needed for HTML template rendering.
 type TemplateData struct {
 	Timestamp           string
@@ -57,15 +105,33 @@ type TemplateData struct {
 	SuccessfulRequests  int64
 	FailedRequests      int64
 	URLsDiscovered      int
+	NormalizedDups      int
+	CanonicalDups       int
+	NofollowSkips       domain.NofollowSkips
 	SuccessRate         float64
 	SuccessRateClass    string
 	RequestsPerSecond   float64
+	BytesReceived       string
+	Bandwidth           string
 	AverageResponseTime string
 	StatusDistribution  []StatusDistributionEntry
 	URLValidations      []URLValidationEntry
+	URLsTested          int64 // URLs counted in the status distribution; may exceed URLValidations
 	SlowRequests        []SlowRequestEntry
+	Timing              *domain.TimingBreakdown
+	TimingPhases        []TimingPhaseEntry
+	Routes              []RouteEntry
+	TimeSeries          []TimeSeriesEntry
+	RedirectIssues      []domain.RedirectIssue
+	BrokenLinks         []domain.BrokenLink
+	LinkGraph           *domain.LinkGraphStats
+	CrawlTraps          []domain.CrawlTrap
+	DeniedLinks         []domain.DeniedLink
+	Robots              *domain.RobotsReport
 	Errors              []domain.ErrorInfo
-	ResponseTimesMs     []float64
+	ErrorClasses        []ErrorClassEntry
+	Latency             domain.LatencyStats
+	LatencyBuckets      []LatencyBucketEntry
 }
 
 // New creates a new report generator
@@ -125,25 +191,81 @@ func (r *Reporter) PrintSummary() {
 	fmt.Printf("%s\n", strings.Repeat("=", 60))
 	fmt.Printf("Duration:             %s\n", r.results.Duration)
 	fmt.Printf("URLs Discovered:      %d\n", r.results.URLsDiscovered)
+	if r.results.DuplicatesByNormalization > 0 || r.results.DuplicatesByCanonical > 0 {
+		fmt.Printf("Duplicates Collapsed: %d by normalization, %d by canonical\n",
+			r.results.DuplicatesByNormalization, r.results.DuplicatesByCanonical)
+	}
+	if skips := r.results.NofollowSkips; skips.Total() > 0 {
+		fmt.Printf("Nofollow Links Skipped: %d (meta robots %d, X-Robots-Tag %d, rel=nofollow %d)\n",
+			skips.Total(), skips.MetaRobots, skips.XRobotsTag, skips.RelNofollow)
+	}
 	fmt.Printf("Total Requests:       %d\n", r.results.TotalRequests)
 	fmt.Printf("Successful Requests:  %d\n", r.results.SuccessfulRequests)
 	fmt.Printf("Failed Requests:      %d\n", r.results.FailedRequests)
 	fmt.Printf("Average Response Time: %s\n", r.results.AverageResponseTime)
 	fmt.Printf("Min Response Time:    %s\n", r.results.MinResponseTime)
 	fmt.Printf("Max Response Time:    %s\n", r.results.MaxResponseTime)
+	latency := r.results.LatencyStats()
+	if latency.Count > 0 {
+		fmt.Printf("Percentiles:          p50 %s, p75 %s, p90 %s, p95 %s, p99 %s, p99.9 %s\n",
+			latency.P50.Round(time.Microsecond), latency.P75.Round(time.Microsecond),
+			latency.P90.Round(time.Microsecond), latency.P95.Round(time.Microsecond),
+			latency.P99.Round(time.Microsecond), latency.P999.Round(time.Microsecond))
+		fmt.Printf("Std Deviation:        %s\n", latency.StdDev.Round(time.Microsecond))
+	}
 	fmt.Printf("Requests/Second:      %.2f\n", r.results.RequestsPerSecond)
+	if r.results.BytesReceived > 0 {
+		fmt.Printf("Bandwidth:            %s/s (%s received)\n",
+			formatBytes(int64(r.results.BytesPerSecond)), formatBytes(r.results.BytesReceived))
+	}
 	fmt.Printf("Success Rate:         %.2f%%\n", r.results.SuccessRate)
 
-	if len(r.results.Errors) > 0 {
+	if len(latency.Distribution) > 0 {
 		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
-		fmt.Printf("ERRORS SUMMARY\n")
+		fmt.Printf("LATENCY DISTRIBUTION\n")
 		fmt.Printf("%s\n", strings.Repeat("-", 60))
-		errorCounts := make(map[string]int)
-		for _, err := range r.results.Errors {
-			errorCounts[err.Error]++
+		for _, bucket := range latencyBuckets(latency.Distribution) {
+			fmt.Printf("  %-9s %7d %6.2f%%\n", bucket.Label, bucket.Count,
+				float64(bucket.Count)/float64(latency.Count)*100)
 		}
-		for errorMsg, count := range errorCounts {
-			fmt.Printf("  %s: %d occurrence(s)\n", errorMsg, count)
+	}
+
+	if timing := r.results.Timing; timing != nil {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("TIMING BREAKDOWN\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		fmt.Printf("  %-9s %7s %10s %10s %10s %10s\n", "Phase", "Count", "Mean", "P50", "P95", "P99")
+		for _, phase := range timingPhases(timing) {
+			fmt.Printf("  %-9s %7d %10s %10s %10s %10s\n", phase.Name, phase.Stats.Count,
+				phase.Stats.Mean.Round(time.Microsecond), phase.Stats.P50.Round(time.Microsecond),
+				phase.Stats.P95.Round(time.Microsecond), phase.Stats.P99.Round(time.Microsecond))
+		}
+		fmt.Printf("Connections: %d reused, %d new\n", timing.ReusedConnections, timing.NewConnections)
+	}
+
+	if len(r.results.RouteStats) > 0 {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("ROUTES\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		fmt.Printf("  %-32s %8s %7s %10s %10s %10s  %s\n", "Route", "Requests", "Errors", "P50", "P95", "P99", "Status")
+		for i, route := range r.results.RouteStats {
+			if i >= maxConsoleRoutes {
+				fmt.Printf("  ... and %d more (see JSON report)\n", len(r.results.RouteStats)-i)
+				break
+			}
+			fmt.Printf("  %-32s %8d %7d %10s %10s %10s  %s\n", route.Name, route.Requests, route.Errors,
+				route.Latency.P50.Round(time.Microsecond), route.Latency.P95.Round(time.Microsecond),
+				route.Latency.P99.Round(time.Microsecond), statusMix(route.StatusCodes))
+		}
+	}
+
+	if classes := errorClasses(r.results); len(classes) > 0 {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("ERRORS SUMMARY\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		fmt.Printf("  %-20s %8s %7s  %s\n", "Class", "Count", "Share", "Example")
+		for _, class := range classes {
+			fmt.Printf("  %-20s %8d %6.1f%%  %s\n", class.Class, class.Count, class.Percent, class.Example)
 		}
 	}
 
@@ -159,35 +281,187 @@ func (r *Reporter) PrintSummary() {
 		}
 	}
 
+	if len(r.results.RedirectIssues) > 0 {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("REDIRECT ISSUES\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		for i, issue := range r.results.RedirectIssues {
+			if i >= 10 {
+				fmt.Printf("  ... and %d more (see JSON report)\n", len(r.results.RedirectIssues)-i)
+				break
+			}
+			fmt.Printf("  [%s] %s: %s\n", issue.Kind, issue.URL, issue.Detail)
+		}
+	}
+
+	if len(r.results.BrokenLinks) > 0 {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("BROKEN LINKS (%d)\n", len(r.results.BrokenLinks))
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		for i, link := range r.results.BrokenLinks {
+			if i >= 10 {
+				fmt.Printf("  ... and %d more (see JSON report)\n", len(r.results.BrokenLinks)-i)
+				break
+			}
+			status := fmt.Sprintf("HTTP %d", link.StatusCode)
+			if link.Error != "" {
+				status = link.Error
+			}
+			fmt.Printf("  %s (%s)\n", link.URL, status)
+			for j, ref := range link.Referrers {
+				if j >= 3 {
+					fmt.Printf("      ... %d link(s) in total\n", link.Links)
+					break
+				}
+				if ref.AnchorText != "" {
+					fmt.Printf("      linked from %s (%q)\n", ref.SourceURL, ref.AnchorText)
+				} else {
+					fmt.Printf("      linked from %s\n", ref.SourceURL)
+				}
+			}
+		}
+	}
+
+	if graph := r.results.LinkGraph; graph != nil {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("LINK GRAPH\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		fmt.Printf("Pages: %d, Links: %d, Max Click Depth: %d\n", graph.Pages, graph.Links, graph.MaxClickDepth)
+		fmt.Printf("Dead Ends: %d, Unreachable: %d\n", len(graph.DeadEnds), graph.Unreachable)
+		if len(graph.MostLinked) > 0 {
+			fmt.Printf("Most linked:\n")
+			for i, page := range graph.MostLinked {
+				if i >= 5 {
+					break
+				}
+				fmt.Printf("  %s (%d inbound)\n", page.URL, page.Inbound)
+			}
+		}
+	}
+
+	if len(r.results.CrawlTraps) > 0 {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("CRAWL TRAPS\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		for i, trap := range r.results.CrawlTraps {
+			if i >= 10 {
+				fmt.Printf("  ... and %d more (see JSON report)\n", len(r.results.CrawlTraps)-i)
+				break
+			}
+			fmt.Printf("  [%s] %s: %d URL(s) suppressed\n", trap.Reason, trap.Pattern, trap.Suppressed)
+			if len(trap.Examples) > 0 {
+				fmt.Printf("      e.g. %s\n", trap.Examples[0])
+			}
+		}
+	}
+
+	if len(r.results.DeniedLinks) > 0 {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("DENIED LINKS\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		for i, denied := range r.results.DeniedLinks {
+			if i >= 10 {
+				fmt.Printf("  ... and %d more (see JSON report)\n", len(r.results.DeniedLinks)-i)
+				break
+			}
+			fmt.Printf("  [%s] %d link(s)\n", denied.Rule, denied.Links)
+			if len(denied.Examples) > 0 {
+				fmt.Printf("      e.g. %s\n", denied.Examples[0])
+			}
+		}
+	}
+
+	if robots := r.results.Robots; robots != nil {
+		fmt.Printf("\n%s\n", strings.Repeat("-", 60))
+		fmt.Printf("ROBOTS.TXT\n")
+		fmt.Printf("%s\n", strings.Repeat("-", 60))
+		printRobotsRules("", domain.RobotsOrigin{
+			Disallow:    robots.Disallow,
+			Allow:       robots.Allow,
+			CrawlDelay:  robots.CrawlDelay,
+			Found:       robots.Found,
+			Unreachable: robots.Unreachable,
+		})
+		for _, origin := range robots.Origins {
+			printRobotsRules(origin.Origin+" ", origin)
+		}
+		fmt.Printf("Blocked URLs: %d\n", robots.BlockedURLs)
+		for i, blocked := range robots.BlockedSample {
+			if i >= 10 {
+				fmt.Printf("  ... and %d more (see JSON report)\n", robots.BlockedURLs-int64(i))
+				break
+			}
+			fmt.Printf("  %s\n", blocked)
+		}
+	}
+
 	fmt.Printf("\n%s\n", strings.Repeat("-", 60))
 	fmt.Printf("URL VALIDATION SUMMARY\n")
 	fmt.Printf("%s\n", strings.Repeat("-", 60))
-	statusCounts := make(map[int]int)
-	for _, validation := range r.results.URLValidations {
-		statusCounts[validation.StatusCode]++
-	}
-
-	for status, count := range statusCounts {
+	for status, count := range statusCounts(r.results) {
 		fmt.Printf("HTTP %d: %d URL(s)\n", status, count)
 	}
+	if kept, total := len(r.results.URLValidations), totalCount(statusCounts(r.results)); int64(kept) < total {
+		fmt.Printf("Per-URL results kept: %d of %d (see -max-url-validations)\n", kept, total)
+	}
 	fmt.Printf("%s\n\n", strings.Repeat("=", 60))
 }
 
+// printRobotsRules prints the robots.txt rules of an origin, starting its
+// Found and Crawl-delay lines with prefix.
+func printRobotsRules(prefix string, rules domain.RobotsOrigin) {
+	switch {
+	case rules.Unreachable:
+		fmt.Printf("%sFound: unreachable (all paths disallowed per RFC 9309)\n", prefix)
+	case rules.Found:
+		fmt.Printf("%sFound: yes (%d disallow, %d allow rule(s))\n", prefix, len(rules.Disallow), len(rules.Allow))
+	default:
+		fmt.Printf("%sFound: no (all paths allowed)\n", prefix)
+	}
+	if rules.CrawlDelay != "" {
+		fmt.Printf("%sCrawl-delay: %s\n", prefix, rules.CrawlDelay)
+	}
+	for _, rule := range rules.Disallow {
+		fmt.Printf("  Disallow: %s\n", rule)
+	}
+	for _, rule := range rules.Allow {
+		fmt.Printf("  Allow: %s\n", rule)
+	}
+}
+
+// statusCounts returns the URLs tested by status. Results saved before status
+// counts were kept count their per-URL results instead.
+func statusCounts(results *domain.TestResults) map[int]int64 {
+	if results.StatusCounts != nil {
+		return results.StatusCounts
+	}
+	counts := make(map[int]int64)
+	for _, validation := range results.URLValidations {
+		counts[validation.StatusCode]++
+	}
+	return counts
+}
+
+// totalCount returns the sum of status counts.
+func totalCount(counts map[int]int64) int64 {
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
 // prepareTemplateData prepares data for HTML template rendering
 func (r *Reporter) prepareTemplateData() *TemplateData {
 	// Calculate status distribution
-	statusCounts := make(map[int]int)
-	for _, validation := range r.results.URLValidations {
-		statusCounts[validation.StatusCode]++
-	}
-
-	statusDistribution := make([]StatusDistributionEntry, 0, len(statusCounts))
-	totalValidations := len(r.results.URLValidations)
-	for status, count := range statusCounts {
+	counts := statusCounts(r.results)
+	statusDistribution := make([]StatusDistributionEntry, 0, len(counts))
+	totalValidations := totalCount(counts)
+	for status, count := range counts {
 		percentage := float64(count) / float64(totalValidations) * 100
 		statusDistribution = append(statusDistribution, StatusDistributionEntry{
 			StatusCode:  status,
-			Count:       count,
+			Count:       int(count),
 			Percentage:  percentage,
 			StatusGroup: statusGroupFromCode(status),
 		})
@@ -223,10 +497,21 @@ func (r *Reporter) prepareTemplateData() *TemplateData {
 		})
 	}
 
-	// Convert response times to milliseconds for charting
-	responseTimesMs := make([]float64, 0, len(r.results.ResponseTimes))
-	for _, entry := range r.results.ResponseTimes {
-		responseTimesMs = append(responseTimesMs, float64(entry.ResponseTime.Nanoseconds())/1000000)
+	latency := r.results.LatencyStats()
+
+	routes := make([]RouteEntry, 0, len(r.results.RouteStats))
+	for _, route := range r.results.RouteStats {
+		routes = append(routes, RouteEntry{
+			Name:      route.Name,
+			StatusMix: statusMix(route.StatusCodes),
+			ClassMix:  classMix(route.ErrorClasses),
+			P50:       route.Latency.P50,
+			P95:       route.Latency.P95,
+			P99:       route.Latency.P99,
+			Requests:  route.Requests,
+			Errors:    route.Errors,
+			ErrorRate: route.ErrorRate(),
+		})
 	}
 
 	// Determine success rate class
@@ -238,6 +523,12 @@ func (r *Reporter) prepareTemplateData() *TemplateData {
 		successRateClass = "success-low"
 	}
 
+	var bytesReceived, bandwidth string
+	if r.results.BytesReceived > 0 {
+		bytesReceived = formatBytes(r.results.BytesReceived)
+		bandwidth = formatBytes(int64(r.results.BytesPerSecond)) + "/s"
+	}
+
 	return &TemplateData{
 		Timestamp:           time.Now().Format("2006-01-02 15:04:05 MST"),
 		Duration:            r.results.Duration,
@@ -245,16 +536,167 @@ func (r *Reporter) prepareTemplateData() *TemplateData {
 		SuccessfulRequests:  r.results.SuccessfulRequests,
 		FailedRequests:      r.results.FailedRequests,
 		URLsDiscovered:      r.results.URLsDiscovered,
+		NormalizedDups:      r.results.DuplicatesByNormalization,
+		CanonicalDups:       r.results.DuplicatesByCanonical,
+		NofollowSkips:       r.results.NofollowSkips,
 		SuccessRate:         r.results.SuccessRate,
 		SuccessRateClass:    successRateClass,
 		RequestsPerSecond:   r.results.RequestsPerSecond,
+		BytesReceived:       bytesReceived,
+		Bandwidth:           bandwidth,
 		AverageResponseTime: r.results.AverageResponseTime,
 		StatusDistribution:  statusDistribution,
 		URLValidations:      urlValidations,
+		URLsTested:          totalValidations,
 		SlowRequests:        slowRequests,
+		Timing:              r.results.Timing,
+		TimingPhases:        timingPhases(r.results.Timing),
+		Routes:              routes,
+		TimeSeries:          timeSeries(r.results.TimeSeries),
+		RedirectIssues:      r.results.RedirectIssues,
+		BrokenLinks:         r.results.BrokenLinks,
+		LinkGraph:           r.results.LinkGraph,
+		CrawlTraps:          r.results.CrawlTraps,
+		DeniedLinks:         r.results.DeniedLinks,
+		Robots:              r.results.Robots,
 		Errors:              r.results.Errors,
-		ResponseTimesMs:     responseTimesMs,
+		ErrorClasses:        errorClasses(r.results),
+		Latency:             latency,
+		LatencyBuckets:      latencyBuckets(latency.Distribution),
+	}
+}
+
+// timingPhases lists the phases of a timing breakdown in request order, skipping
+// connection phases no request went through.
+func timingPhases(timing *domain.TimingBreakdown) []TimingPhaseEntry {
+	if timing == nil {
+		return nil
+	}
+	all := []TimingPhaseEntry{
+		{Name: "DNS", Stats: timing.DNS},
+		{Name: "Connect", Stats: timing.Connect},
+		{Name: "TLS", Stats: timing.TLS},
+		{Name: "TTFB", Stats: timing.TTFB},
+		{Name: "Transfer", Stats: timing.Transfer},
+		{Name: "TTLB", Stats: timing.TTLB},
+	}
+	phases := make([]TimingPhaseEntry, 0, len(all))
+	for _, phase := range all {
+		if phase.Stats.Count > 0 {
+			phases = append(phases, phase)
+		}
+	}
+	return phases
+}
+
+// timeSeries converts time-series buckets to per-second rates and milliseconds,
+// labeled by their offset into the run.
+func timeSeries(buckets []domain.TimeBucket) []TimeSeriesEntry {
+	entries := make([]TimeSeriesEntry, 0, len(buckets))
+	for _, bucket := range buckets {
+		seconds := bucket.Duration.Seconds()
+		if seconds <= 0 {
+			continue
+		}
+		entries = append(entries, TimeSeriesEntry{
+			Label:             bucket.Offset.Round(time.Second).String(),
+			RequestsPerSecond: float64(bucket.Requests) / seconds,
+			FailuresPerSecond: float64(bucket.FailureCount()) / seconds,
+			P50Ms:             float64(bucket.P50) / float64(time.Millisecond),
+			P95Ms:             float64(bucket.P95) / float64(time.Millisecond),
+			P99Ms:             float64(bucket.P99) / float64(time.Millisecond),
+			InFlight:          bucket.InFlight,
+		})
+	}
+	return entries
+}
+
+// statusMix formats response counts by status code, e.g. "200×12 404×1".
+func statusMix(codes map[int]int64) string {
+	statuses := make([]int, 0, len(codes))
+	for code := range codes {
+		statuses = append(statuses, code)
+	}
+	sort.Ints(statuses)
+	parts := make([]string, 0, len(statuses))
+	for _, code := range statuses {
+		parts = append(parts, fmt.Sprintf("%d×%d", code, codes[code]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// classMix formats error class counts in taxonomy order, e.g. "timeout_ttfb×3 http_5xx×1".
+func classMix(classes map[domain.ErrorClass]int64) string {
+	parts := make([]string, 0, len(classes))
+	for _, class := range domain.ErrorClasses {
+		if count := classes[class]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%s×%d", class, count))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// errorClasses counts the failed requests by class, in taxonomy order. Results
+// without class counts, such as those of older versions, are counted from their errors.
+func errorClasses(results *domain.TestResults) []ErrorClassEntry {
+	counted := make(map[domain.ErrorClass]int64)
+	examples := make(map[domain.ErrorClass]string)
+	for _, errInfo := range results.Errors {
+		class := errInfo.Class
+		if class == "" {
+			class = domain.ErrorClassOther
+		}
+		counted[class]++
+		if _, ok := examples[class]; !ok {
+			examples[class] = errInfo.Error
+		}
+	}
+	counts := results.ErrorClasses
+	if counts == nil {
+		counts = counted
+	}
+
+	entries := make([]ErrorClassEntry, 0, len(counts))
+	for _, class := range domain.ErrorClasses {
+		count := counts[class]
+		if count == 0 {
+			continue
+		}
+		entry := ErrorClassEntry{Class: class, Example: examples[class], Count: count}
+		if results.TotalRequests > 0 {
+			entry.Percent = float64(count) / float64(results.TotalRequests) * 100
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// latencyBuckets labels the buckets of a latency distribution by their upper bound.
+func latencyBuckets(distribution []domain.LatencyBucket) []LatencyBucketEntry {
+	buckets := make([]LatencyBucketEntry, 0, len(distribution))
+	for _, bucket := range distribution {
+		label := "≤ " + bucket.UpperBound.String()
+		if bucket.UpperBound == 0 {
+			label = "> " + domain.LatencyBucketBounds[len(domain.LatencyBucketBounds)-1].String()
+		}
+		buckets = append(buckets, LatencyBucketEntry{Label: label, Count: bucket.Count})
+	}
+	return buckets
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "1.5 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	suffix := 0
+	for value >= unit && suffix < 3 {
+		value /= unit
+		suffix++
 	}
+	return fmt.Sprintf("%.1f %s", value, []string{"B", "KB", "MB", "GB"}[suffix])
 }
 
 // statusGroupFromCode returns the status group CSS class for a given HTTP status code.
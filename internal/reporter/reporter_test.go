@@ -2,7 +2,10 @@ package reporter
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -202,14 +205,17 @@ func TestPrepareTemplateData(t *testing.T) {
 		t.Error("Expected StatusDistribution to have entries")
 	}
 
-	// Verify ResponseTimesMs conversion
-	if len(data.ResponseTimesMs) != len(results.ResponseTimes) {
-		t.Errorf("Expected %d response times, got %d", len(results.ResponseTimes), len(data.ResponseTimesMs))
+	// Verify latency statistics cover the response times
+	if data.Latency.Count != int64(len(results.ResponseTimes)) {
+		t.Errorf("Expected latency statistics over %d response times, got %d", len(results.ResponseTimes), data.Latency.Count)
 	}
 
-	// Verify conversion to milliseconds (100ms → 100.0)
-	if data.ResponseTimesMs[0] != 100.0 {
-		t.Errorf("Expected first response time 100.0ms, got %.1f", data.ResponseTimesMs[0])
+	var bucketed int64
+	for _, bucket := range data.LatencyBuckets {
+		bucketed += bucket.Count
+	}
+	if bucketed != data.Latency.Count {
+		t.Errorf("Expected the latency buckets to hold %d response times, got %d", data.Latency.Count, bucketed)
 	}
 }
 
@@ -396,8 +402,8 @@ func TestPrepareTemplateData_EmptyResults(t *testing.T) {
 		t.Errorf("Expected empty status distribution, got %d entries", len(data.StatusDistribution))
 	}
 
-	if len(data.ResponseTimesMs) != 0 {
-		t.Errorf("Expected empty response times, got %d entries", len(data.ResponseTimesMs))
+	if data.Latency.Count != 0 || len(data.LatencyBuckets) != 0 {
+		t.Errorf("Expected no latency statistics, got %d response times in %d buckets", data.Latency.Count, len(data.LatencyBuckets))
 	}
 }
 
@@ -431,3 +437,640 @@ func TestPrintSummary_WithSlowRequests(t *testing.T) {
 	reporter := New(results)
 	reporter.PrintSummary()
 }
+
+func TestGenerateHTML_RedirectIssues(t *testing.T) {
+	results := testutil.SampleResults()
+	results.RedirectIssues = []domain.RedirectIssue{
+		{URL: "https://example.com/old", Kind: domain.RedirectIssueDowngrade, Detail: "hop 1: https://example.com/old -> http://example.com/new", Hops: 1},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Redirect Issues") {
+		t.Error("Expected HTML to contain redirect issues section")
+	}
+	if !strings.Contains(html, domain.RedirectIssueDowngrade) {
+		t.Error("Expected HTML to list the downgrade issue")
+	}
+}
+
+func TestPrintSummary_WithRedirectIssues(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	for i := 0; i < 12; i++ {
+		results.RedirectIssues = append(results.RedirectIssues, domain.RedirectIssue{
+			URL: "http://example.com/loop", Kind: domain.RedirectIssueLoop, Detail: "redirects back to http://example.com/loop", Hops: 2,
+		})
+	}
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_BrokenLinks(t *testing.T) {
+	results := testutil.SampleResults()
+	results.BrokenLinks = []domain.BrokenLink{
+		{
+			URL:        "http://example.com/missing",
+			StatusCode: 404,
+			Links:      2,
+			Referrers: []domain.Referrer{
+				{SourceURL: "http://example.com/", AnchorText: "Old page"},
+				{SourceURL: "http://example.com/about"},
+			},
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Broken Links") {
+		t.Error("Expected HTML to contain broken links section")
+	}
+	if !strings.Contains(html, "http://example.com/about") || !strings.Contains(html, "Old page") {
+		t.Error("Expected HTML to list the referring pages and anchor text")
+	}
+}
+
+func TestPrintSummary_WithBrokenLinks(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	for i := 0; i < 12; i++ {
+		results.BrokenLinks = append(results.BrokenLinks, domain.BrokenLink{
+			URL:        "http://example.com/missing",
+			StatusCode: 404,
+			Links:      5,
+			Referrers: []domain.Referrer{
+				{SourceURL: "http://example.com/a", AnchorText: "A"},
+				{SourceURL: "http://example.com/b"},
+				{SourceURL: "http://example.com/c"},
+				{SourceURL: "http://example.com/d"},
+			},
+		})
+	}
+	results.BrokenLinks[0].Error = "connection refused"
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_LinkGraph(t *testing.T) {
+	results := testutil.SampleResults()
+	results.LinkGraph = &domain.LinkGraphStats{
+		Pages:      3,
+		Links:      2,
+		MostLinked: []domain.PageLinks{{URL: "http://example.com/popular", Inbound: 2}},
+		DeadEnds:   []string{"http://example.com/end"},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Link Graph") || !strings.Contains(html, "1 dead ends") {
+		t.Error("Expected HTML to contain the link graph summary")
+	}
+	if !strings.Contains(html, "http://example.com/popular") {
+		t.Error("Expected HTML to list the most-linked page")
+	}
+}
+
+func TestPrintSummary_WithLinkGraph(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	results.LinkGraph = &domain.LinkGraphStats{Pages: 1}
+	for i := 0; i < 7; i++ {
+		results.LinkGraph.MostLinked = append(results.LinkGraph.MostLinked, domain.PageLinks{URL: "http://example.com/", Inbound: 7 - i})
+	}
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_CrawlTraps(t *testing.T) {
+	results := testutil.SampleResults()
+	results.CrawlTraps = []domain.CrawlTrap{
+		{
+			Reason:     domain.TrapCalendar,
+			Pattern:    "example.com/calendar/{n}/{n}",
+			Examples:   []string{"http://example.com/calendar/2031/01"},
+			Suppressed: 42,
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Crawl Traps") {
+		t.Error("Expected HTML to contain crawl traps section")
+	}
+	if !strings.Contains(html, "example.com/calendar/{n}/{n}") || !strings.Contains(html, "http://example.com/calendar/2031/01") {
+		t.Error("Expected HTML to list the trap pattern and example URLs")
+	}
+}
+
+func TestPrintSummary_WithCrawlTraps(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	for i := 0; i < 12; i++ {
+		results.CrawlTraps = append(results.CrawlTraps, domain.CrawlTrap{
+			Reason:     domain.TrapPathVariants,
+			Pattern:    "example.com/search",
+			Examples:   []string{"http://example.com/search?q=1"},
+			Suppressed: 12 - i,
+		})
+	}
+	results.CrawlTraps[0].Examples = nil
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_NofollowSkips(t *testing.T) {
+	results := testutil.SampleResults()
+	results.NofollowSkips = domain.NofollowSkips{MetaRobots: 4, XRobotsTag: 2, RelNofollow: 3}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Nofollow Links Skipped") {
+		t.Error("Expected HTML to contain the nofollow card")
+	}
+	if !strings.Contains(html, "meta robots 4, X-Robots-Tag 2, rel=nofollow 3") {
+		t.Error("Expected HTML to break skipped links down by directive")
+	}
+
+	// The card is omitted when nothing was skipped
+	results.NofollowSkips = domain.NofollowSkips{}
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, _ = os.ReadFile(outputPath)
+	if strings.Contains(string(data), "Nofollow Links Skipped") {
+		t.Error("Expected no nofollow card when no links were skipped")
+	}
+}
+
+func TestGenerateHTML_DeniedLinks(t *testing.T) {
+	results := testutil.SampleResults()
+	results.DeniedLinks = []domain.DeniedLink{
+		{Rule: domain.DenyRuleSessionInvalidated, Examples: []string{"http://example.com/bye"}, Links: 3},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "Denied Links") {
+		t.Error("Expected HTML to contain denied links section")
+	}
+	if !strings.Contains(html, domain.DenyRuleSessionInvalidated) || !strings.Contains(html, "http://example.com/bye") {
+		t.Error("Expected HTML to list the rule and example URLs")
+	}
+}
+
+func TestPrintSummary_WithDeniedLinks(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	for i := 0; i < 12; i++ {
+		results.DeniedLinks = append(results.DeniedLinks, domain.DeniedLink{
+			Rule:     `unsubscribe`,
+			Examples: []string{"http://example.com/unsubscribe"},
+			Links:    12 - i,
+		})
+	}
+	results.DeniedLinks[0].Examples = nil
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_Robots(t *testing.T) {
+	results := testutil.SampleResults()
+	results.Robots = &domain.RobotsReport{
+		Found:         true,
+		Disallow:      []string{"/admin"},
+		Allow:         []string{"/admin/public"},
+		CrawlDelay:    "2s",
+		BlockedURLs:   4,
+		BlockedSample: []string{"http://example.com/admin/users"},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"robots.txt", "Disallow: /admin", "Allow: /admin/public", "2s", "http://example.com/admin/users"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestPrintSummary_WithRobots(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	results.Robots = &domain.RobotsReport{Found: true, Disallow: []string{"/admin"}, CrawlDelay: "1s", BlockedURLs: 30}
+	for i := 0; i < 20; i++ {
+		results.Robots.BlockedSample = append(results.Robots.BlockedSample, "http://example.com/admin/"+strconv.Itoa(i))
+	}
+	reporter := New(results)
+	reporter.PrintSummary()
+
+	results.Robots = &domain.RobotsReport{}
+	New(results).PrintSummary()
+}
+
+func TestGenerateHTML_RobotsUnreachable(t *testing.T) {
+	results := testutil.SampleResults()
+	results.Robots = &domain.RobotsReport{Unreachable: true, BlockedURLs: 1}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "unreachable (all paths disallowed per RFC 9309)") {
+		t.Error("Expected HTML to say robots.txt was unreachable")
+	}
+	if strings.Contains(html, "Disallow: /") {
+		t.Error("Expected no rules for a robots.txt that was never fetched")
+	}
+}
+
+func TestGenerateHTML_URLValidationsLimited(t *testing.T) {
+	results := testutil.SampleResults()
+	results.StatusCounts = map[int]int64{200: 40, 404: 2}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(data), "Showing the first 2 of 42 URLs tested") {
+		t.Error("Expected HTML to say only some per-URL results were kept")
+	}
+}
+
+func TestGenerateHTML_RobotsOrigins(t *testing.T) {
+	results := testutil.SampleResults()
+	results.Robots = &domain.RobotsReport{
+		Found: true,
+		Origins: []domain.RobotsOrigin{
+			{Origin: "https://example.com", Disallow: []string{"/secure"}, Found: true},
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "https://example.com") || !strings.Contains(html, "Disallow: /secure") {
+		t.Error("Expected HTML to list the rules of the other origin")
+	}
+}
+
+func TestGenerateHTML_Timing(t *testing.T) {
+	results := testutil.SampleResults()
+	results.Timing = &domain.TimingBreakdown{
+		Connect:           domain.PhaseStats{Count: 2, Mean: 3 * time.Millisecond, P95: 4 * time.Millisecond},
+		TTFB:              domain.PhaseStats{Count: 10, Mean: 42 * time.Millisecond, P99: 87 * time.Millisecond},
+		Transfer:          domain.PhaseStats{Count: 10},
+		ReusedConnections: 8,
+		NewConnections:    2,
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"Timing Breakdown", "Connect", "TTFB", "87ms", "8 reused, 2 new"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<td>DNS</td>") {
+		t.Error("Expected phases without samples to be left out")
+	}
+}
+
+func TestGenerateHTML_Bandwidth(t *testing.T) {
+	results := testutil.SampleResults()
+	results.BytesReceived = 3 * 1024 * 1024
+	results.BytesPerSecond = 512 * 1024
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"Bandwidth", "512.0 KB/s", "3.0 MB received"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		want  string
+		bytes int64
+	}{
+		{bytes: 0, want: "0 B"},
+		{bytes: 1023, want: "1023 B"},
+		{bytes: 1536, want: "1.5 KB"},
+		{bytes: 10 * 1024 * 1024, want: "10.0 MB"},
+		{bytes: 5 * 1024 * 1024 * 1024, want: "5.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.bytes); got != tt.want {
+			t.Errorf("Expected formatBytes(%d) = %q, got %q", tt.bytes, tt.want, got)
+		}
+	}
+}
+
+func TestPrintSummary_WithTiming(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	results.Timing = &domain.TimingBreakdown{
+		DNS:               domain.PhaseStats{Count: 1, Mean: time.Millisecond},
+		TTFB:              domain.PhaseStats{Count: 5, Mean: 12 * time.Millisecond},
+		Transfer:          domain.PhaseStats{Count: 5},
+		TTLB:              domain.PhaseStats{Count: 5, Mean: 15 * time.Millisecond},
+		NewConnections:    1,
+		ReusedConnections: 4,
+	}
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_Latency(t *testing.T) {
+	results := testutil.SampleResults()
+	results.Latency = &domain.LatencyStats{
+		Count:  4,
+		P50:    120 * time.Millisecond,
+		P999:   870 * time.Millisecond,
+		StdDev: 35 * time.Millisecond,
+		Distribution: []domain.LatencyBucket{
+			{UpperBound: 250 * time.Millisecond, Count: 3},
+			{UpperBound: 500 * time.Millisecond},
+			{Count: 1},
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"Response Time Percentiles", "P99.9", "870ms", "35ms", "≤ 250ms", "30s"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestPrintSummary_WithLatency(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	results.Latency = &domain.LatencyStats{
+		Count: 2,
+		P50:   10 * time.Millisecond,
+		P99:   90 * time.Millisecond,
+		Distribution: []domain.LatencyBucket{
+			{UpperBound: 10 * time.Millisecond, Count: 1},
+			{UpperBound: 25 * time.Millisecond},
+			{UpperBound: 50 * time.Millisecond},
+			{UpperBound: 100 * time.Millisecond, Count: 1},
+		},
+	}
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func sampleRouteStats() []domain.EndpointStats {
+	return []domain.EndpointStats{
+		{
+			Name:        "/users/:id",
+			Requests:    40,
+			Errors:      2,
+			StatusCodes: map[int]int64{200: 38, 500: 2},
+			Latency:     domain.LatencyStats{P50: 12 * time.Millisecond, P95: 48 * time.Millisecond, P99: 91 * time.Millisecond},
+		},
+		{
+			Name:        "POST /search",
+			Requests:    5,
+			StatusCodes: map[int]int64{200: 5},
+		},
+	}
+}
+
+func TestGenerateHTML_Routes(t *testing.T) {
+	results := testutil.SampleResults()
+	results.RouteStats = sampleRouteStats()
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"routeTable", "/users/:id", "POST /search", "5.0%", "91ms", "200×38 500×2", `data-value="48000000"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestStatusMix(t *testing.T) {
+	if got := statusMix(map[int]int64{404: 1, 200: 12, 301: 3}); got != "200×12 301×3 404×1" {
+		t.Errorf("Expected status codes in order, got %q", got)
+	}
+	if got := statusMix(nil); got != "" {
+		t.Errorf("Expected no status codes, got %q", got)
+	}
+}
+
+func TestPrintSummary_WithRoutes(t *testing.T) {
+	_ = t // Test verifies no panic occurs
+	results := testutil.SampleResults()
+	results.RouteStats = sampleRouteStats()
+	for i := range maxConsoleRoutes {
+		results.RouteStats = append(results.RouteStats, domain.EndpointStats{Name: fmt.Sprintf("/page-%d", i), Requests: 1})
+	}
+	reporter := New(results)
+	reporter.PrintSummary()
+}
+
+func TestGenerateHTML_TimeSeries(t *testing.T) {
+	results := testutil.SampleResults()
+	results.TimeSeries = []domain.TimeBucket{
+		{Offset: 0, Duration: time.Second, Requests: 40, Failures: map[domain.ErrorClass]int64{domain.ErrorClassHTTP5xx: 2}, P95: 120 * time.Millisecond, InFlight: 5},
+		{Offset: time.Second, Duration: 500 * time.Millisecond, Requests: 10},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"timeSeriesChart", "Requests/s", "Failures/s", "P95 (ms)"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
+
+func TestTimeSeries(t *testing.T) {
+	entries := timeSeries([]domain.TimeBucket{
+		{Offset: 0, Duration: time.Second, Requests: 40, Failures: map[domain.ErrorClass]int64{domain.ErrorClassConnectionRefused: 1, domain.ErrorClassHTTP4xx: 3}, P99: 250 * time.Millisecond},
+		{Offset: time.Second, Duration: 500 * time.Millisecond, Requests: 10},
+		{Offset: 1500 * time.Millisecond},
+	})
+	if len(entries) != 2 {
+		t.Fatalf("Expected empty-duration buckets to be skipped, got %d entries", len(entries))
+	}
+	if entries[0].RequestsPerSecond != 40 || entries[0].FailuresPerSecond != 4 || entries[0].P99Ms != 250 {
+		t.Errorf("Expected 40 req/s, 4 failures/s and p99 250ms, got %+v", entries[0])
+	}
+	if entries[1].Label != "1s" || entries[1].RequestsPerSecond != 20 {
+		t.Errorf("Expected a partial bucket at 1s rated 20 req/s, got %+v", entries[1])
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	results := &domain.TestResults{
+		TotalRequests: 50,
+		ErrorClasses:  map[domain.ErrorClass]int64{domain.ErrorClassHTTP5xx: 4, domain.ErrorClassTimeoutTTFB: 1},
+		Errors: []domain.ErrorInfo{
+			{URL: "http://example.com/a", Error: "timeout to host A", Class: domain.ErrorClassTimeoutTTFB},
+		},
+	}
+	classes := errorClasses(results)
+	if len(classes) != 2 {
+		t.Fatalf("Expected 2 classes, got %+v", classes)
+	}
+	if classes[0].Class != domain.ErrorClassTimeoutTTFB || classes[0].Example != "timeout to host A" || classes[0].Percent != 2 {
+		t.Errorf("Expected timeouts first with their example and a 2%% share, got %+v", classes[0])
+	}
+	if classes[1].Class != domain.ErrorClassHTTP5xx || classes[1].Count != 4 || classes[1].Example != "" {
+		t.Errorf("Expected 4 server errors without an example, got %+v", classes[1])
+	}
+
+	// Results without class counts are counted from their errors
+	legacy := &domain.TestResults{TotalRequests: 10, Errors: []domain.ErrorInfo{{Error: "a"}, {Error: "b"}}}
+	if classes := errorClasses(legacy); len(classes) != 1 || classes[0].Class != domain.ErrorClassOther || classes[0].Count != 2 {
+		t.Errorf("Expected 2 unclassified errors, got %+v", classes)
+	}
+}
+
+func TestClassMix(t *testing.T) {
+	mix := classMix(map[domain.ErrorClass]int64{domain.ErrorClassHTTP5xx: 1, domain.ErrorClassDNS: 2})
+	if mix != "dns×2 http_5xx×1" {
+		t.Errorf("Expected classes in taxonomy order, got %q", mix)
+	}
+}
+
+func TestGenerateHTML_ErrorClasses(t *testing.T) {
+	results := testutil.SampleResults()
+	results.ErrorClasses = map[domain.ErrorClass]int64{domain.ErrorClassConnectionRefused: 3}
+	results.Errors[0].Class = domain.ErrorClassConnectionRefused
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := New(results).GenerateHTML(outputPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"errorClassTable", "connection_refused", "[connection_refused] connection timeout"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package robots
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// DefaultCacheTTL is how long fetched robots.txt rules are reused. RFC 9309 §2.4
+// asks crawlers not to use a cached robots.txt for more than 24 hours.
+const DefaultCacheTTL = 24 * time.Hour
+
+// Cache holds the robots.txt rules of each origin (scheme and host), fetched the
+// first time a URL of that origin is checked and refetched once older than the TTL.
+// It is safe for concurrent use; concurrent lookups of an origin share one fetch.
+type Cache struct {
+	entries   map[string]*cacheEntry
+	now       func() time.Time
+	userAgent string
+	ttl       time.Duration
+	mu        sync.Mutex
+}
+
+// cacheEntry is the parser of one origin; ready is closed once it has been fetched.
+// err is set if the fetch was cut off by its context, and the entry is then dropped.
+type cacheEntry struct {
+	parser    *Parser
+	ready     chan struct{}
+	err       error
+	fetchedAt time.Time
+}
+
+// NewCache creates a robots.txt cache for userAgent. A ttl of zero or less means DefaultCacheTTL.
+func NewCache(userAgent string, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{
+		entries:   make(map[string]*cacheEntry),
+		now:       time.Now,
+		userAgent: userAgent,
+		ttl:       ttl,
+	}
+}
+
+// Get returns the parser for rawURL's origin, fetching its robots.txt if it is not
+// cached or has expired. A fetch error is returned only to the caller that fetched;
+// the parser then applies the RFC 9309 fallback (see FetchAndParse) and is cached too.
+// A fetch cut off by its context is not cached: the context's error is returned and
+// the next caller fetches again. The parser is never nil: a URL without a host gets
+// one allowing everything.
+func (c *Cache) Get(ctx context.Context, rawURL string) (*Parser, error) {
+	origin, err := originOf(rawURL)
+	if err != nil {
+		return New(c.userAgent), err
+	}
+
+	for {
+		c.mu.Lock()
+		entry, ok := c.entries[origin]
+		if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+			c.mu.Unlock()
+			<-entry.ready
+			if entry.err != nil && ctx.Err() == nil {
+				// The fetching caller gave up; fetch again with this context
+				continue
+			}
+			return entry.parser, entry.err
+		}
+		entry = &cacheEntry{parser: New(c.userAgent), ready: make(chan struct{}), fetchedAt: c.now()}
+		c.entries[origin] = entry
+		c.mu.Unlock()
+
+		err = entry.parser.FetchAndParse(ctx, origin)
+		if ctx.Err() != nil {
+			// Cancellation says nothing about robots.txt, so the fallback rules are not kept
+			entry.parser = New(c.userAgent)
+			entry.err = fmt.Errorf("fetching robots.txt: %w", ctx.Err())
+			c.mu.Lock()
+			if c.entries[origin] == entry {
+				delete(c.entries, origin)
+			}
+			c.mu.Unlock()
+			err = entry.err
+		}
+		close(entry.ready)
+		return entry.parser, err
+	}
+}
+
+// Put caches parser as the rules of rawURL's origin, such as rules restored from a
+// checkpoint, so they are used until the TTL expires instead of being fetched.
+func (c *Cache) Put(rawURL string, parser *Parser) error {
+	origin, err := originOf(rawURL)
+	if err != nil {
+		return err
+	}
+
+	entry := &cacheEntry{parser: parser, ready: make(chan struct{}), fetchedAt: c.now()}
+	close(entry.ready)
+	c.mu.Lock()
+	c.entries[origin] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// Snapshot returns the rules of every origin fetched so far, keyed by origin.
+// Origins still being fetched, or whose fetch was cancelled, are left out.
+func (c *Cache) Snapshot() map[string]domain.RobotsState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make(map[string]domain.RobotsState, len(c.entries))
+	for origin, entry := range c.entries {
+		select {
+		case <-entry.ready:
+			if entry.err == nil {
+				states[origin] = entry.parser.Snapshot()
+			}
+		default:
+		}
+	}
+	return states
+}
+
+// Origin returns the origin (scheme and host) of rawURL that robots.txt rules apply to.
+func Origin(rawURL string) (string, error) {
+	return originOf(rawURL)
+}
+
+// originOf returns the scheme and host of a URL, which robots.txt rules apply to.
+func originOf(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return "", fmt.Errorf("invalid URL %s: scheme and host are required", rawURL)
+	}
+	return parsedURL.Scheme + "://" + parsedURL.Host, nil
+}
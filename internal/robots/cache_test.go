@@ -0,0 +1,185 @@
+package robots
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_FetchesEachOriginOnce(t *testing.T) {
+	var fetches int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&fetches, 1)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	defer server.Close()
+
+	cache := NewCache("Lobster/1.0", time.Hour)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parser, err := cache.Get(context.Background(), server.URL+"/page")
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+			if parser.IsAllowed(server.URL + "/private/x") {
+				t.Error("Expected /private/x to be disallowed")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&fetches); got != 1 {
+		t.Errorf("Expected robots.txt to be fetched once, got %d fetches", got)
+	}
+}
+
+func TestCache_RefetchesAfterTTL(t *testing.T) {
+	var fetches int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&fetches, 1) == 1 {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := NewCache("Lobster/1.0", time.Hour)
+	cache.now = func() time.Time { return now }
+
+	parser, _ := cache.Get(context.Background(), server.URL)
+	if parser.IsAllowed(server.URL + "/page") {
+		t.Fatal("Expected the first robots.txt to disallow everything")
+	}
+
+	now = now.Add(59 * time.Minute)
+	if parser, _ = cache.Get(context.Background(), server.URL); parser.IsAllowed(server.URL + "/page") {
+		t.Error("Expected the cached rules to be used before the TTL expires")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if parser, _ = cache.Get(context.Background(), server.URL); !parser.IsAllowed(server.URL + "/page") {
+		t.Error("Expected robots.txt to be refetched after the TTL expired")
+	}
+	if got := atomic.LoadInt64(&fetches); got != 2 {
+		t.Errorf("Expected 2 fetches, got %d", got)
+	}
+}
+
+func TestCache_SeparatesOrigins(t *testing.T) {
+	strict := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	defer strict.Close()
+	open := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer open.Close()
+
+	cache := NewCache("Lobster/1.0", 0)
+	if cache.ttl != DefaultCacheTTL {
+		t.Errorf("Expected default TTL %v, got %v", DefaultCacheTTL, cache.ttl)
+	}
+
+	strictParser, _ := cache.Get(context.Background(), strict.URL+"/a")
+	openParser, _ := cache.Get(context.Background(), open.URL+"/a")
+	if strictParser.IsAllowed(strict.URL + "/a") {
+		t.Error("Expected the strict host to disallow /a")
+	}
+	if !openParser.IsAllowed(open.URL + "/a") {
+		t.Error("Expected the host without robots.txt to allow /a")
+	}
+}
+
+func TestCache_Put(t *testing.T) {
+	cache := NewCache("Lobster/1.0", time.Hour)
+
+	parser := New("Lobster/1.0")
+	parser.robotsTxtFound = true
+	parser.disallowPaths = []string{"/admin"}
+	if err := cache.Put("http://example.com/", parser); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, err := cache.Get(context.Background(), "http://example.com/admin")
+	if err != nil {
+		t.Fatalf("Expected the cached rules without fetching, got: %v", err)
+	}
+	if got != parser {
+		t.Error("Expected Get to return the parser that was put")
+	}
+
+	if err := cache.Put("/relative", parser); err == nil {
+		t.Error("Expected an error for a URL without a host")
+	}
+}
+
+func TestCache_Snapshot(t *testing.T) {
+	cache := NewCache("Lobster/1.0", time.Hour)
+
+	parser := New("Lobster/1.0")
+	parser.robotsTxtFound = true
+	parser.disallowPaths = []string{"/admin"}
+	if err := cache.Put("https://example.com/a", parser); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := cache.Put("http://example.com/", New("Lobster/1.0")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	states := cache.Snapshot()
+	if len(states) != 2 {
+		t.Fatalf("Expected rules for 2 origins, got %+v", states)
+	}
+	if got := states["https://example.com"]; !got.Found || len(got.Disallow) != 1 || got.Disallow[0] != "/admin" {
+		t.Errorf("Expected the https origin's rules, got %+v", got)
+	}
+	if got := states["http://example.com"]; got.Found {
+		t.Errorf("Expected the http origin to have no robots.txt, got %+v", got)
+	}
+}
+
+func TestCache_CancelledFetchNotCached(t *testing.T) {
+	var slow atomic.Bool
+	slow.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slow.Load() {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	defer server.Close()
+
+	cache := NewCache("Lobster/1.0", time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	parser, err := cache.Get(ctx, server.URL)
+	if err == nil {
+		t.Fatal("Expected an error when the context ends during the fetch")
+	}
+	if parser.Unreachable() || !parser.IsAllowed(server.URL+"/page") {
+		t.Error("Expected a cancelled fetch not to disallow everything")
+	}
+	if states := cache.Snapshot(); len(states) != 0 {
+		t.Errorf("Expected no rules for a cancelled fetch, got %+v", states)
+	}
+
+	slow.Store(false)
+	if parser, err = cache.Get(context.Background(), server.URL); err != nil {
+		t.Fatalf("Expected robots.txt to be fetched again, got: %v", err)
+	}
+	if parser.IsAllowed(server.URL + "/private/x") {
+		t.Error("Expected the refetched rules to disallow /private/x")
+	}
+}
@@ -0,0 +1,312 @@
+package robots
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Conformance cases for RFC 9309 (Robots Exclusion Protocol). Each case parses
+// a robots.txt as the given user agent and checks the verdict for one URL.
+func TestRFC9309_Matching(t *testing.T) {
+	tests := []struct {
+		name      string
+		robotsTxt string
+		userAgent string
+		url       string
+		allowed   bool
+	}{
+		// §2.2.1 user-agent line: the most specific group applies
+		{
+			name:      "named group replaces wildcard group",
+			robotsTxt: "User-agent: *\nDisallow: /\n\nUser-agent: Lobster\nDisallow: /private\n",
+			userAgent: "Lobster/1.0",
+			url:       "/page",
+			allowed:   true,
+		},
+		{
+			name:      "wildcard group applies when no group names the agent",
+			robotsTxt: "User-agent: OtherBot\nDisallow: /\n\nUser-agent: *\nDisallow: /private\n",
+			userAgent: "Lobster/1.0",
+			url:       "/private/x",
+			allowed:   false,
+		},
+		{
+			name:      "product token matched case-insensitively",
+			robotsTxt: "User-agent: LOBSTER\nDisallow: /x\n",
+			userAgent: "lobster/2.0 (+https://example.com)",
+			url:       "/x",
+			allowed:   false,
+		},
+		{
+			name:      "no substring matching of user agents",
+			robotsTxt: "User-agent: Lob\nDisallow: /\n",
+			userAgent: "Lobster/1.0",
+			url:       "/page",
+			allowed:   true,
+		},
+		{
+			name:      "version in robots.txt user-agent ignored",
+			robotsTxt: "User-agent: Lobster/0.9\nDisallow: /old\n",
+			userAgent: "Lobster/1.0",
+			url:       "/old",
+			allowed:   false,
+		},
+		{
+			name:      "groups naming the same agent are combined",
+			robotsTxt: "User-agent: Lobster\nDisallow: /a\n\nUser-agent: *\nDisallow: /b\n\nUser-agent: lobster\nDisallow: /c\n",
+			userAgent: "Lobster",
+			url:       "/c",
+			allowed:   false,
+		},
+		{
+			name:      "combined named groups still ignore wildcard rules",
+			robotsTxt: "User-agent: Lobster\nDisallow: /a\n\nUser-agent: *\nDisallow: /b\n\nUser-agent: lobster\nDisallow: /c\n",
+			userAgent: "Lobster",
+			url:       "/b",
+			allowed:   true,
+		},
+		{
+			name:      "consecutive user-agent lines share a group",
+			robotsTxt: "User-agent: OtherBot\nUser-agent: Lobster\nDisallow: /shared\n",
+			userAgent: "Lobster",
+			url:       "/shared",
+			allowed:   false,
+		},
+		{
+			name:      "rules before any user-agent line are ignored",
+			robotsTxt: "Disallow: /\nUser-agent: *\nDisallow: /private\n",
+			userAgent: "Lobster",
+			url:       "/page",
+			allowed:   true,
+		},
+		{
+			name:      "unknown lines do not end a group",
+			robotsTxt: "User-agent: *\nSitemap: https://example.com/sitemap.xml\nDisallow: /private\n",
+			userAgent: "Lobster",
+			url:       "/private",
+			allowed:   false,
+		},
+		{
+			name:      "field names are case-insensitive",
+			robotsTxt: "USER-AGENT: *\nDISALLOW: /private\n",
+			userAgent: "Lobster",
+			url:       "/private",
+			allowed:   false,
+		},
+
+		// §2.2.2 allow and disallow lines: the longest match wins, Allow wins ties
+		{
+			name:      "longer allow overrides disallow",
+			robotsTxt: "User-agent: *\nDisallow: /shop\nAllow: /shop/public\n",
+			userAgent: "Lobster",
+			url:       "/shop/public/item",
+			allowed:   true,
+		},
+		{
+			name:      "longer disallow overrides allow listed first",
+			robotsTxt: "User-agent: *\nAllow: /shop\nDisallow: /shop/cart\n",
+			userAgent: "Lobster",
+			url:       "/shop/cart/add",
+			allowed:   false,
+		},
+		{
+			name:      "allow wins a tie",
+			robotsTxt: "User-agent: *\nDisallow: /page\nAllow: /page\n",
+			userAgent: "Lobster",
+			url:       "/page",
+			allowed:   true,
+		},
+		{
+			name:      "wildcard patterns compete by length",
+			robotsTxt: "User-agent: *\nAllow: /*.html\nDisallow: /folder/\n",
+			userAgent: "Lobster",
+			url:       "/folder/page.html",
+			allowed:   false,
+		},
+		{
+			name:      "unmatched paths are allowed",
+			robotsTxt: "User-agent: *\nDisallow: /private\n",
+			userAgent: "Lobster",
+			url:       "/public",
+			allowed:   true,
+		},
+		{
+			name:      "empty disallow allows everything",
+			robotsTxt: "User-agent: *\nDisallow:\n",
+			userAgent: "Lobster",
+			url:       "/anything",
+			allowed:   true,
+		},
+		{
+			name:      "matching is case-sensitive",
+			robotsTxt: "User-agent: *\nDisallow: /Private\n",
+			userAgent: "Lobster",
+			url:       "/private",
+			allowed:   true,
+		},
+		{
+			name:      "query string is matched",
+			robotsTxt: "User-agent: *\nDisallow: /*?sessionid=\n",
+			userAgent: "Lobster",
+			url:       "/cart?sessionid=abc",
+			allowed:   false,
+		},
+		{
+			name:      "robots.txt is implicitly allowed",
+			robotsTxt: "User-agent: *\nDisallow: /\n",
+			userAgent: "Lobster",
+			url:       "/robots.txt",
+			allowed:   true,
+		},
+
+		// §2.2.3 special characters
+		{
+			name:      "end anchor matches at end",
+			robotsTxt: "User-agent: *\nDisallow: /*.php$\n",
+			userAgent: "Lobster",
+			url:       "/dir/index.php",
+			allowed:   false,
+		},
+		{
+			name:      "end anchor rejects longer paths",
+			robotsTxt: "User-agent: *\nDisallow: /*.php$\n",
+			userAgent: "Lobster",
+			url:       "/index.php?x=1",
+			allowed:   true,
+		},
+		{
+			name:      "end anchor backtracks past earlier occurrences",
+			robotsTxt: "User-agent: *\nDisallow: /*b$\n",
+			userAgent: "Lobster",
+			url:       "/abcb",
+			allowed:   false,
+		},
+		{
+			name:      "trailing wildcard is redundant",
+			robotsTxt: "User-agent: *\nDisallow: /fish*\n",
+			userAgent: "Lobster",
+			url:       "/fishheads",
+			allowed:   false,
+		},
+
+		// §2.2.2 percent-encoding normalization
+		{
+			name:      "encoded unreserved character in URL",
+			robotsTxt: "User-agent: *\nDisallow: /~joe/\n",
+			userAgent: "Lobster",
+			url:       "/%7Ejoe/index.html",
+			allowed:   false,
+		},
+		{
+			name:      "encoded unreserved character in pattern",
+			robotsTxt: "User-agent: *\nDisallow: /%7Ejoe/\n",
+			userAgent: "Lobster",
+			url:       "/~joe/index.html",
+			allowed:   false,
+		},
+		{
+			name:      "non-ASCII pattern matches encoded URL",
+			robotsTxt: "User-agent: *\nDisallow: /ä\n",
+			userAgent: "Lobster",
+			url:       "/%C3%A4rger",
+			allowed:   false,
+		},
+		{
+			name:      "hex case of escapes is ignored",
+			robotsTxt: "User-agent: *\nDisallow: /a%2fb\n",
+			userAgent: "Lobster",
+			url:       "/a%2Fb/c",
+			allowed:   false,
+		},
+		{
+			name:      "encoded reserved character stays distinct",
+			robotsTxt: "User-agent: *\nDisallow: /a/b\n",
+			userAgent: "Lobster",
+			url:       "/a%2Fb",
+			allowed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := New(tt.userAgent)
+			if err := parser.Parse(strings.NewReader(tt.robotsTxt)); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			parser.robotsTxtFound = true
+
+			if allowed := parser.IsAllowed(tt.url); allowed != tt.allowed {
+				t.Errorf("IsAllowed(%s) = %v, want %v", tt.url, allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+// TestRFC9309_Access checks §2.3.1 access results: how the robots.txt response
+// status decides what may be crawled.
+func TestRFC9309_Access(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		redirects int
+		allowed   bool
+		wantErr   bool
+	}{
+		{name: "200 applies rules", status: http.StatusOK, allowed: false},
+		{name: "redirects are followed", status: http.StatusOK, redirects: 5, allowed: false},
+		{name: "too many redirects means unavailable", status: http.StatusOK, redirects: 6, allowed: true},
+		{name: "403 means unavailable", status: http.StatusForbidden, allowed: true},
+		{name: "404 means unavailable", status: http.StatusNotFound, allowed: true},
+		{name: "410 means unavailable", status: http.StatusGone, allowed: true},
+		{name: "500 means unreachable", status: http.StatusInternalServerError, allowed: false, wantErr: true},
+		{name: "503 means unreachable", status: http.StatusServiceUnavailable, allowed: false, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hop, _ := strconv.Atoi(r.URL.Query().Get("hop"))
+				if hop < tt.redirects {
+					http.Redirect(w, r, "/robots.txt?hop="+strconv.Itoa(hop+1), http.StatusMovedPermanently)
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+			}))
+			defer server.Close()
+
+			parser := New("Lobster/1.0")
+			err := parser.FetchAndParse(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FetchAndParse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if allowed := parser.IsAllowed("/private/page"); allowed != tt.allowed {
+				t.Errorf("IsAllowed(/private/page) = %v, want %v", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestRFC9309_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL := server.URL
+	server.Close() // Nothing listens on the address any more
+
+	parser := New("Lobster/1.0")
+	if err := parser.FetchAndParse(context.Background(), baseURL); err == nil {
+		t.Error("Expected an error when robots.txt is unreachable")
+	}
+	if parser.IsAllowed("/page") {
+		t.Error("Expected every path to be disallowed when robots.txt is unreachable")
+	}
+	if parser.RobotsTxtFound() || !parser.Unreachable() {
+		t.Error("Expected an unreachable robots.txt not to count as found")
+	}
+	if state := parser.Snapshot(); len(state.Disallow) != 0 {
+		t.Errorf("Expected no rules from a file never fetched, got %v", state.Disallow)
+	}
+}
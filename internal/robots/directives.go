@@ -0,0 +1,83 @@
+package robots
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// metaTagPattern matches a <meta> tag
+	metaTagPattern = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	// metaNamePattern extracts a meta tag's name attribute
+	metaNamePattern = regexp.MustCompile(`(?is)\bname\s*=\s*["']([^"']*)["']`)
+	// metaContentPattern extracts a meta tag's content attribute
+	metaContentPattern = regexp.MustCompile(`(?is)\bcontent\s*=\s*["']([^"']*)["']`)
+)
+
+// valuedDirectives are X-Robots-Tag directives written as "name: value",
+// which must not be mistaken for a user-agent prefix.
+var valuedDirectives = map[string]bool{
+	"unavailable_after": true,
+	"max-snippet":       true,
+	"max-image-preview": true,
+	"max-video-preview": true,
+}
+
+// MetaNofollow reports whether an HTML page asks crawlers not to follow its links
+// through <meta name="robots"> or a meta tag naming userAgent.
+func MetaNofollow(body, userAgent string) bool {
+	for _, tag := range metaTagPattern.FindAllString(body, -1) {
+		name := metaNamePattern.FindStringSubmatch(tag)
+		content := metaContentPattern.FindStringSubmatch(tag)
+		if name == nil || content == nil {
+			continue
+		}
+		agent := strings.TrimSpace(name[1])
+		if !strings.EqualFold(agent, "robots") && !matchesUserAgent(userAgent, agent) {
+			continue
+		}
+		if hasNofollow(content[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// HeaderNofollow reports whether X-Robots-Tag header values ask crawlers not to
+// follow a page's links. Values prefixed with a user agent ("googlebot: nofollow")
+// apply only when the prefix matches userAgent.
+func HeaderNofollow(values []string, userAgent string) bool {
+	for _, value := range values {
+		if agent, rest, found := strings.Cut(value, ":"); found {
+			agent = strings.TrimSpace(agent)
+			if !valuedDirectives[strings.ToLower(agent)] && !strings.Contains(agent, ",") {
+				if !matchesUserAgent(userAgent, agent) {
+					continue
+				}
+				value = rest
+			}
+		}
+		if hasNofollow(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNofollow reports whether a comma-separated directive list includes nofollow or none.
+func hasNofollow(directives string) bool {
+	for _, directive := range strings.Split(directives, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "nofollow", "none":
+			return true
+		}
+	}
+	return false
+}
+
+// matchesUserAgent reports whether a robots agent name applies to userAgent,
+// using the same substring match as robots.txt User-agent lines.
+func matchesUserAgent(userAgent, agent string) bool {
+	return agent == "*" ||
+		(agent != "" && strings.Contains(strings.ToLower(userAgent), strings.ToLower(agent)))
+}
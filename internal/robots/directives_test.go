@@ -0,0 +1,55 @@
+package robots
+
+import "testing"
+
+func TestMetaNofollow(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"robots nofollow", `<head><meta name="robots" content="noindex, nofollow"></head>`, true},
+		{"robots none", `<meta name="ROBOTS" content="none">`, true},
+		{"content before name", `<meta content="nofollow" name="robots" />`, true},
+		{"matching agent", `<meta name="lobster" content="nofollow">`, true},
+		{"other agent", `<meta name="googlebot" content="nofollow">`, false},
+		{"index follow", `<meta name="robots" content="index, follow">`, false},
+		{"other meta", `<meta name="description" content="nofollow tips">`, false},
+		{"no meta", `<p>nofollow</p>`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetaNofollow(tt.body, "Lobster/1.0"); got != tt.want {
+				t.Errorf("MetaNofollow(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderNofollow(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   bool
+	}{
+		{"nofollow", []string{"nofollow"}, true},
+		{"list", []string{"noindex, NoFollow"}, true},
+		{"none", []string{"none"}, true},
+		{"second header", []string{"noarchive", "nofollow"}, true},
+		{"matching agent", []string{"lobster: nofollow"}, true},
+		{"wildcard agent", []string{"*: nofollow"}, true},
+		{"other agent", []string{"googlebot: nofollow"}, false},
+		{"valued directive", []string{"unavailable_after: 25 Jun 2030 15:00:00 PST"}, false},
+		{"valued directive with nofollow", []string{"nofollow, unavailable_after: 25 Jun 2030"}, true},
+		{"no header", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HeaderNofollow(tt.values, "Lobster/1.0"); got != tt.want {
+				t.Errorf("HeaderNofollow(%q) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package robots
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decision explains whether robots.txt allows a URL and which rule decided it.
+type Decision struct {
+	// Reason says in words why the URL is allowed or disallowed.
+	Reason string
+	// Rule is the deciding rule, as written ("Disallow: /admin"); empty if no rule matched.
+	Rule string
+	// Group lists the user agents of the group the rule belongs to, or of the group
+	// that applies when no rule matched; nil if no group applies.
+	Group []string
+	// RuleLine and GroupLine are the 1-based robots.txt lines of the rule and of the
+	// group's first user-agent (0 if unknown).
+	RuleLine  int
+	GroupLine int
+	// Allowed is the decision IsAllowed makes for the URL.
+	Allowed bool
+}
+
+// Explain returns the decision IsAllowed makes for rawURL, with the group and rule
+// behind it. Rules restored from a checkpoint have no group or line information.
+func (p *Parser) Explain(rawURL string) Decision {
+	if p.unreachable {
+		if path, err := matchPath(rawURL); err == nil && isRobotsTxt(path) {
+			return Decision{Allowed: true, Reason: "robots.txt itself is always allowed"}
+		}
+		return Decision{Rule: "Disallow: /", Reason: "robots.txt could not be fetched (server error or unreachable), so every path is disallowed"}
+	}
+
+	if !p.robotsTxtFound {
+		return Decision{Allowed: true, Reason: "no robots.txt was found, so every path is allowed"}
+	}
+
+	if len(p.allowPaths) == 0 && len(p.disallowPaths) == 0 {
+		if len(p.groups) == 0 {
+			return Decision{
+				Allowed: true,
+				Reason:  fmt.Sprintf("no group applies to product token %q and there is no * group, so every path is allowed", strings.ToLower(productToken(p.userAgent))),
+			}
+		}
+		return Decision{
+			Allowed:   true,
+			Group:     p.groups[0].agents,
+			GroupLine: p.groups[0].line,
+			Reason:    "the group that applies has no Allow or Disallow rules, so every path is allowed",
+		}
+	}
+
+	path, err := matchPath(rawURL)
+	if err != nil {
+		return Decision{Reason: fmt.Sprintf("the URL cannot be parsed (%v), so it is disallowed to be safe", err)}
+	}
+	if isRobotsTxt(path) {
+		return Decision{Allowed: true, Reason: "robots.txt itself is always allowed"}
+	}
+	// Rules restored without their groups are explained as one anonymous group
+	groups := p.groups
+	if groups == nil {
+		restored := &group{}
+		for _, pattern := range p.disallowPaths {
+			restored.rules = append(restored.rules, rule{path: pattern})
+		}
+		for _, pattern := range p.allowPaths {
+			restored.rules = append(restored.rules, rule{path: pattern, allow: true})
+		}
+		groups = []*group{restored}
+	}
+
+	var best *rule
+	var bestGroup *group
+	bestLen := -1
+	tie := false
+	for _, g := range groups {
+		for i := range g.rules {
+			r := &g.rules[i]
+			pattern := normalizeEncoding(r.path)
+			if !matchesPath(path, pattern) {
+				continue
+			}
+			switch {
+			case len(pattern) > bestLen:
+				best, bestGroup, bestLen, tie = r, g, len(pattern), false
+			case len(pattern) == bestLen && r.allow != best.allow:
+				tie = true
+				if r.allow {
+					best, bestGroup = r, g
+				}
+			}
+		}
+	}
+
+	if best == nil {
+		return Decision{
+			Allowed:   true,
+			Group:     groups[0].agents,
+			GroupLine: groups[0].line,
+			Reason:    fmt.Sprintf("no rule of the group matches %s, so it is allowed", path),
+		}
+	}
+
+	decision := Decision{
+		Allowed:   best.allow,
+		Rule:      ruleString(*best),
+		Group:     bestGroup.agents,
+		RuleLine:  best.line,
+		GroupLine: bestGroup.line,
+	}
+	switch {
+	case tie:
+		decision.Reason = "an Allow and a Disallow rule match equally long; Allow wins the tie"
+	case best.allow:
+		decision.Reason = fmt.Sprintf("the longest rule matching %s allows it", path)
+	default:
+		decision.Reason = fmt.Sprintf("the longest rule matching %s disallows it", path)
+	}
+	return decision
+}
+
+// ruleString formats a rule as it appears in robots.txt
+func ruleString(r rule) string {
+	if r.allow {
+		return "Allow: " + r.path
+	}
+	return "Disallow: " + r.path
+}
@@ -0,0 +1,127 @@
+package robots
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	const robotsTxt = `User-agent: googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /admin
+Allow: /admin/public
+Allow: /shop
+Disallow: /shop
+`
+
+	tests := []struct {
+		name      string
+		url       string
+		rule      string
+		reason    string
+		ruleLine  int
+		groupLine int
+		allowed   bool
+	}{
+		{
+			name:      "disallowed by the longest rule",
+			url:       "http://example.com/admin/users",
+			rule:      "Disallow: /admin",
+			reason:    "disallows it",
+			ruleLine:  5,
+			groupLine: 4,
+		},
+		{
+			name:      "allowed by a longer rule",
+			url:       "http://example.com/admin/public/page",
+			allowed:   true,
+			rule:      "Allow: /admin/public",
+			reason:    "allows it",
+			ruleLine:  6,
+			groupLine: 4,
+		},
+		{
+			name:      "Allow wins a tie",
+			url:       "http://example.com/shop/cart",
+			allowed:   true,
+			rule:      "Allow: /shop",
+			reason:    "Allow wins",
+			ruleLine:  7,
+			groupLine: 4,
+		},
+		{
+			name:      "no rule matches",
+			url:       "http://example.com/blog",
+			allowed:   true,
+			reason:    "no rule",
+			groupLine: 4,
+		},
+		{
+			name:    "robots.txt itself",
+			url:     "http://example.com/robots.txt",
+			allowed: true,
+			reason:  "always allowed",
+		},
+	}
+
+	parser := New("Lobster/1.0")
+	parser.robotsTxtFound = true
+	if err := parser.Parse(strings.NewReader(robotsTxt)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := parser.Explain(tt.url)
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Expected allowed=%v, got %v", tt.allowed, decision.Allowed)
+			}
+			if decision.Allowed != parser.IsAllowed(tt.url) {
+				t.Errorf("Expected Explain to agree with IsAllowed for %s", tt.url)
+			}
+			if decision.Rule != tt.rule || decision.RuleLine != tt.ruleLine {
+				t.Errorf("Expected rule %q on line %d, got %q on line %d", tt.rule, tt.ruleLine, decision.Rule, decision.RuleLine)
+			}
+			if decision.GroupLine != tt.groupLine {
+				t.Errorf("Expected group on line %d, got %d", tt.groupLine, decision.GroupLine)
+			}
+			if tt.groupLine > 0 && !slices.Equal(decision.Group, []string{"*"}) {
+				t.Errorf("Expected the * group, got %v", decision.Group)
+			}
+			if !strings.Contains(decision.Reason, tt.reason) {
+				t.Errorf("Expected reason containing %q, got %q", tt.reason, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestExplain_Fallbacks(t *testing.T) {
+	notFound := New("Lobster/1.0")
+	if decision := notFound.Explain("http://example.com/a"); !decision.Allowed || !strings.Contains(decision.Reason, "no robots.txt") {
+		t.Errorf("Expected no robots.txt to allow everything, got %+v", decision)
+	}
+
+	unreachable := New("Lobster/1.0")
+	unreachable.disallowAll()
+	if decision := unreachable.Explain("http://example.com/a"); decision.Allowed || !strings.Contains(decision.Reason, "could not be fetched") {
+		t.Errorf("Expected unreachable robots.txt to disallow everything, got %+v", decision)
+	}
+
+	otherAgent := New("Lobster/1.0")
+	otherAgent.robotsTxtFound = true
+	if err := otherAgent.Parse(strings.NewReader("User-agent: googlebot\nDisallow: /\n")); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if decision := otherAgent.Explain("http://example.com/a"); !decision.Allowed || !strings.Contains(decision.Reason, `"lobster"`) {
+		t.Errorf("Expected no applicable group to allow everything, got %+v", decision)
+	}
+
+	restored := New("Lobster/1.0")
+	restored.Restore(unreachable.Snapshot())
+	if decision := restored.Explain("http://example.com/a"); decision.Allowed || decision.Rule != "Disallow: /" || decision.RuleLine != 0 {
+		t.Errorf("Expected restored rules to be explained without lines, got %+v", decision)
+	}
+}
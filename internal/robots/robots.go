@@ -4,12 +4,15 @@ package robots
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
 )
 
 // Parser handles robots.txt parsing and URL compliance checking
@@ -17,8 +20,10 @@ type Parser struct {
 	userAgent      string
 	disallowPaths  []string
 	allowPaths     []string
+	groups         []*group // Groups the rules came from, kept for Explain (nil when restored)
 	crawlDelay     time.Duration
 	robotsTxtFound bool
+	unreachable    bool // Everything is disallowed because robots.txt could not be fetched
 }
 
 // New creates a new robots.txt parser
@@ -31,7 +36,16 @@ func New(userAgent string) *Parser {
 	}
 }
 
-// FetchAndParse fetches and parses robots.txt from the given base URL
+// maxRobotsRedirects is the number of consecutive redirects followed for robots.txt (RFC 9309 §2.3.1.2)
+const maxRobotsRedirects = 5
+
+// errTooManyRedirects stops the robots.txt client after maxRobotsRedirects
+var errTooManyRedirects = errors.New("too many redirects")
+
+// FetchAndParse fetches and parses robots.txt from the given base URL.
+// Status handling follows RFC 9309: redirects are followed up to five hops,
+// 4xx responses mean no restrictions, and 5xx responses or an unreachable
+// server mean everything is disallowed (an error is returned in that case).
 func (p *Parser) FetchAndParse(ctx context.Context, baseURL string) error {
 	// Parse base URL
 	parsedURL, err := url.Parse(baseURL)
@@ -42,9 +56,15 @@ func (p *Parser) FetchAndParse(ctx context.Context, baseURL string) error {
 	// Construct robots.txt URL
 	robotsURL := fmt.Sprintf("%s://%s/robots.txt", parsedURL.Scheme, parsedURL.Host)
 
-	// Create HTTP client with timeout
+	// Create HTTP client with timeout, following redirects up to the RFC 9309 minimum
 	client := &http.Client{
 		Timeout: 10 * time.Second,
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) > maxRobotsRedirects {
+				return errTooManyRedirects
+			}
+			return nil
+		},
 	}
 
 	// Create request with context
@@ -58,103 +78,128 @@ func (p *Parser) FetchAndParse(ctx context.Context, baseURL string) error {
 	// Fetch robots.txt
 	resp, err := client.Do(req)
 	if err != nil {
-		// Context errors (timeout, cancellation) should be propagated
-		// to inform caller and trigger conservative blocking
+		// A redirect loop or chain too long to follow means robots.txt is unavailable
+		if errors.Is(err, errTooManyRedirects) {
+			return nil
+		}
+		// Otherwise robots.txt is unreachable, so nothing may be crawled
+		p.disallowAll()
 		if ctx.Err() != nil {
-			p.robotsTxtFound = true
-			p.disallowPaths = append(p.disallowPaths, "/") // Disallow everything
 			return fmt.Errorf("fetching robots.txt: %w", ctx.Err())
 		}
-		// For other network errors (connection refused, DNS failure, etc.)
-		// allow crawling - we couldn't reach the server
-		return nil
+		return fmt.Errorf("fetching robots.txt: %w - disallowing all paths", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	// If robots.txt not found (404), allow crawling
-	if resp.StatusCode == http.StatusNotFound {
-		return nil
-	}
-
-	// If we got robots.txt successfully
-	if resp.StatusCode == http.StatusOK {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
 		p.robotsTxtFound = true
 		return p.Parse(resp.Body)
-	}
 
-	// For other status codes (403, 500, etc.), be conservative and block crawling
-	if resp.StatusCode >= 400 {
-		p.robotsTxtFound = true
-		p.disallowPaths = append(p.disallowPaths, "/") // Disallow everything
+	case resp.StatusCode >= 500:
+		// Server errors make robots.txt unreachable: disallow everything
+		p.disallowAll()
 		return fmt.Errorf("robots.txt returned status %d - disallowing all paths", resp.StatusCode)
+
+	default:
+		// 4xx (and unfollowed 3xx) mean robots.txt is unavailable: crawling is unrestricted
+		return nil
 	}
+}
 
-	return nil
+// disallowAll marks robots.txt unreachable, which disallows every path.
+// No file was found, so no rules are kept.
+func (p *Parser) disallowAll() {
+	p.robotsTxtFound = false
+	p.unreachable = true
+	p.groups = nil
+	p.allowPaths = p.allowPaths[:0]
+	p.disallowPaths = p.disallowPaths[:0]
 }
 
 // Maximum size of robots.txt to parse (1MB)
 const maxRobotsTxtSize = 1 * 1024 * 1024
 
-// Parse parses robots.txt content from a reader
+// group is a robots.txt group: consecutive user-agent lines and the rules following them
+type group struct {
+	agents     []string
+	allow      []string
+	disallow   []string
+	rules      []rule
+	line       int // Line of the group's first user-agent
+	crawlDelay time.Duration
+	hasDelay   bool
+}
+
+// rule is an allow or disallow line of a group, kept with its position for Explain
+type rule struct {
+	path  string
+	line  int
+	allow bool
+}
+
+// Parse parses robots.txt content from a reader and keeps the rules of the group that
+// applies to the parser's user agent (RFC 9309 §2.2.1): the groups naming its product
+// token, combined, or else the "*" groups.
 func (p *Parser) Parse(reader io.Reader) error {
 	// Limit reading to prevent memory exhaustion from malicious robots.txt
 	limitedReader := io.LimitReader(reader, maxRobotsTxtSize)
 	scanner := bufio.NewScanner(limitedReader)
-	inMatchingUserAgent := false
-	foundAnyUserAgent := false
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	var groups []*group
+	var current *group
+	inRules := false // a rule has been seen since the last user-agent line
+	lineNum := 0
 
-		// Skip comments and empty lines
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
 
-		// Remove inline comments
+		// Remove comments
 		if idx := strings.Index(line, "#"); idx != -1 {
-			line = strings.TrimSpace(line[:idx])
-			if line == "" {
-				continue
-			}
+			line = line[:idx]
 		}
 
 		// Split on first colon
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
 			continue
 		}
-
-		field := strings.TrimSpace(strings.ToLower(parts[0]))
-		value := strings.TrimSpace(parts[1])
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
 
 		switch field {
 		case "user-agent":
-			foundAnyUserAgent = true
-			// Check if this user-agent matches ours
-			if value == "*" || strings.Contains(strings.ToLower(p.userAgent), strings.ToLower(value)) {
-				inMatchingUserAgent = true
-			} else {
-				inMatchingUserAgent = false
+			// A user-agent line after rules starts a new group
+			if current == nil || inRules {
+				current = &group{line: lineNum}
+				groups = append(groups, current)
+				inRules = false
 			}
+			current.agents = append(current.agents, strings.ToLower(productToken(value)))
 
-		case "disallow":
-			if inMatchingUserAgent && value != "" {
-				p.disallowPaths = append(p.disallowPaths, value)
-			}
-
-		case "allow":
-			if inMatchingUserAgent && value != "" {
-				p.allowPaths = append(p.allowPaths, value)
+		case "disallow", "allow", "crawl-delay":
+			// Rules outside any group are ignored
+			if current == nil {
+				continue
 			}
-
-		case "crawl-delay":
-			if inMatchingUserAgent {
+			inRules = true
+			switch {
+			case value == "":
+				// An empty rule matches nothing
+			case field == "disallow":
+				current.disallow = append(current.disallow, value)
+				current.rules = append(current.rules, rule{path: value, line: lineNum})
+			case field == "allow":
+				current.allow = append(current.allow, value)
+				current.rules = append(current.rules, rule{path: value, line: lineNum, allow: true})
+			default:
 				var delay float64
-				if _, err := fmt.Sscanf(value, "%f", &delay); err == nil {
-					p.crawlDelay = time.Duration(delay * float64(time.Second))
+				if _, err := fmt.Sscanf(value, "%f", &delay); err == nil && delay >= 0 {
+					current.crawlDelay = time.Duration(delay * float64(time.Second))
+					current.hasDelay = true
 				}
 			}
 		}
@@ -164,17 +209,65 @@ func (p *Parser) Parse(reader io.Reader) error {
 		return fmt.Errorf("reading robots.txt: %w", err)
 	}
 
-	// If no rules found for our user-agent and wildcard exists, use wildcard rules
-	if !foundAnyUserAgent {
-		// No robots.txt rules at all - allow crawling
-		return nil
+	p.groups = matchingGroups(groups, strings.ToLower(productToken(p.userAgent)))
+	for _, g := range p.groups {
+		p.allowPaths = append(p.allowPaths, g.allow...)
+		p.disallowPaths = append(p.disallowPaths, g.disallow...)
+		if g.hasDelay {
+			p.crawlDelay = g.crawlDelay
+		}
 	}
 
 	return nil
 }
 
-// IsAllowed checks if the given URL path is allowed by robots.txt
+// matchingGroups returns the groups naming the product token, or the "*" groups if none do
+func matchingGroups(groups []*group, token string) []*group {
+	var named, wildcard []*group
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			if agent == token && token != "" {
+				named = append(named, g)
+				break
+			}
+			if agent == "*" {
+				wildcard = append(wildcard, g)
+				break
+			}
+		}
+	}
+	if len(named) > 0 {
+		return named
+	}
+	return wildcard
+}
+
+// productToken returns the leading product token of a user agent ("Lobster/1.0" -> "Lobster").
+// RFC 9309 limits product tokens to letters, "-" and "_"; "*" is kept as the wildcard.
+func productToken(userAgent string) string {
+	userAgent = strings.TrimSpace(userAgent)
+	if strings.HasPrefix(userAgent, "*") {
+		return "*"
+	}
+	end := strings.IndexFunc(userAgent, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && r != '-' && r != '_'
+	})
+	if end == -1 {
+		return userAgent
+	}
+	return userAgent[:end]
+}
+
+// IsAllowed checks if the given URL is allowed by robots.txt. The most specific
+// (longest) matching rule decides, and Allow wins over Disallow of equal length
+// (RFC 9309 §2.2.2). Paths are compared after percent-encoding normalization.
 func (p *Parser) IsAllowed(urlPath string) bool {
+	// If robots.txt could not be fetched, only robots.txt itself is allowed
+	if p.unreachable {
+		path, err := matchPath(urlPath)
+		return err == nil && isRobotsTxt(path)
+	}
+
 	// If no robots.txt was found, allow all paths
 	if !p.robotsTxtFound {
 		return true
@@ -185,34 +278,57 @@ func (p *Parser) IsAllowed(urlPath string) bool {
 		return true
 	}
 
-	// Parse URL to get path
-	parsedURL, err := url.Parse(urlPath)
+	path, err := matchPath(urlPath)
 	if err != nil {
 		// If we can't parse, be conservative and disallow
 		return false
 	}
+	// robots.txt itself is always allowed
+	if isRobotsTxt(path) {
+		return true
+	}
 
-	path := parsedURL.Path
+	disallowLen := longestMatch(path, p.disallowPaths)
+	if disallowLen < 0 {
+		return true
+	}
+	return longestMatch(path, p.allowPaths) >= disallowLen
+}
+
+// matchPath returns the part of a URL that robots.txt rules are matched against:
+// its path and query, percent-encoding normalized.
+func matchPath(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	path := parsedURL.EscapedPath()
 	if path == "" {
 		path = "/"
 	}
-
-	// Check Allow rules first (more specific)
-	for _, allowPath := range p.allowPaths {
-		if matchesPath(path, allowPath) {
-			return true
-		}
+	if parsedURL.RawQuery != "" || parsedURL.ForceQuery {
+		path += "?" + parsedURL.RawQuery
 	}
+	return normalizeEncoding(path), nil
+}
 
-	// Then check Disallow rules
-	for _, disallowPath := range p.disallowPaths {
-		if matchesPath(path, disallowPath) {
-			return false
+// isRobotsTxt reports whether a match path addresses robots.txt itself
+func isRobotsTxt(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	return path == "/robots.txt"
+}
+
+// longestMatch returns the length of the longest pattern matching path, or -1 if none does
+func longestMatch(path string, patterns []string) int {
+	longest := -1
+	for _, pattern := range patterns {
+		pattern = normalizeEncoding(pattern)
+		if len(pattern) > longest && matchesPath(path, pattern) {
+			longest = len(pattern)
 		}
 	}
-
-	// If no rules matched, allow by default
-	return true
+	return longest
 }
 
 // GetCrawlDelay returns the crawl delay specified in robots.txt
@@ -225,67 +341,125 @@ func (p *Parser) RobotsTxtFound() bool {
 	return p.robotsTxtFound
 }
 
+// Unreachable returns whether robots.txt could not be fetched, disallowing every path
+func (p *Parser) Unreachable() bool {
+	return p.unreachable
+}
+
 // matchesPath checks if a URL path matches a robots.txt path pattern.
-// Supports wildcards per Google's robots.txt specification:
+// Supports the RFC 9309 special characters:
 // - * matches any sequence of characters
-// - $ matches end of URL path
-// - Patterns are matched against URL path (case-sensitive)
+// - $ at the end of the pattern matches the end of the URL path
+// - Otherwise patterns match as prefixes, case-sensitively
 func matchesPath(urlPath, robotsPath string) bool {
 	// Empty pattern matches nothing
 	if robotsPath == "" {
 		return false
 	}
 
-	// Handle $ anchor at end (matches end of URL)
-	if strings.HasSuffix(robotsPath, "$") {
-		// Remove $ and check if pattern matches exactly
-		pattern := strings.TrimSuffix(robotsPath, "$")
-		if strings.Contains(pattern, "*") {
-			return matchWildcard(urlPath, pattern, true)
-		}
-		return urlPath == pattern
+	// A trailing $ anchors the pattern; otherwise it may be followed by anything
+	anchored := strings.HasSuffix(robotsPath, "$")
+	pattern := strings.TrimSuffix(robotsPath, "$")
+	if !anchored {
+		pattern += "*"
 	}
+	return matchWildcard(urlPath, pattern)
+}
 
-	// Handle wildcard patterns
-	if strings.Contains(robotsPath, "*") {
-		return matchWildcard(urlPath, robotsPath, false)
+// matchWildcard reports whether the whole of urlPath matches pattern, where * matches
+// any sequence of characters. On a mismatch the last * absorbs one more character.
+func matchWildcard(urlPath, pattern string) bool {
+	pathPos, patternPos := 0, 0
+	starPattern, starPath := -1, 0
+
+	for pathPos < len(urlPath) {
+		switch {
+		case patternPos < len(pattern) && pattern[patternPos] == '*':
+			starPattern, starPath = patternPos, pathPos
+			patternPos++
+		case patternPos < len(pattern) && pattern[patternPos] == urlPath[pathPos]:
+			patternPos++
+			pathPos++
+		case starPattern >= 0:
+			starPath++
+			pathPos, patternPos = starPath, starPattern+1
+		default:
+			return false
+		}
 	}
 
-	// Exact prefix match (standard robots.txt behavior)
-	return strings.HasPrefix(urlPath, robotsPath)
+	// Only wildcards may remain
+	for patternPos < len(pattern) && pattern[patternPos] == '*' {
+		patternPos++
+	}
+	return patternPos == len(pattern)
 }
 
-// matchWildcard matches a path against a pattern with wildcards.
-// exactEnd means the pattern must match the end of urlPath ($ anchor).
-func matchWildcard(urlPath, pattern string, exactEnd bool) bool {
-	// Split pattern on wildcards
-	parts := strings.Split(pattern, "*")
-
-	// Empty parts from consecutive wildcards or leading/trailing wildcards
-	pos := 0
-	for i, part := range parts {
-		if part == "" {
-			continue
+// normalizeEncoding brings a path or pattern to the form RFC 9309 §2.2.2 compares:
+// percent-encoded unreserved characters are decoded, other escapes use uppercase hex,
+// and octets outside printable US-ASCII are percent-encoded.
+func normalizeEncoding(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			decoded := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(decoded) {
+				b.WriteByte(decoded)
+			} else {
+				b.WriteByte('%')
+				b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+			}
+			i += 2
+		case c <= ' ' || c >= 0x7f:
+			fmt.Fprintf(&b, "%%%02X", c)
+		default:
+			b.WriteByte(c)
 		}
+	}
+	return b.String()
+}
 
-		// Find this part in the remaining path
-		idx := strings.Index(urlPath[pos:], part)
-		if idx == -1 {
-			return false
-		}
+// isUnreserved reports whether c is an RFC 3986 unreserved character
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
 
-		// For the first part, it must match at the start (prefix matching)
-		if i == 0 && idx != 0 {
-			return false
-		}
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
 
-		pos += idx + len(part)
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
 	}
+}
 
-	// If exactEnd ($), the pattern must have consumed the entire path
-	if exactEnd {
-		return pos == len(urlPath)
+// Snapshot returns the parsed rules so they can be restored without refetching
+func (p *Parser) Snapshot() domain.RobotsState {
+	return domain.RobotsState{
+		Disallow:    append([]string(nil), p.disallowPaths...),
+		Allow:       append([]string(nil), p.allowPaths...),
+		CrawlDelay:  p.crawlDelay,
+		Found:       p.robotsTxtFound,
+		Unreachable: p.unreachable,
 	}
+}
 
-	return true
+// Restore replaces the parsed rules with a snapshot taken by Snapshot
+func (p *Parser) Restore(state domain.RobotsState) {
+	p.disallowPaths = append(make([]string, 0, len(state.Disallow)), state.Disallow...)
+	p.allowPaths = append(make([]string, 0, len(state.Allow)), state.Allow...)
+	p.groups = nil
+	p.crawlDelay = state.CrawlDelay
+	p.robotsTxtFound = state.Found
+	p.unreachable = state.Unreachable
 }
@@ -452,3 +452,45 @@ func TestMatchesPath_MalformedPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	original := New("TestBot")
+	original.robotsTxtFound = true
+	if err := original.Parse(strings.NewReader("User-agent: *\nDisallow: /private\nAllow: /private/ok\nCrawl-delay: 2\n")); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	restored := New("TestBot")
+	restored.Restore(original.Snapshot())
+
+	if !restored.RobotsTxtFound() {
+		t.Error("Expected restored parser to report robots.txt found")
+	}
+	if restored.GetCrawlDelay() != 2*time.Second {
+		t.Errorf("Expected crawl delay 2s, got %v", restored.GetCrawlDelay())
+	}
+	if restored.IsAllowed("http://example.com/private/page") {
+		t.Error("Expected /private/page to be disallowed after restore")
+	}
+	if !restored.IsAllowed("http://example.com/private/ok") {
+		t.Error("Expected /private/ok to be allowed after restore")
+	}
+}
+
+func TestSnapshotRestore_Unreachable(t *testing.T) {
+	original := New("TestBot")
+	original.disallowAll()
+
+	restored := New("TestBot")
+	restored.Restore(original.Snapshot())
+
+	if !restored.Unreachable() || restored.RobotsTxtFound() {
+		t.Error("Expected restored parser to stay unreachable")
+	}
+	if restored.IsAllowed("http://example.com/page") {
+		t.Error("Expected every path to stay disallowed after restore")
+	}
+	if !restored.IsAllowed("http://example.com/robots.txt") {
+		t.Error("Expected robots.txt itself to stay allowed")
+	}
+}
@@ -0,0 +1,222 @@
+// Package routes groups request URLs into routes, such as /users/123 into
+// /users/:id, and aggregates request statistics per URL and per route.
+package routes
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+// Placeholders substituted for automatically detected path segments.
+const (
+	idPlaceholder   = ":id"
+	uuidPlaceholder = ":uuid"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Templater maps request URLs to routes: their path, with the query string dropped
+// and variable segments replaced by placeholders.
+type Templater struct {
+	templates  []template
+	autoDetect bool
+}
+
+// template is a configured route template split into path segments.
+type template struct {
+	route    string
+	segments []string
+}
+
+// NewTemplater returns a templater applying policy.
+func NewTemplater(policy domain.RoutePolicy) (*Templater, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	t := &Templater{autoDetect: !policy.NoAutoDetect}
+	for _, route := range policy.Templates {
+		t.templates = append(t.templates, template{route: route, segments: strings.Split(route[1:], "/")})
+	}
+	return t, nil
+}
+
+// Route returns the route of a request, prefixed with the method when it is not GET.
+// The first configured template matching the path wins; otherwise numeric and
+// UUID segments become :id and :uuid, unless automatic detection is off.
+func (t *Templater) Route(method, rawURL string) string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.EscapedPath()
+	}
+	if path == "" {
+		path = "/"
+	}
+	return endpointName(method, t.template(path))
+}
+
+// template returns the route of path.
+func (t *Templater) template(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for _, tmpl := range t.templates {
+		if tmpl.matches(segments) {
+			return tmpl.route
+		}
+	}
+	if !t.autoDetect {
+		return path
+	}
+
+	for i, segment := range segments {
+		switch {
+		case isNumeric(segment):
+			segments[i] = idPlaceholder
+		case uuidPattern.MatchString(segment):
+			segments[i] = uuidPlaceholder
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// matches reports whether a path split into segments matches the template.
+func (tmpl template) matches(segments []string) bool {
+	for i, want := range tmpl.segments {
+		if want == "*" {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if strings.HasPrefix(want, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segments[i] != want {
+			return false
+		}
+	}
+	return len(segments) == len(tmpl.segments)
+}
+
+// isNumeric reports whether segment is a non-empty run of decimal digits.
+func isNumeric(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// endpointName prefixes name with method when the method is not GET.
+func endpointName(method, name string) string {
+	if method == "" || method == "GET" {
+		return name
+	}
+	return method + " " + name
+}
+
+// Stats aggregate requests per URL and per route. Route histograms keep one
+// significant digit less than the run's, and URL histograms the minimum, since
+// a crawl can reach many URLs. Stats are not safe for concurrent use.
+type Stats struct {
+	templater *Templater
+	routes    map[string]*domain.EndpointStats
+	urls      map[string]*domain.EndpointStats
+	precision int
+}
+
+// NewStats returns empty statistics grouping URLs with templater, for a run
+// whose latency histograms keep precision significant digits.
+func NewStats(templater *Templater, precision int) *Stats {
+	return &Stats{
+		templater: templater,
+		routes:    make(map[string]*domain.EndpointStats),
+		urls:      make(map[string]*domain.EndpointStats),
+		precision: max(histogram.MinPrecision, precision-1),
+	}
+}
+
+// Record adds a request that got a response.
+func (s *Stats) Record(validation domain.URLValidation) {
+	s.route(validation.Method, validation.URL).Record(validation)
+	s.url(validation.Method, validation.URL).Record(validation)
+}
+
+// RecordError adds a request that got no response, failing with class.
+func (s *Stats) RecordError(method, rawURL string, class domain.ErrorClass) {
+	s.route(method, rawURL).RecordError(class)
+	s.url(method, rawURL).RecordError(class)
+}
+
+// Restore continues from statistics saved by an earlier run.
+func (s *Stats) Restore(routes, urls []domain.EndpointStats) {
+	restore := func(into map[string]*domain.EndpointStats, saved []domain.EndpointStats, precision int) {
+		for _, stats := range saved {
+			restored := stats
+			if restored.StatusCodes == nil {
+				restored.StatusCodes = make(map[int]int64)
+			}
+			if restored.Histogram == nil {
+				restored.Histogram = histogram.New(precision)
+			}
+			into[stats.Name] = &restored
+		}
+	}
+	restore(s.routes, routes, s.precision)
+	restore(s.urls, urls, histogram.MinPrecision)
+}
+
+// Routes returns the statistics of every route, ordered by one of domain.RouteSorts.
+func (s *Stats) Routes(sortBy string) []domain.EndpointStats {
+	return summarize(s.routes, sortBy)
+}
+
+// URLs returns the statistics of every URL, ordered by one of domain.RouteSorts.
+func (s *Stats) URLs(sortBy string) []domain.EndpointStats {
+	return summarize(s.urls, sortBy)
+}
+
+// route returns the statistics of the route of a request, creating them if missing.
+func (s *Stats) route(method, rawURL string) *domain.EndpointStats {
+	name := s.templater.Route(method, rawURL)
+	stats, ok := s.routes[name]
+	if !ok {
+		stats = domain.NewEndpointStats(name, s.precision)
+		s.routes[name] = stats
+	}
+	return stats
+}
+
+// url returns the statistics of a requested URL, creating them if missing.
+func (s *Stats) url(method, rawURL string) *domain.EndpointStats {
+	name := endpointName(method, rawURL)
+	stats, ok := s.urls[name]
+	if !ok {
+		stats = domain.NewEndpointStats(name, histogram.MinPrecision)
+		s.urls[name] = stats
+	}
+	return stats
+}
+
+// summarize lists endpoint statistics with their latency computed, in sortBy order.
+func summarize(endpoints map[string]*domain.EndpointStats, sortBy string) []domain.EndpointStats {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	summary := make([]domain.EndpointStats, 0, len(endpoints))
+	for _, stats := range endpoints {
+		stats.Latency = domain.SummarizeLatency(stats.Histogram)
+		summary = append(summary, *stats)
+	}
+	domain.SortEndpoints(summary, sortBy)
+	return summary
+}
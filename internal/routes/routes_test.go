@@ -0,0 +1,101 @@
+package routes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestTemplater_Route(t *testing.T) {
+	templater, err := NewTemplater(domain.RoutePolicy{Templates: []string{"/posts/:slug", "/static/*", "/users/me"}})
+	if err != nil {
+		t.Fatalf("NewTemplater() error = %v", err)
+	}
+
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{"", "http://example.com/users/123", "/users/:id"},
+		{"GET", "http://example.com/users/123/orders/45?page=2", "/users/:id/orders/:id"},
+		{"", "http://example.com/items/3f2504e0-4f89-11d3-9a0c-0305e82c3301", "/items/:uuid"},
+		{"", "http://example.com/users/me", "/users/me"},
+		{"", "http://example.com/posts/hello-world", "/posts/:slug"},
+		{"", "http://example.com/posts/hello-world/comments", "/posts/hello-world/comments"},
+		{"", "http://example.com/static/css/site.css", "/static/*"},
+		{"", "http://example.com/static", "/static/*"},
+		{"", "http://example.com", "/"},
+		{"", "http://example.com/v2/about", "/v2/about"},
+		{"POST", "http://example.com/search", "POST /search"},
+	}
+	for _, tt := range tests {
+		if got := templater.Route(tt.method, tt.url); got != tt.want {
+			t.Errorf("Route(%q, %q) = %q, want %q", tt.method, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestTemplater_NoAutoDetect(t *testing.T) {
+	templater, err := NewTemplater(domain.RoutePolicy{NoAutoDetect: true})
+	if err != nil {
+		t.Fatalf("NewTemplater() error = %v", err)
+	}
+	if got := templater.Route("", "http://example.com/users/123"); got != "/users/123" {
+		t.Errorf("Expected numeric segments kept without auto-detection, got %q", got)
+	}
+}
+
+func TestNewTemplater_InvalidTemplate(t *testing.T) {
+	if _, err := NewTemplater(domain.RoutePolicy{Templates: []string{"users/:id"}}); err == nil {
+		t.Error("Expected an error for a template without a leading slash")
+	}
+}
+
+func TestStats(t *testing.T) {
+	templater, err := NewTemplater(domain.RoutePolicy{})
+	if err != nil {
+		t.Fatalf("NewTemplater() error = %v", err)
+	}
+	stats := NewStats(templater, 3)
+
+	stats.Record(domain.URLValidation{URL: "http://example.com/users/1", StatusCode: 200, ResponseTime: 10 * time.Millisecond, IsValid: true})
+	stats.Record(domain.URLValidation{URL: "http://example.com/users/2", StatusCode: 200, ResponseTime: 30 * time.Millisecond, IsValid: true})
+	stats.Record(domain.URLValidation{URL: "http://example.com/users/2", StatusCode: 500, ResponseTime: 50 * time.Millisecond})
+	stats.RecordError("", "http://example.com/users/3", domain.ErrorClassTimeoutTTFB)
+	stats.Record(domain.URLValidation{URL: "http://example.com/", StatusCode: 200, ResponseTime: time.Millisecond, IsValid: true})
+
+	routes := stats.Routes(domain.RouteSortRequests)
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+	users := routes[0]
+	if users.Name != "/users/:id" || users.Requests != 4 || users.Errors != 2 {
+		t.Errorf("Expected /users/:id with 4 requests and 2 errors first, got %s with %d and %d", users.Name, users.Requests, users.Errors)
+	}
+	if users.StatusCodes[200] != 2 || users.StatusCodes[500] != 1 {
+		t.Errorf("Expected status mix 2x200 and 1x500, got %v", users.StatusCodes)
+	}
+	if users.ErrorClasses[domain.ErrorClassHTTP5xx] != 1 || users.ErrorClasses[domain.ErrorClassTimeoutTTFB] != 1 {
+		t.Errorf("Expected one 5xx and one timeout, got %v", users.ErrorClasses)
+	}
+	if users.Latency.Count != 3 || users.Latency.Max != 50*time.Millisecond {
+		t.Errorf("Expected latency over the 3 responses up to 50ms, got %d up to %v", users.Latency.Count, users.Latency.Max)
+	}
+	if users.Histogram.Precision() != 2 {
+		t.Errorf("Expected route histograms one digit below the run's precision, got %d", users.Histogram.Precision())
+	}
+
+	urls := stats.URLs(domain.RouteSortErrors)
+	if len(urls) != 4 || urls[0].Name != "http://example.com/users/2" {
+		t.Errorf("Expected 4 URLs with /users/2 erroring most, got %d starting with %s", len(urls), urls[0].Name)
+	}
+
+	restored := NewStats(templater, 3)
+	restored.Restore(routes, urls)
+	restored.Record(domain.URLValidation{URL: "http://example.com/users/9", StatusCode: 200, ResponseTime: 20 * time.Millisecond, IsValid: true})
+	if got := restored.Routes(domain.RouteSortRequests)[0]; got.Requests != 5 || got.Latency.Count != 4 {
+		t.Errorf("Expected restored statistics to continue, got %d requests and %d timed", got.Requests, got.Latency.Count)
+	}
+}
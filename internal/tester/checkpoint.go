@@ -0,0 +1,147 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+const (
+	// checkpointVersion is the current checkpoint file format version.
+	checkpointVersion = 1
+
+	// defaultCheckpointInterval is used when checkpointing is enabled without an interval.
+	defaultCheckpointInterval = 30 * time.Second
+)
+
+// loadCheckpoint reads a checkpoint file and checks that it can resume a run against baseURL.
+func loadCheckpoint(path, baseURL string) (*domain.CrawlCheckpoint, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Checkpoint path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	var cp domain.CrawlCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", path, err)
+	}
+
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has format version %d, expected %d", path, cp.Version, checkpointVersion)
+	}
+	if cp.BaseURL != baseURL {
+		return nil, fmt.Errorf("checkpoint %s was taken for %s, not %s", path, cp.BaseURL, baseURL)
+	}
+	if cp.Results == nil {
+		cp.Results = &domain.TestResults{}
+	}
+
+	return &cp, nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file, so an interruption
+// mid-write never leaves a truncated checkpoint behind.
+func writeCheckpoint(path string, cp *domain.CrawlCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating checkpoint file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name()) // No-op once renamed
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("syncing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// saveCheckpoint writes the current crawl state to the checkpoint file.
+// Must be called from the aggregator (or after it exits) while workers are paused,
+// so results, frontier and visited set are mutually consistent.
+func (t *Tester) saveCheckpoint() {
+	if t.config.CheckpointPath == "" {
+		return
+	}
+
+	results := *t.results
+	results.URLValidations = nil // Per-URL results stay out of checkpoints; counts cover them
+	results.TotalRequests = atomic.LoadInt64(&t.results.TotalRequests)
+	results.SuccessfulRequests = atomic.LoadInt64(&t.results.SuccessfulRequests)
+	results.FailedRequests = atomic.LoadInt64(&t.results.FailedRequests)
+	results.BytesReceived = atomic.LoadInt64(&t.results.BytesReceived)
+	results.NofollowSkips = domain.NofollowSkips{
+		MetaRobots:  atomic.LoadInt64(&t.results.NofollowSkips.MetaRobots),
+		XRobotsTag:  atomic.LoadInt64(&t.results.NofollowSkips.XRobotsTag),
+		RelNofollow: atomic.LoadInt64(&t.results.NofollowSkips.RelNofollow),
+	}
+	results.Robots = t.robotsReport()
+	results.RouteStats = t.routeStats.Routes(t.config.Routes.Sort)
+	results.URLStats = t.routeStats.URLs(t.config.Routes.Sort)
+	results.ErrorClasses = domain.TotalErrorClasses(results.RouteStats)
+	results.TimeSeries = t.series.snapshot()
+
+	crawl, err := t.crawler.Snapshot()
+	if err != nil {
+		t.logger.Warn("Failed to save checkpoint", "file", t.config.CheckpointPath, "error", err)
+		return
+	}
+	cp := &domain.CrawlCheckpoint{
+		SavedAt: time.Now(),
+		Results: &results,
+		BaseURL: t.config.BaseURL,
+		Crawl:   crawl,
+		Elapsed: t.priorElapsed + time.Since(t.startTime),
+		Version: checkpointVersion,
+		DryRun:  t.config.DryRun,
+	}
+	if !t.config.IgnoreRobots {
+		robotsState := t.robotsParser.Snapshot()
+		cp.Robots = &robotsState
+		if others := t.otherRobotsOrigins(); len(others) > 0 {
+			cp.RobotsOrigins = others
+		}
+	}
+	if t.graph != nil {
+		graph := t.graph.Snapshot()
+		cp.Graph = &graph
+	}
+
+	if err := writeCheckpoint(t.config.CheckpointPath, cp); err != nil {
+		t.logger.Warn("Failed to save checkpoint", "file", t.config.CheckpointPath, "error", err)
+		return
+	}
+	t.logger.Debug("Checkpoint saved",
+		"file", t.config.CheckpointPath,
+		"frontier", cp.Crawl.FrontierSize(),
+		"requests", results.TotalRequests)
+}
+
+// checkpointInterval returns the configured checkpoint interval or the default.
+func (t *Tester) checkpointInterval() time.Duration {
+	if t.config.CheckpointInterval > 0 {
+		return t.config.CheckpointInterval
+	}
+	return defaultCheckpointInterval
+}
@@ -0,0 +1,210 @@
+package tester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// linkedSite serves a root page linking to count leaf pages and records every hit.
+func linkedSite(t *testing.T, count int) (*httptest.Server, func() map[string]int) {
+	t.Helper()
+	var mu sync.Mutex
+	hits := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path != "/" {
+			_, _ = w.Write([]byte("<html><body>leaf</body></html>"))
+			return
+		}
+		var body strings.Builder
+		body.WriteString("<html><body>")
+		for i := 0; i < count; i++ {
+			body.WriteString(`<a href="/page` + string(rune('a'+i)) + `">link</a>`)
+		}
+		body.WriteString("</body></html>")
+		_, _ = w.Write([]byte(body.String()))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		snapshot := make(map[string]int, len(hits))
+		for path, n := range hits {
+			snapshot[path] = n
+		}
+		return snapshot
+	}
+}
+
+func TestRun_WritesCheckpoint(t *testing.T) {
+	server, _ := linkedSite(t, 3)
+	checkpointPath := filepath.Join(t.TempDir(), "crawl.ckpt")
+
+	config := testConfig(server.URL)
+	config.DryRun = true
+	config.FollowLinks = true
+	config.CheckpointPath = checkpointPath
+	config.CheckpointInterval = 10 * time.Millisecond
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if _, err := tester.Run(ctx); err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	cp, err := loadCheckpoint(checkpointPath, server.URL)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() error = %v", err)
+	}
+	if len(cp.Crawl.Frontier) != 0 {
+		t.Errorf("Expected empty frontier after a finished crawl, got %v", cp.Crawl.Frontier)
+	}
+	if len(cp.Results.URLValidations) != 0 {
+		t.Errorf("Expected per-URL results to stay out of the checkpoint, got %d", len(cp.Results.URLValidations))
+	}
+	if cp.Results.StatusCounts[200] != 4 {
+		t.Errorf("Expected 4 URLs counted with status 200 in checkpoint, got %v", cp.Results.StatusCounts)
+	}
+	if len(cp.Crawl.Visited) != 4 {
+		t.Errorf("Expected 4 visited URLs in checkpoint, got %d", len(cp.Crawl.Visited))
+	}
+	if !cp.DryRun {
+		t.Error("Expected checkpoint to record dry-run mode")
+	}
+	if cp.Elapsed <= 0 {
+		t.Errorf("Expected positive elapsed time, got %v", cp.Elapsed)
+	}
+}
+
+func TestRun_ResumeFromCheckpoint(t *testing.T) {
+	server, hits := linkedSite(t, 2)
+	checkpointPath := filepath.Join(t.TempDir(), "crawl.ckpt")
+
+	// Simulate a crawl interrupted after the root page and /pagea
+	cp := &domain.CrawlCheckpoint{
+		SavedAt: time.Now(),
+		Results: &domain.TestResults{
+			StatusCounts:  map[int]int64{200: 2},
+			TotalRequests: 2,
+		},
+		BaseURL: server.URL,
+		Crawl: domain.CrawlState{
+			Frontier:   []domain.URLTask{{URL: server.URL + "/pageb", Depth: 1}},
+			Visited:    []string{server.URL + "/", server.URL + "/pagea", server.URL + "/pageb"},
+			Discovered: 3,
+		},
+		Elapsed: time.Minute,
+		Version: checkpointVersion,
+		DryRun:  true,
+	}
+	if err := writeCheckpoint(checkpointPath, cp); err != nil {
+		t.Fatalf("writeCheckpoint() error = %v", err)
+	}
+
+	config := testConfig(server.URL)
+	config.DryRun = true
+	config.FollowLinks = true
+	config.CheckpointPath = checkpointPath
+	config.Resume = true
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	got := hits()
+	if got["/pageb"] != 1 {
+		t.Errorf("Expected frontier URL /pageb to be fetched once, got %d", got["/pageb"])
+	}
+	if got["/"] != 0 || got["/pagea"] != 0 {
+		t.Errorf("Expected already-crawled pages not to be refetched, got hits %v", got)
+	}
+
+	if len(results.URLValidations) != 1 {
+		t.Errorf("Expected only this session's validation, got %d", len(results.URLValidations))
+	}
+	if results.StatusCounts[200] != 3 {
+		t.Errorf("Expected 3 URLs counted across both sessions, got %v", results.StatusCounts)
+	}
+	if results.TotalRequests != 3 {
+		t.Errorf("Expected 3 total requests across both sessions, got %d", results.TotalRequests)
+	}
+	if results.URLsDiscovered != 3 {
+		t.Errorf("Expected 3 discovered URLs, got %d", results.URLsDiscovered)
+	}
+
+	duration, err := time.ParseDuration(results.Duration)
+	if err != nil || duration < time.Minute {
+		t.Errorf("Expected duration to include the checkpointed minute, got %q", results.Duration)
+	}
+}
+
+func TestNew_ResumeErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	mismatched := filepath.Join(dir, "other.ckpt")
+	if err := writeCheckpoint(mismatched, &domain.CrawlCheckpoint{
+		BaseURL: "http://other.example.com",
+		Version: checkpointVersion,
+	}); err != nil {
+		t.Fatalf("writeCheckpoint() error = %v", err)
+	}
+
+	future := filepath.Join(dir, "future.ckpt")
+	data, _ := json.Marshal(domain.CrawlCheckpoint{BaseURL: "http://example.com", Version: checkpointVersion + 1})
+	if err := os.WriteFile(future, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "no checkpoint path", path: "", wantErr: "requires a checkpoint file"},
+		{name: "missing file", path: filepath.Join(dir, "missing.ckpt"), wantErr: "reading checkpoint"},
+		{name: "different base URL", path: mismatched, wantErr: "was taken for http://other.example.com"},
+		{name: "unknown version", path: future, wantErr: "format version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig("http://example.com")
+			config.CheckpointPath = tt.path
+			config.Resume = true
+
+			_, err := New(config, testLogger())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package tester
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/util"
+)
+
+// requestError is the error of a request that got no response, with its class.
+type requestError struct {
+	err   error
+	class domain.ErrorClass
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+func (e *requestError) Unwrap() error { return e.err }
+
+// requestPhaseKey is the context key of a request's requestPhase.
+type requestPhaseKey struct{}
+
+// requestPhase tracks how far a request has got, so a timeout can be blamed on
+// the phase it hit. Callbacks can run on other goroutines, hence the mutex.
+type requestPhase struct {
+	timeout domain.ErrorClass // Class of a timeout now
+	mu      sync.Mutex
+}
+
+// withRequestPhase returns ctx with a requestPhase attached and fed by an
+// httptrace.ClientTrace. A request starts out waiting for a connection.
+func withRequestPhase(ctx context.Context) context.Context {
+	phase := &requestPhase{timeout: domain.ErrorClassTimeoutConnect}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { phase.enter(domain.ErrorClassTimeoutDNS) },
+		ConnectStart:         func(string, string) { phase.enter(domain.ErrorClassTimeoutConnect) },
+		TLSHandshakeStart:    func() { phase.enter(domain.ErrorClassTimeoutTLS) },
+		GotConn:              func(httptrace.GotConnInfo) { phase.enter(domain.ErrorClassTimeoutTTFB) },
+		GotFirstResponseByte: func() { phase.enter(domain.ErrorClassTimeoutBody) },
+	}
+	ctx = context.WithValue(ctx, requestPhaseKey{}, phase)
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// enter records that the request reached the phase timing out as class.
+func (p *requestPhase) enter(class domain.ErrorClass) {
+	p.mu.Lock()
+	p.timeout = class
+	p.mu.Unlock()
+}
+
+// timeoutClass returns the class of a timeout of the request sent with ctx.
+func timeoutClass(ctx context.Context) domain.ErrorClass {
+	phase, ok := ctx.Value(requestPhaseKey{}).(*requestPhase)
+	if !ok {
+		return domain.ErrorClassOther
+	}
+	phase.mu.Lock()
+	defer phase.mu.Unlock()
+	return phase.timeout
+}
+
+// requestFailed returns err, from sending req, with its class.
+func requestFailed(req *http.Request, err error) error {
+	ctx := req.Context()
+	return &requestError{err: err, class: classifyError(ctx, err, timeoutClass(ctx))}
+}
+
+// errorClass returns the class of a request's error: the one it was sent with,
+// or cancelled if the run ended, or other.
+func errorClass(ctx context.Context, err error) domain.ErrorClass {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.class
+	}
+	return classifyError(ctx, err, domain.ErrorClassOther)
+}
+
+// bodyErrorClass returns the class of an error reading a response body.
+func bodyErrorClass(ctx context.Context, err error) domain.ErrorClass {
+	switch class := classifyError(ctx, err, domain.ErrorClassTimeoutBody); class {
+	case domain.ErrorClassCancelled, domain.ErrorClassTimeoutBody:
+		return class
+	default:
+		return domain.ErrorClassBodyRead
+	}
+}
+
+// failBodyRead marks a response failed by an error reading its body.
+func (t *Tester) failBodyRead(ctx context.Context, validation *domain.URLValidation, err error) {
+	validation.IsValid = false
+	validation.ErrorClass = bodyErrorClass(ctx, err)
+	validation.Error = util.SanitizeErrorForDisplay(fmt.Sprintf("reading body: %v", err), t.config.Verbose)
+}
+
+// classifyError returns the class of err, from a request sent with ctx. timeout
+// is the class of a timeout in the phase the request had reached. Requests cut
+// short because ctx ended are cancelled by the harness, not failing.
+func classifyError(ctx context.Context, err error, timeout domain.ErrorClass) domain.ErrorClass {
+	var dnsErr *net.DNSError
+	switch {
+	case ctx.Err() != nil:
+		return domain.ErrorClassCancelled
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsTimeout {
+			return domain.ErrorClassTimeoutDNS
+		}
+		return domain.ErrorClassDNS
+	case isTimeout(err):
+		return timeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return domain.ErrorClassConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return domain.ErrorClassConnectionReset
+	case isTLSError(err), timeout == domain.ErrorClassTimeoutTLS:
+		return domain.ErrorClassTLS
+	default:
+		return domain.ErrorClassOther
+	}
+}
+
+// isTimeout reports whether err is a timeout, including the client's request timeout.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// isTLSError reports whether err comes from the TLS handshake or certificate verification.
+// Most handshake failures are unexported errors, recognized by their "tls: " prefix.
+func isTLSError(err error) bool {
+	var (
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	return errors.As(err, &recordErr) || errors.As(err, &alertErr) || errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) ||
+		strings.Contains(err.Error(), "tls: ")
+}
@@ -0,0 +1,132 @@
+package tester
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestClassifyError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dial := func(err error) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", err)}
+	}
+	tests := []struct {
+		ctx     context.Context
+		err     error
+		name    string
+		timeout domain.ErrorClass
+		want    domain.ErrorClass
+	}{
+		{name: "run ended", ctx: cancelled, err: context.Canceled, want: domain.ErrorClassCancelled},
+		{name: "unknown host", err: &net.DNSError{Err: "no such host", Name: "nowhere.invalid", IsNotFound: true}, want: domain.ErrorClassDNS},
+		{name: "DNS timeout", err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}, want: domain.ErrorClassTimeoutDNS},
+		{name: "timeout in phase", err: fmt.Errorf("get: %w", context.DeadlineExceeded), timeout: domain.ErrorClassTimeoutTTFB, want: domain.ErrorClassTimeoutTTFB},
+		{name: "refused", err: dial(syscall.ECONNREFUSED), want: domain.ErrorClassConnectionRefused},
+		{name: "reset", err: dial(syscall.ECONNRESET), want: domain.ErrorClassConnectionReset},
+		{name: "closed early", err: fmt.Errorf("get: %w", io.EOF), want: domain.ErrorClassConnectionReset},
+		{name: "untrusted certificate", err: fmt.Errorf("get: %w", x509.UnknownAuthorityError{}), want: domain.ErrorClassTLS},
+		{name: "handshake failure", err: errors.New("tls: handshake failure"), want: domain.ErrorClassTLS},
+		{name: "other", err: errors.New("unsupported protocol scheme"), timeout: domain.ErrorClassTimeoutConnect, want: domain.ErrorClassOther},
+	}
+	for _, tt := range tests {
+		ctx := tt.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if got := classifyError(ctx, tt.err, tt.timeout); got != tt.want {
+			t.Errorf("%s: classifyError() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBodyErrorClass(t *testing.T) {
+	ctx := context.Background()
+	if got := bodyErrorClass(ctx, io.ErrUnexpectedEOF); got != domain.ErrorClassBodyRead {
+		t.Errorf("Expected a truncated body to be a body read error, got %q", got)
+	}
+	if got := bodyErrorClass(ctx, context.DeadlineExceeded); got != domain.ErrorClassTimeoutBody {
+		t.Errorf("Expected a body timeout, got %q", got)
+	}
+}
+
+func TestProcessURL_ClassifiesErrors(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	t.Cleanup(slow.Close)
+	untrusted := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(untrusted.Close)
+	closed := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		want    domain.ErrorClass
+		timeout time.Duration
+	}{
+		{"refused", closed.URL, domain.ErrorClassConnectionRefused, 5 * time.Second},
+		{"timeout waiting for response", slow.URL, domain.ErrorClassTimeoutTTFB, 100 * time.Millisecond},
+		{"untrusted certificate", untrusted.URL, domain.ErrorClassTLS, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(tt.url)
+			config.RequestTimeout = tt.timeout
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			validations, errs := processTask(t, tester, tt.url)
+			if len(errs) != 1 || len(validations) != 0 {
+				t.Fatalf("Expected 1 error and no validations, got %d and %d", len(errs), len(validations))
+			}
+			if errInfo := errs[0]; errInfo.Class != tt.want {
+				t.Errorf("Expected class %q, got %q (%s)", tt.want, errInfo.Class, errInfo.Error)
+			}
+		})
+	}
+}
+
+func TestProcessURL_BodyReadError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Promise more than is sent, then drop the connection
+		w.Header().Set("Content-Length", "1000")
+		_, _ = w.Write([]byte("partial"))
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tester, err := New(testConfig(server.URL), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	validations, errs := processTask(t, tester, server.URL)
+	if len(validations) != 1 || len(errs) != 0 {
+		t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+	}
+	validation := validations[0]
+	if validation.IsValid || validation.ErrorClass != domain.ErrorClassBodyRead || validation.Error == "" {
+		t.Errorf("Expected a failed body read, got valid=%v class=%q error=%q", validation.IsValid, validation.ErrorClass, validation.Error)
+	}
+}
@@ -364,9 +364,10 @@ func TestIntegration_ErrorHandlingAndRecovery(t *testing.T) {
 		t.Error("Expected some successful requests")
 	}
 
-	// HTTP error codes (404, 500) are successful HTTP requests but invalid validations
-	// FailedRequests is only for network errors, timeouts, etc.
-	// So we verify invalid validations instead
+	// HTTP error codes (404, 500) fail the default success criteria
+	if results.FailedRequests == 0 {
+		t.Error("Expected error responses to count as failed requests")
+	}
 
 	// Success rate should be calculated correctly
 	expectedRate := float64(results.SuccessfulRequests) / float64(results.TotalRequests) * 100
@@ -0,0 +1,52 @@
+package tester
+
+import (
+	"math/rand/v2"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+// recordLatency adds a response time measurement to the latency histograms and
+// offers it to the raw sample reservoir. Called from the aggregator only.
+func (t *Tester) recordLatency(entry domain.ResponseTimeEntry) {
+	if t.results.Histograms == nil {
+		// Results from before histograms were kept start from their raw samples
+		t.results.Histograms = domain.NewLatencyHistograms(t.latencyPrecision())
+		for _, sample := range t.results.ResponseTimes {
+			t.results.Histograms.Record(sample)
+		}
+	}
+	t.results.Histograms.Record(entry)
+
+	// Reservoir sampling keeps a uniform sample of every measurement so far
+	limit := t.latencySamples()
+	switch seen := t.results.Histograms.ResponseTime.Count(); {
+	case len(t.results.ResponseTimes) < limit:
+		t.results.ResponseTimes = append(t.results.ResponseTimes, entry)
+	case limit > 0:
+		if slot := rand.Int64N(seen); slot < int64(limit) {
+			t.results.ResponseTimes[slot] = entry
+		}
+	}
+}
+
+// latencyPrecision returns the significant digits latency histograms keep.
+func (t *Tester) latencyPrecision() int {
+	if t.config.Latency.Precision > 0 {
+		return t.config.Latency.Precision
+	}
+	return histogram.DefaultPrecision
+}
+
+// latencySamples returns how many raw response times are kept.
+func (t *Tester) latencySamples() int {
+	switch {
+	case t.config.Latency.NoSamples:
+		return 0
+	case t.config.Latency.Samples > 0:
+		return t.config.Latency.Samples
+	default:
+		return domain.DefaultLatencySamples
+	}
+}
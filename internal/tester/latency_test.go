@@ -0,0 +1,79 @@
+package tester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestRecordLatency_BoundsRawSamples(t *testing.T) {
+	config := testConfig("http://example.com")
+	config.Latency.Samples = 100
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	for i := 1; i <= 10000; i++ {
+		tester.recordLatency(domain.ResponseTimeEntry{ResponseTime: time.Duration(i) * time.Millisecond})
+	}
+
+	if got := len(tester.results.ResponseTimes); got != 100 {
+		t.Errorf("Expected 100 raw samples, got %d", got)
+	}
+	if got := tester.results.Histograms.ResponseTime.Count(); got != 10000 {
+		t.Errorf("Expected the histogram to count all 10000 measurements, got %d", got)
+	}
+
+	// A uniform sample of 1..10000ms should include late measurements too
+	late := 0
+	for _, entry := range tester.results.ResponseTimes {
+		if entry.ResponseTime > 5000*time.Millisecond {
+			late++
+		}
+	}
+	if late < 20 || late > 80 {
+		t.Errorf("Expected about half the samples from the second half of the run, got %d of 100", late)
+	}
+
+	tester.calculateResults(time.Second)
+	if tester.results.MaxResponseTime != "10s" || tester.results.AverageResponseTime != "5.0005s" {
+		t.Errorf("Expected statistics over every measurement, got max %s, mean %s",
+			tester.results.MaxResponseTime, tester.results.AverageResponseTime)
+	}
+}
+
+func TestRecordLatency_NoSamples(t *testing.T) {
+	config := testConfig("http://example.com")
+	config.Latency.NoSamples = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	for range 10 {
+		tester.recordLatency(domain.ResponseTimeEntry{ResponseTime: time.Millisecond})
+	}
+	if len(tester.results.ResponseTimes) != 0 {
+		t.Errorf("Expected no raw samples, got %d", len(tester.results.ResponseTimes))
+	}
+	if got := tester.results.Histograms.ResponseTime.Count(); got != 10 {
+		t.Errorf("Expected 10 measurements in the histogram, got %d", got)
+	}
+}
+
+func TestRecordLatency_StartsFromEarlierSamples(t *testing.T) {
+	tester, err := New(testConfig("http://example.com"), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+	// Results resumed from a checkpoint written before histograms were kept
+	tester.results.ResponseTimes = []domain.ResponseTimeEntry{{ResponseTime: time.Second}}
+
+	tester.recordLatency(domain.ResponseTimeEntry{ResponseTime: 3 * time.Second})
+
+	if h := tester.results.Histograms.ResponseTime; h.Count() != 2 || h.Mean() != 2*time.Second {
+		t.Errorf("Expected the earlier sample to be counted, got %d measurements averaging %v", h.Count(), h.Mean())
+	}
+}
@@ -0,0 +1,185 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/util"
+)
+
+// processLinkCheck checks a single URL in link-check mode. Pages that will be crawled
+// are fetched with GET so their links can be extracted; everything else, including
+// off-site links, is checked with HEAD and a GET fallback.
+func (t *Tester) processLinkCheck(ctx context.Context, task domain.URLTask) {
+	if t.rateLimiter != nil {
+		if err := t.rateLimiter.Wait(ctx); err != nil {
+			return // Canceled; the task stays in the frontier
+		}
+	}
+
+	crawl := !task.External && t.config.FollowLinks && task.Depth < t.config.MaxDepth
+	var (
+		resp         *http.Response
+		responseTime time.Duration
+		err          error
+	)
+	if crawl {
+		resp, responseTime, err = t.makeHTTPRequestWithRetry(ctx, task)
+	} else {
+		resp, responseTime, err = t.checkLink(ctx, task)
+	}
+
+	// A check cut off by shutdown is interrupted, not broken: the task stays in the
+	// frontier and is checked again on resume, so it is not counted
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	atomic.AddInt64(&t.results.TotalRequests, 1)
+
+	validation := domain.URLValidation{
+		URL:          task.URL,
+		Method:       task.Method,
+		SourceURL:    task.SourceURL,
+		AnchorText:   task.AnchorText,
+		Feed:         task.Feed,
+		Script:       task.Script,
+		Confidence:   task.Confidence,
+		ResponseTime: responseTime,
+		Depth:        task.Depth,
+	}
+
+	if err != nil {
+		atomic.AddInt64(&t.results.FailedRequests, 1)
+		// A link that cannot be fetched at all is broken too
+		validation.Error = util.SanitizeErrorForDisplay(err.Error(), t.config.Verbose)
+		validation.ErrorClass = errorClass(ctx, err)
+		t.recordError(task, validation.ErrorClass, fmt.Sprintf("checking link: %v", err))
+		t.addValidation(validation)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	validation.StatusCode = resp.StatusCode
+	validation.ContentLength = resp.ContentLength
+	validation.ContentType = resp.Header.Get("Content-Type")
+
+	criteria, capture := t.successCriteria(task, resp)
+	if crawl {
+		validation.LinksFound = t.discoverLinksFromResponse(resp, task)
+	}
+	var bodyErr error
+	validation.BytesReceived, bodyErr = t.drainBody(resp, task)
+	t.checkSuccess(ctx, &validation, resp, criteria, capture, bodyErr)
+	atomic.AddInt64(&t.results.BytesReceived, validation.BytesReceived)
+	validation.Timing = requestTimingFrom(resp)
+	t.recordResponseTime(task.URL, responseTime, validation.Timing)
+	t.recordRedirects(&validation, resp, task)
+	t.checkSession(resp, task)
+
+	t.streamRequest(task, validation)
+	t.addValidation(validation)
+
+	t.logger.Debug("Link checked",
+		"url", util.SanitizeURLDefault(task.URL),
+		"method", resp.Request.Method,
+		"status", resp.StatusCode,
+		"external", task.External,
+		"links_found", validation.LinksFound)
+}
+
+// checkLink requests a URL with HEAD, retrying with GET when the server rejects
+// or mishandles HEAD. Off-site links are requested without credentials.
+func (t *Tester) checkLink(ctx context.Context, task domain.URLTask) (*http.Response, time.Duration, error) {
+	resp, responseTime, err := t.linkRequest(ctx, http.MethodHead, task.URL, !task.External)
+	if err == nil && !needsGetFallback(resp.StatusCode) {
+		return resp, responseTime, nil
+	}
+	if err == nil {
+		t.drainBody(resp, task)
+		_ = resp.Body.Close()
+	}
+	if ctx.Err() != nil {
+		return nil, responseTime, ctx.Err()
+	}
+	return t.linkRequest(ctx, http.MethodGet, task.URL, !task.External)
+}
+
+// needsGetFallback reports whether a HEAD status is unreliable enough to retry with GET.
+// Many servers answer HEAD with 405, 501 or other errors while GET succeeds;
+// only 404 and 410 are trusted as definitive.
+func needsGetFallback(status int) bool {
+	return status >= 400 && status != http.StatusNotFound && status != http.StatusGone
+}
+
+// linkRequest executes a single request for link checking, returning the response and duration.
+// Authentication is only applied when authenticate is true.
+func (t *Tester) linkRequest(ctx context.Context, method, url string, authenticate bool) (*http.Response, time.Duration, error) {
+	startTime := time.Now()
+
+	req, err := http.NewRequestWithContext(withRequestPhase(withRequestTimer(withRedirectTrace(ctx))), method, url, http.NoBody)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", t.config.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	if authenticate {
+		if err := t.applyAuthentication(req); err != nil {
+			return nil, 0, fmt.Errorf("applying authentication: %w", err)
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	responseTime := time.Since(startTime)
+	if err != nil {
+		return nil, responseTime, requestFailed(req, err)
+	}
+	return resp, responseTime, nil
+}
+
+// brokenLink returns validation as a broken link if it failed or was unsuccessful,
+// with the page it was first discovered on as its referrer.
+func brokenLink(validation domain.URLValidation, baseHost string) (domain.BrokenLink, bool) {
+	if validation.IsValid {
+		return domain.BrokenLink{}, false
+	}
+
+	link := domain.BrokenLink{
+		URL:        validation.URL,
+		Error:      validation.Error,
+		StatusCode: validation.StatusCode,
+		External:   !strings.EqualFold(hostOf(validation.URL), baseHost),
+	}
+	if validation.SourceURL != "" {
+		link.Referrers = []domain.Referrer{{SourceURL: validation.SourceURL, AnchorText: validation.AnchorText}}
+		link.Links = 1
+	}
+	return link, true
+}
+
+// addReferrers replaces the referrers of broken links with every page tracked as
+// linking to them, where any were tracked, and sorts them most-linked first.
+func addReferrers(broken []domain.BrokenLink, referrers func(string) domain.ReferrerList) {
+	for i := range broken {
+		if list := referrers(broken[i].URL); len(list.Referrers) > 0 {
+			broken[i].Referrers = list.Referrers
+			broken[i].Links = list.Links
+		}
+	}
+
+	sort.Slice(broken, func(i, j int) bool {
+		if broken[i].Links != broken[j].Links {
+			return broken[i].Links > broken[j].Links
+		}
+		return broken[i].URL < broken[j].URL
+	})
+}
@@ -0,0 +1,244 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// requestLog records requests per method and path.
+type requestLog struct {
+	mu       sync.Mutex
+	requests map[string]int
+	authed   int
+}
+
+func (l *requestLog) record(r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.requests == nil {
+		l.requests = make(map[string]int)
+	}
+	l.requests[r.Method+" "+r.URL.Path]++
+	if r.Header.Get("Authorization") != "" {
+		l.authed++
+	}
+}
+
+func (l *requestLog) count(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.requests[key]
+}
+
+func TestRun_LinkCheckReportsBrokenLinks(t *testing.T) {
+	external := &requestLog{}
+	offsite := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		external.record(r)
+		if r.URL.Path == "/dead" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(offsite.Close)
+
+	site := &requestLog{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site.record(r)
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><body>
+				<a href="/missing">Gone <b>page</b></a>
+				<a href="docs/">Docs</a>
+				<a href="` + offsite.URL + `/dead">Partner</a>
+				<a href="` + offsite.URL + `/ok">Sponsor</a>
+			</body></html>`))
+		case "/docs/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="intro">Intro</a> <a href="/missing">Missing again</a> <a href="/head-405">Legacy</a>`))
+		case "/docs/intro":
+			w.WriteHeader(http.StatusOK)
+		case "/head-405":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := testConfig(server.URL)
+	config.FollowLinks = true
+	config.MaxDepth = 2
+	config.LinkCheck = true
+	config.CheckExternal = true
+	config.Auth = &domain.AuthConfig{Type: "bearer", Token: "secret"}
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	if len(results.BrokenLinks) != 2 {
+		t.Fatalf("Expected 2 broken links, got %+v", results.BrokenLinks)
+	}
+
+	// Most-linked first: /missing is linked from two pages
+	missing := results.BrokenLinks[0]
+	if missing.URL != server.URL+"/missing" || missing.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected %s/missing (404) first, got %s (%d)", server.URL, missing.URL, missing.StatusCode)
+	}
+	if missing.Links != 2 || len(missing.Referrers) != 2 {
+		t.Errorf("Expected 2 referrers for /missing, got %+v", missing.Referrers)
+	}
+	if missing.Referrers[0].AnchorText != "Gone page" && missing.Referrers[1].AnchorText != "Gone page" {
+		t.Errorf("Expected anchor text %q among referrers, got %+v", "Gone page", missing.Referrers)
+	}
+
+	dead := results.BrokenLinks[1]
+	if dead.URL != offsite.URL+"/dead" || !dead.External {
+		t.Errorf("Expected external %s/dead, got %+v", offsite.URL, dead)
+	}
+	if len(dead.Referrers) != 1 || dead.Referrers[0].SourceURL != server.URL+"/" || dead.Referrers[0].AnchorText != "Partner" {
+		t.Errorf("Expected /dead to be linked from the home page as %q, got %+v", "Partner", dead.Referrers)
+	}
+
+	// Relative links resolve against their page, not the base URL;
+	// pages at the maximum depth are only checked, with HEAD
+	if got := site.count("HEAD /docs/intro"); got != 1 {
+		t.Errorf("Expected /docs/intro to be checked once with HEAD, got %d", got)
+	}
+	// Each URL is checked once; HEAD is retried with GET only when rejected
+	if got := site.count("HEAD /missing") + site.count("GET /missing"); got != 1 {
+		t.Errorf("Expected /missing to be requested once, got %d", got)
+	}
+	if site.count("HEAD /head-405") != 1 || site.count("GET /head-405") != 1 {
+		t.Errorf("Expected HEAD then GET fallback for /head-405, got %v", site.requests)
+	}
+	// Off-site links are checked with HEAD, never crawled or sent credentials
+	if external.count("GET /ok") != 0 || external.count("HEAD /ok") != 1 {
+		t.Errorf("Expected a single HEAD for the external link, got %v", external.requests)
+	}
+	if external.authed != 0 {
+		t.Errorf("Expected no credentials sent off-site, got %d authenticated request(s)", external.authed)
+	}
+}
+
+func TestProcessLinkCheck_RecordsTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	config := testConfig(server.URL)
+	config.LinkCheck = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	validations, errs := processTask(t, tester, server.URL)
+	if len(validations) != 1 || len(errs) != 0 {
+		t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+	}
+	if timing := validations[0].Timing; timing == nil || timing.TTLB <= 0 {
+		t.Errorf("Expected the link check's phase timing to be recorded, got %+v", timing)
+	}
+}
+
+func TestProcessLinkCheck_InterruptedNotCounted(t *testing.T) {
+	requested := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	config := testConfig(server.URL)
+	config.LinkCheck = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requested
+		cancel()
+	}()
+	tester.processLinkCheck(ctx, domain.URLTask{URL: server.URL})
+
+	// The task stays in the frontier to be checked on resume, so nothing is counted yet
+	if total, failed := tester.results.TotalRequests, tester.results.FailedRequests; total != 0 || failed != 0 {
+		t.Errorf("Expected an interrupted check not to be counted, got %d requests and %d failures", total, failed)
+	}
+	if len(tester.validationsCh) != 0 || len(tester.errorsCh) != 0 {
+		t.Error("Expected no result for an interrupted check")
+	}
+}
+
+func TestNeedsGetFallback(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusMovedPermanently, false},
+		{http.StatusNotFound, false},
+		{http.StatusGone, false},
+		{http.StatusForbidden, true},
+		{http.StatusMethodNotAllowed, true},
+		{http.StatusNotImplemented, true},
+	}
+
+	for _, tt := range tests {
+		if got := needsGetFallback(tt.status); got != tt.want {
+			t.Errorf("needsGetFallback(%d) = %v, expected %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestBrokenLink_FallsBackToSource(t *testing.T) {
+	validations := []domain.URLValidation{
+		{URL: "http://example.com/ok", StatusCode: 200, IsValid: true},
+		{URL: "http://example.com/gone", StatusCode: 410, SourceURL: "http://example.com/", AnchorText: "Gone"},
+		{URL: "http://other.com/down", Error: "connection refused", SourceURL: "http://example.com/links"},
+	}
+	noReferrers := func(string) domain.ReferrerList { return domain.ReferrerList{} }
+
+	var broken []domain.BrokenLink
+	for _, validation := range validations {
+		if link, ok := brokenLink(validation, "example.com"); ok {
+			broken = append(broken, link)
+		}
+	}
+	addReferrers(broken, noReferrers)
+
+	if len(broken) != 2 {
+		t.Fatalf("Expected 2 broken links, got %+v", broken)
+	}
+	if broken[0].URL != "http://example.com/gone" || broken[0].Referrers[0].AnchorText != "Gone" {
+		t.Errorf("Expected /gone with its discovering page, got %+v", broken[0])
+	}
+	if !broken[1].External || !strings.Contains(broken[1].Error, "refused") {
+		t.Errorf("Expected external failed link, got %+v", broken[1])
+	}
+}
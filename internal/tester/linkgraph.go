@@ -0,0 +1,36 @@
+package tester
+
+import (
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/linkgraph"
+)
+
+// recordLink adds a link from task's page to the target of result to the link graph.
+// Links that were out of scope or unparseable have no target and are skipped.
+func (t *Tester) recordLink(task domain.URLTask, result domain.AddURLResult, targetDepth int) {
+	if t.graph == nil || result.URL == "" {
+		return
+	}
+	t.graph.AddLink(task.URL, task.Depth, result.URL, targetDepth)
+}
+
+// exportLinkGraph summarizes the link graph and writes it to the configured file.
+// Must be called after the aggregator has finished.
+func (t *Tester) exportLinkGraph() {
+	if t.graph == nil {
+		return
+	}
+
+	graph := t.graph.Snapshot()
+	stats := linkgraph.Stats(graph)
+	t.results.LinkGraph = &stats
+
+	if err := linkgraph.WriteFile(t.config.GraphPath, graph); err != nil {
+		t.logger.Error("Failed to write link graph", "file", t.config.GraphPath, "error", err)
+		return
+	}
+	t.logger.Info("Link graph saved",
+		"file", t.config.GraphPath,
+		"pages", stats.Pages,
+		"links", stats.Links)
+}
@@ -0,0 +1,88 @@
+package tester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_ExportsLinkGraph(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<a href="/a">A</a> <a href="/b">B</a>`))
+		case "/a":
+			_, _ = w.Write([]byte(`<a href="/b">B</a> <a href="/">Home</a>`))
+		case "/b":
+			_, _ = w.Write([]byte(`no links here`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	config := testConfig(server.URL + "/")
+	config.FollowLinks = true
+	config.MaxDepth = 2
+	config.GraphPath = filepath.Join(dir, "graph.dot")
+	config.CheckpointPath = filepath.Join(dir, "crawl.ckpt")
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	stats := results.LinkGraph
+	if stats == nil {
+		t.Fatal("Expected link graph stats in results")
+	}
+	if stats.Pages != 3 || stats.Links != 4 {
+		t.Errorf("Expected 3 pages and 4 links, got %d and %d", stats.Pages, stats.Links)
+	}
+	if len(stats.DeadEnds) != 1 || !strings.HasSuffix(stats.DeadEnds[0], "/b") {
+		t.Errorf("Expected /b to be the only dead end, got %v", stats.DeadEnds)
+	}
+	if len(stats.MostLinked) == 0 || !strings.HasSuffix(stats.MostLinked[0].URL, "/b") {
+		t.Errorf("Expected /b to be the most linked page, got %+v", stats.MostLinked)
+	}
+
+	dot, err := os.ReadFile(config.GraphPath)
+	if err != nil {
+		t.Fatalf("Expected graph file, got error: %v", err)
+	}
+	if !strings.HasPrefix(string(dot), "digraph crawl {") || !strings.Contains(string(dot), "status=200") {
+		t.Errorf("Expected DOT graph with page statuses, got:\n%s", dot)
+	}
+
+	// The graph is checkpointed so a resumed crawl keeps the links found so far
+	data, err := os.ReadFile(config.CheckpointPath)
+	if err != nil {
+		t.Fatalf("Expected checkpoint file, got error: %v", err)
+	}
+	var cp struct {
+		Graph *struct {
+			Edges []json.RawMessage `json:"edges"`
+		} `json:"graph"`
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		t.Fatalf("Failed to parse checkpoint: %v", err)
+	}
+	if cp.Graph == nil || len(cp.Graph.Edges) != 4 {
+		t.Errorf("Expected 4 checkpointed edges, got %+v", cp.Graph)
+	}
+}
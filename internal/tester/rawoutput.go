@@ -0,0 +1,153 @@
+package tester
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+const (
+	// rawOutputBuffer is how many records can wait for the writer before new ones are dropped.
+	rawOutputBuffer = 8192
+
+	// rawOutputWriteBuffer is the size of the buffer in front of the raw output file.
+	rawOutputWriteBuffer = 64 * 1024
+)
+
+// rawWriter streams request records to a file as NDJSON from its own goroutine,
+// so a slow disk never holds up workers: when the writer falls behind, records
+// are dropped and counted instead. The file is flushed whenever the writer
+// catches up, so a crash loses little more than the records still queued.
+type rawWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	records chan domain.RequestRecord
+	done    chan struct{}
+	err     error // First write error, read after done is closed
+	dropped int64 // Records dropped because the writer fell behind (atomic)
+}
+
+// newRawWriter creates path, or appends to it when resuming, and starts writing.
+func newRawWriter(path string, resume bool) (*rawWriter, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &rawWriter{
+		file:    file,
+		buf:     bufio.NewWriterSize(file, rawOutputWriteBuffer),
+		records: make(chan domain.RequestRecord, rawOutputBuffer),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// run encodes records until the channel is closed. After a write error the
+// remaining records are discarded.
+func (w *rawWriter) run() {
+	defer close(w.done)
+
+	encoder := json.NewEncoder(w.buf)
+	for record := range w.records {
+		if w.err != nil {
+			continue
+		}
+		if err := encoder.Encode(record); err != nil {
+			w.err = err
+			continue
+		}
+		if len(w.records) == 0 {
+			w.err = w.buf.Flush()
+		}
+	}
+}
+
+// write queues record without blocking, dropping it if the writer has fallen behind.
+func (w *rawWriter) write(record domain.RequestRecord) {
+	select {
+	case w.records <- record:
+	default:
+		atomic.AddInt64(&w.dropped, 1)
+	}
+}
+
+// close writes the queued records and closes the file. No record may be
+// written after close is called.
+func (w *rawWriter) close() error {
+	close(w.records)
+	<-w.done
+
+	err := w.err
+	if err == nil {
+		err = w.buf.Flush()
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+// openRawOutput starts streaming requests to the configured raw output file, if any.
+func (t *Tester) openRawOutput(resume bool) error {
+	if t.config.RawOutputPath == "" {
+		return nil
+	}
+	raw, err := newRawWriter(t.config.RawOutputPath, resume)
+	if err != nil {
+		return fmt.Errorf("opening raw output: %w", err)
+	}
+	t.raw = raw
+	return nil
+}
+
+// closeRawOutput finishes the raw output file. Must be called after the workers have stopped.
+func (t *Tester) closeRawOutput() {
+	if t.raw == nil {
+		return
+	}
+	if dropped := atomic.LoadInt64(&t.raw.dropped); dropped > 0 {
+		t.logger.Warn("Raw output records dropped",
+			"file", t.config.RawOutputPath,
+			"dropped_count", dropped,
+			"hint", "Writing fell behind the workers; use a faster disk or lower -concurrency")
+	}
+	if err := t.raw.close(); err != nil {
+		t.logger.Error("Failed to write raw output", "file", t.config.RawOutputPath, "error", err)
+	}
+	t.raw = nil
+}
+
+// streamRequest writes a finished request to the raw output file, if any.
+// Requests that got no response have only their error set in validation.
+func (t *Tester) streamRequest(task domain.URLTask, validation domain.URLValidation) {
+	if t.raw == nil {
+		return
+	}
+
+	method := task.Method
+	if method == "" {
+		method = "GET"
+	}
+	record := domain.RequestRecord{
+		Timestamp:     time.Now(),
+		URL:           task.URL,
+		Method:        method,
+		Error:         validation.Error,
+		ErrorClass:    validation.FailureClass(),
+		Timing:        validation.Timing,
+		ResponseTime:  validation.ResponseTime,
+		BytesReceived: validation.BytesReceived,
+		StatusCode:    validation.StatusCode,
+		Worker:        task.Worker,
+	}
+	t.raw.write(record)
+}
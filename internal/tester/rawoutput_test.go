@@ -0,0 +1,130 @@
+package tester
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// readRawOutput parses every line of a raw output file.
+func readRawOutput(t *testing.T, path string) []domain.RequestRecord {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open raw output: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var records []domain.RequestRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record domain.RequestRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Invalid raw output line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read raw output: %v", err)
+	}
+	return records
+}
+
+func TestRun_StreamsRawOutput(t *testing.T) {
+	server, _ := linkedSite(t, 3)
+
+	config := testConfig(server.URL)
+	config.FollowLinks = true
+	config.RawOutputPath = filepath.Join(t.TempDir(), "requests.ndjson")
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	records := readRawOutput(t, config.RawOutputPath)
+	if int64(len(records)) != results.TotalRequests || len(records) != 4 {
+		t.Fatalf("Expected a line for each of the 4 requests, got %d lines for %d requests", len(records), results.TotalRequests)
+	}
+	for _, record := range records {
+		if record.Method != "GET" || record.StatusCode != 200 || record.ErrorClass != "" {
+			t.Errorf("Expected a successful GET, got %+v", record)
+		}
+		if record.Worker < 0 || record.Worker >= config.Concurrency {
+			t.Errorf("Expected a worker ID below %d, got %d", config.Concurrency, record.Worker)
+		}
+		if record.Timing == nil || record.ResponseTime <= 0 || record.BytesReceived == 0 || record.Timestamp.IsZero() {
+			t.Errorf("Expected timings, bytes and a timestamp, got %+v", record)
+		}
+	}
+}
+
+func TestStreamRequest_FailureClasses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	raw, err := newRawWriter(path, false)
+	if err != nil {
+		t.Fatalf("newRawWriter() error = %v", err)
+	}
+	tester := &Tester{raw: raw}
+
+	task := domain.URLTask{URL: "http://example.com/form", Method: "POST", Worker: 3}
+	tester.streamRequest(task, domain.URLValidation{StatusCode: 503})
+	tester.streamRequest(task, domain.URLValidation{Error: "connection refused", ErrorClass: domain.ErrorClassConnectionRefused})
+	if err := raw.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+
+	records := readRawOutput(t, path)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(records))
+	}
+	if records[0].ErrorClass != domain.ErrorClassHTTP5xx || records[0].Method != "POST" || records[0].Worker != 3 {
+		t.Errorf("Expected a 5xx POST from worker 3, got %+v", records[0])
+	}
+	if records[1].ErrorClass != domain.ErrorClassConnectionRefused || records[1].Error != "connection refused" {
+		t.Errorf("Expected a refused connection, got %+v", records[1])
+	}
+}
+
+func TestRawWriter_DropsWhenBehind(t *testing.T) {
+	// A writer whose goroutine never reads stands in for a stalled disk
+	raw := &rawWriter{records: make(chan domain.RequestRecord, 1)}
+
+	raw.write(domain.RequestRecord{URL: "http://example.com/a"})
+	raw.write(domain.RequestRecord{URL: "http://example.com/b"})
+
+	if raw.dropped != 1 {
+		t.Errorf("Expected the record beyond the buffer to be dropped, got %d dropped", raw.dropped)
+	}
+}
+
+func TestNewRawWriter_AppendsOnResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	for _, resume := range []bool{false, true} {
+		raw, err := newRawWriter(path, resume)
+		if err != nil {
+			t.Fatalf("newRawWriter() error = %v", err)
+		}
+		raw.write(domain.RequestRecord{URL: "http://example.com/"})
+		if err := raw.close(); err != nil {
+			t.Fatalf("close() error = %v", err)
+		}
+	}
+
+	if records := readRawOutput(t, path); len(records) != 2 {
+		t.Errorf("Expected the resumed run to append, got %d lines", len(records))
+	}
+}
@@ -0,0 +1,167 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// redirectTraceKey is the context key for the redirect trace of a request.
+type redirectTraceKey struct{}
+
+// redirectTrace collects the redirect hops of a single request.
+// The HTTP client follows redirects sequentially, so no locking is needed.
+type redirectTrace struct {
+	last    time.Time
+	hops    []domain.RedirectHop
+	cookies []*http.Cookie // Cookies set by redirect responses
+	stopped string
+}
+
+// withRedirectTrace attaches a fresh redirect trace to ctx, timed from now.
+// Redirect requests inherit the context, so the trace sees every hop.
+func withRedirectTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, redirectTraceKey{}, &redirectTrace{last: time.Now()})
+}
+
+// redirectTraceFrom returns the trace of the request that produced resp, if any.
+func redirectTraceFrom(resp *http.Response) *redirectTrace {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	trace, _ := resp.Request.Context().Value(redirectTraceKey{}).(*redirectTrace)
+	return trace
+}
+
+// newRedirectPolicy returns an http.Client CheckRedirect function that records each hop
+// and enforces the policy. When a redirect is not followed, the redirect response itself
+// is returned as the final response rather than an error.
+func newRedirectPolicy(policy domain.RedirectPolicy, baseHost string) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		trace, _ := req.Context().Value(redirectTraceKey{}).(*redirectTrace)
+		stop := func(reason string) error {
+			if trace != nil {
+				trace.stopped = reason
+			}
+			return http.ErrUseLastResponse
+		}
+
+		if trace != nil {
+			now := time.Now()
+			hop := domain.RedirectHop{
+				Duration: now.Sub(trace.last),
+				URL:      via[len(via)-1].URL.String(),
+				Location: req.URL.String(),
+			}
+			if req.Response != nil {
+				hop.StatusCode = req.Response.StatusCode
+				trace.cookies = append(trace.cookies, req.Response.Cookies()...)
+			}
+			trace.hops = append(trace.hops, hop)
+			trace.last = now
+		}
+
+		if policy.SeparateResults {
+			return stop(domain.RedirectStoppedSeparate)
+		}
+
+		target := req.URL.String()
+		for _, previous := range via {
+			if previous.URL.String() == target {
+				return stop(domain.RedirectStoppedLoop)
+			}
+		}
+
+		if len(via) > policy.MaxHops {
+			return stop(domain.RedirectStoppedMaxHops)
+		}
+
+		if !policy.FollowCrossScope && !strings.EqualFold(req.URL.Host, baseHost) {
+			return stop(domain.RedirectStoppedScope)
+		}
+
+		return nil
+	}
+}
+
+// hostOf returns the host of rawURL, or "" if it cannot be parsed.
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
+
+// recordRedirects copies the redirect chain of resp into validation.
+// With separate redirect results, the redirect target is queued as a new URL.
+func (t *Tester) recordRedirects(validation *domain.URLValidation, resp *http.Response, task domain.URLTask) {
+	trace := redirectTraceFrom(resp)
+	if trace == nil || len(trace.hops) == 0 {
+		return
+	}
+
+	validation.RedirectChain = trace.hops
+	validation.RedirectStopped = trace.stopped
+	validation.FinalURL = resp.Request.URL.String()
+
+	if trace.stopped == domain.RedirectStoppedSeparate {
+		// A redirect target is the same resource, so it keeps the source's depth
+		target := trace.hops[len(trace.hops)-1].Location
+		result := t.crawler.AddLink(domain.Link{URL: target, SourceURL: task.URL}, task.Depth, t.urlQueue)
+		t.recordLink(task, result, task.Depth)
+	}
+}
+
+// redirectIssues flags a redirect loop, an overly long chain or an HTTPS-to-HTTP
+// downgrade in the redirect chain of validation.
+func redirectIssues(validation domain.URLValidation, longChainHops int) []domain.RedirectIssue {
+	hops := len(validation.RedirectChain)
+	if hops == 0 {
+		return nil
+	}
+
+	var issues []domain.RedirectIssue
+	switch {
+	case validation.RedirectStopped == domain.RedirectStoppedLoop:
+		issues = append(issues, domain.RedirectIssue{
+			URL:    validation.URL,
+			Kind:   domain.RedirectIssueLoop,
+			Detail: fmt.Sprintf("redirects back to %s", validation.RedirectChain[hops-1].Location),
+			Hops:   hops,
+		})
+	case validation.RedirectStopped == domain.RedirectStoppedMaxHops:
+		issues = append(issues, domain.RedirectIssue{
+			URL:    validation.URL,
+			Kind:   domain.RedirectIssueTooMany,
+			Detail: fmt.Sprintf("stopped after %d hops at %s", hops, validation.FinalURL),
+			Hops:   hops,
+		})
+	case longChainHops > 0 && hops >= longChainHops:
+		issues = append(issues, domain.RedirectIssue{
+			URL:    validation.URL,
+			Kind:   domain.RedirectIssueLongChain,
+			Detail: fmt.Sprintf("%d hops before reaching %s", hops, validation.FinalURL),
+			Hops:   hops,
+		})
+	}
+
+	for i, hop := range validation.RedirectChain {
+		if strings.HasPrefix(hop.URL, "https://") && strings.HasPrefix(hop.Location, "http://") {
+			issues = append(issues, domain.RedirectIssue{
+				URL:    validation.URL,
+				Kind:   domain.RedirectIssueDowngrade,
+				Detail: fmt.Sprintf("hop %d: %s -> %s", i+1, hop.URL, hop.Location),
+				Hops:   hops,
+			})
+			break
+		}
+	}
+
+	return issues
+}
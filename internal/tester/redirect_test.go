@@ -0,0 +1,267 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// redirectServer serves /a -> /b -> /c (200), /loop1 <-> /loop2, and /away to other.
+func redirectServer(t *testing.T, other string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case "/loop1":
+			http.Redirect(w, r, "/loop2", http.StatusFound)
+		case "/loop2":
+			http.Redirect(w, r, "/loop1", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, other+"/landing", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProcessURL_RecordsRedirectChain(t *testing.T) {
+	server := redirectServer(t, "")
+	tester, err := New(testConfig(server.URL), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	validations, errs := processTask(t, tester, server.URL+"/a")
+	if len(validations) != 1 || len(errs) != 0 {
+		t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+	}
+	validation := validations[0]
+
+	if validation.StatusCode != http.StatusOK {
+		t.Errorf("Expected final status 200, got %d", validation.StatusCode)
+	}
+	if len(validation.RedirectChain) != 2 {
+		t.Fatalf("Expected 2 redirect hops, got %+v", validation.RedirectChain)
+	}
+	first, second := validation.RedirectChain[0], validation.RedirectChain[1]
+	if first.StatusCode != http.StatusMovedPermanently || first.Location != server.URL+"/b" {
+		t.Errorf("Expected first hop 301 -> /b, got %+v", first)
+	}
+	if second.StatusCode != http.StatusFound || second.URL != server.URL+"/b" || second.Location != server.URL+"/c" {
+		t.Errorf("Expected second hop /b 302 -> /c, got %+v", second)
+	}
+	if validation.FinalURL != server.URL+"/c" {
+		t.Errorf("Expected final URL /c, got %s", validation.FinalURL)
+	}
+	if validation.RedirectStopped != "" {
+		t.Errorf("Expected chain to end normally, got stop reason %q", validation.RedirectStopped)
+	}
+}
+
+func TestProcessURL_RedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("elsewhere"))
+	}))
+	defer other.Close()
+	server := redirectServer(t, other.URL)
+
+	tests := []struct {
+		name        string
+		path        string
+		modify      func(p *domain.RedirectPolicy)
+		wantStopped string
+		wantStatus  int
+		wantHops    int
+	}{
+		{
+			name:        "loop is detected",
+			path:        "/loop1",
+			wantStopped: domain.RedirectStoppedLoop,
+			wantStatus:  http.StatusFound,
+			wantHops:    2,
+		},
+		{
+			name:        "max hops stops the chain",
+			path:        "/a",
+			modify:      func(p *domain.RedirectPolicy) { p.MaxHops = 1 },
+			wantStopped: domain.RedirectStoppedMaxHops,
+			wantStatus:  http.StatusFound,
+			wantHops:    2,
+		},
+		{
+			name:        "zero max hops follows none",
+			path:        "/a",
+			modify:      func(p *domain.RedirectPolicy) { p.MaxHops = 0 },
+			wantStopped: domain.RedirectStoppedMaxHops,
+			wantStatus:  http.StatusMovedPermanently,
+			wantHops:    1,
+		},
+		{
+			name:        "cross-scope redirect not followed",
+			path:        "/away",
+			modify:      func(p *domain.RedirectPolicy) { p.FollowCrossScope = false },
+			wantStopped: domain.RedirectStoppedScope,
+			wantStatus:  http.StatusFound,
+			wantHops:    1,
+		},
+		{
+			name:       "cross-scope redirect followed by default",
+			path:       "/away",
+			wantStatus: http.StatusOK,
+			wantHops:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(server.URL)
+			config.Redirects = domain.DefaultRedirectPolicy()
+			if tt.modify != nil {
+				tt.modify(&config.Redirects)
+			}
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			validations, errs := processTask(t, tester, server.URL+tt.path)
+			if len(validations) != 1 || len(errs) != 0 {
+				t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+			}
+			validation := validations[0]
+
+			if validation.RedirectStopped != tt.wantStopped {
+				t.Errorf("Expected stop reason %q, got %q", tt.wantStopped, validation.RedirectStopped)
+			}
+			if validation.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, validation.StatusCode)
+			}
+			if len(validation.RedirectChain) != tt.wantHops {
+				t.Errorf("Expected %d hops, got %+v", tt.wantHops, validation.RedirectChain)
+			}
+		})
+	}
+}
+
+func TestProcessURL_SeparateRedirectResults(t *testing.T) {
+	server := redirectServer(t, "")
+	config := testConfig(server.URL)
+	config.Redirects = domain.DefaultRedirectPolicy()
+	config.Redirects.SeparateResults = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	validations, errs := processTask(t, tester, server.URL+"/a")
+	if len(validations) != 1 || len(errs) != 0 {
+		t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+	}
+	validation := validations[0]
+
+	if validation.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("Expected the redirect itself as the result, got status %d", validation.StatusCode)
+	}
+	if validation.RedirectStopped != domain.RedirectStoppedSeparate {
+		t.Errorf("Expected stop reason %q, got %q", domain.RedirectStoppedSeparate, validation.RedirectStopped)
+	}
+
+	select {
+	case task := <-tester.urlQueue:
+		if task.URL != server.URL+"/b" || task.Depth != 0 {
+			t.Errorf("Expected redirect target /b queued at depth 0, got %+v", task)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected redirect target to be queued")
+	}
+}
+
+func TestRedirectIssues(t *testing.T) {
+	hop := func(from, to string) domain.RedirectHop {
+		return domain.RedirectHop{URL: from, Location: to, StatusCode: http.StatusFound}
+	}
+
+	validations := []domain.URLValidation{
+		{URL: "http://example.com/ok", RedirectChain: []domain.RedirectHop{hop("http://example.com/ok", "https://example.com/ok")}},
+		{URL: "http://example.com/loop", RedirectStopped: domain.RedirectStoppedLoop, RedirectChain: []domain.RedirectHop{
+			hop("http://example.com/loop", "http://example.com/x"), hop("http://example.com/x", "http://example.com/loop"),
+		}},
+		{URL: "http://example.com/long", FinalURL: "http://example.com/d", RedirectChain: []domain.RedirectHop{
+			hop("http://example.com/long", "http://example.com/b"), hop("http://example.com/b", "http://example.com/c"), hop("http://example.com/c", "http://example.com/d"),
+		}},
+		{URL: "https://example.com/down", RedirectChain: []domain.RedirectHop{hop("https://example.com/down", "http://example.com/down")}},
+		{URL: "http://example.com/many", RedirectStopped: domain.RedirectStoppedMaxHops, RedirectChain: []domain.RedirectHop{
+			hop("http://example.com/many", "http://example.com/m2"),
+		}},
+	}
+
+	var issues []domain.RedirectIssue
+	for _, validation := range validations {
+		issues = append(issues, redirectIssues(validation, 3)...)
+	}
+
+	got := make(map[string]string)
+	for _, issue := range issues {
+		got[issue.URL] = issue.Kind
+	}
+	expected := map[string]string{
+		"http://example.com/loop":  domain.RedirectIssueLoop,
+		"http://example.com/long":  domain.RedirectIssueLongChain,
+		"https://example.com/down": domain.RedirectIssueDowngrade,
+		"http://example.com/many":  domain.RedirectIssueTooMany,
+	}
+	if len(issues) != len(expected) {
+		t.Errorf("Expected %d issues, got %+v", len(expected), issues)
+	}
+	for url, kind := range expected {
+		if got[url] != kind {
+			t.Errorf("Expected %s to be flagged %q, got %q", url, kind, got[url])
+		}
+	}
+
+	// An HTTP->HTTPS upgrade is not a downgrade
+	for _, issue := range issues {
+		if issue.URL == "http://example.com/ok" {
+			t.Errorf("Expected upgrade not to be flagged, got %+v", issue)
+		}
+	}
+
+	if issues := redirectIssues(validations[2], 0); len(issues) != 0 {
+		t.Errorf("Expected long-chain flagging disabled at threshold 0, got %+v", issues)
+	}
+}
+
+func TestRun_ReportsRedirectIssues(t *testing.T) {
+	server := redirectServer(t, "")
+	config := testConfig(server.URL + "/loop1")
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	if len(results.RedirectIssues) != 1 || results.RedirectIssues[0].Kind != domain.RedirectIssueLoop {
+		t.Fatalf("Expected one loop issue, got %+v", results.RedirectIssues)
+	}
+	if !strings.HasSuffix(results.RedirectIssues[0].URL, "/loop1") {
+		t.Errorf("Expected issue for /loop1, got %s", results.RedirectIssues[0].URL)
+	}
+}
@@ -0,0 +1,185 @@
+package tester
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/robots"
+	"github.com/1mb-dev/lobster/v2/internal/util"
+)
+
+// maxRobotsBlockedSample caps the blocked URLs listed in the robots report.
+const maxRobotsBlockedSample = 20
+
+// crawlDelayGate spaces requests at least interval apart, as robots.txt
+// Crawl-delay asks. Each caller reserves the next free slot, so concurrent
+// workers queue up behind each other instead of all firing when the delay ends.
+type crawlDelayGate struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// newCrawlDelayGate returns a gate for interval, or nil if there is no delay.
+func newCrawlDelayGate(interval time.Duration) *crawlDelayGate {
+	if interval <= 0 {
+		return nil
+	}
+	return &crawlDelayGate{interval: interval}
+}
+
+// wait blocks until the caller's slot comes up or ctx is done.
+func (g *crawlDelayGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	now := time.Now()
+	slot := g.next
+	if slot.Before(now) {
+		slot = now
+	}
+	g.next = slot.Add(g.interval)
+	g.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// robotsFor returns the robots.txt rules of rawURL's host, fetching them on first use.
+func (t *Tester) robotsFor(ctx context.Context, rawURL string) *robots.Parser {
+	parser, err := t.robotsCache.Get(ctx, rawURL)
+	if err != nil && ctx.Err() == nil {
+		t.logger.Warn("Failed to fetch robots.txt, crawling the host is blocked",
+			"url", util.SanitizeURLDefault(rawURL),
+			"error", err,
+			"hint", "Unreachable robots.txt disallows every path (RFC 9309); use -ignore-robots only if you own the site")
+	}
+	return parser
+}
+
+// robotsAllowed reports whether robots.txt lets the crawl request task, recording
+// the URL in the robots report when it does not. Off-site links are outside its scope.
+// It returns false without recording anything once ctx is done, as the rules of a
+// fetch cut off by shutdown are unknown.
+func (t *Tester) robotsAllowed(ctx context.Context, task domain.URLTask) bool {
+	if t.config.IgnoreRobots || task.External {
+		return true
+	}
+	parser := t.robotsFor(ctx, task.URL)
+	if ctx.Err() != nil {
+		return false
+	}
+	if parser.IsAllowed(task.URL) {
+		return true
+	}
+
+	t.logger.Debug("URL blocked by robots.txt", "url", util.SanitizeURLDefault(task.URL))
+	atomic.AddInt64(&t.results.Robots.BlockedURLs, 1)
+
+	t.robotsMu.Lock()
+	if len(t.results.Robots.BlockedSample) < maxRobotsBlockedSample {
+		t.results.Robots.BlockedSample = append(t.results.Robots.BlockedSample, task.URL)
+	}
+	t.robotsMu.Unlock()
+	return false
+}
+
+// waitCrawlDelay waits out the Crawl-delay of the task host's robots.txt before
+// requesting it. It returns false if ctx ended first.
+func (t *Tester) waitCrawlDelay(ctx context.Context, task domain.URLTask) bool {
+	if t.config.IgnoreRobots || task.External {
+		return true
+	}
+	gate := t.crawlDelayGate(hostOf(task.URL), t.robotsFor(ctx, task.URL).GetCrawlDelay())
+	if gate == nil {
+		return true
+	}
+	return gate.wait(ctx) == nil
+}
+
+// crawlDelayGate returns the gate spacing requests to host, replacing it if
+// the host's robots.txt has since been refetched with a different delay.
+func (t *Tester) crawlDelayGate(host string, delay time.Duration) *crawlDelayGate {
+	t.robotsMu.Lock()
+	defer t.robotsMu.Unlock()
+
+	gate := t.crawlDelays[host]
+	if gate == nil || gate.interval != delay {
+		gate = newCrawlDelayGate(delay)
+		t.crawlDelays[host] = gate
+	}
+	return gate
+}
+
+// robotsReport returns a copy of the robots report safe to serialize while
+// workers run, or nil when robots.txt is ignored.
+func (t *Tester) robotsReport() *domain.RobotsReport {
+	if t.results.Robots == nil {
+		return nil
+	}
+
+	t.robotsMu.Lock()
+	report := *t.results.Robots
+	report.BlockedSample = append([]string(nil), t.results.Robots.BlockedSample...)
+	t.robotsMu.Unlock()
+	report.BlockedURLs = atomic.LoadInt64(&t.results.Robots.BlockedURLs)
+	return &report
+}
+
+// fillRobotsReport records the robots.txt rules in effect in the robots report:
+// the base URL's, and those of every other origin the crawl reached.
+func (t *Tester) fillRobotsReport() {
+	if t.results.Robots == nil {
+		return
+	}
+
+	base := robotsOrigin("", t.robotsParser.Snapshot())
+	t.results.Robots.Found = base.Found
+	t.results.Robots.Unreachable = base.Unreachable
+	t.results.Robots.Allow = base.Allow
+	t.results.Robots.Disallow = base.Disallow
+	t.results.Robots.CrawlDelay = base.CrawlDelay
+
+	others := t.otherRobotsOrigins()
+	t.results.Robots.Origins = nil
+	for _, origin := range slices.Sorted(maps.Keys(others)) {
+		t.results.Robots.Origins = append(t.results.Robots.Origins, robotsOrigin(origin, others[origin]))
+	}
+}
+
+// otherRobotsOrigins returns the cached robots.txt rules of origins other than the base URL's.
+func (t *Tester) otherRobotsOrigins() map[string]domain.RobotsState {
+	states := t.robotsCache.Snapshot()
+	if base, err := robots.Origin(t.config.BaseURL); err == nil {
+		delete(states, base)
+	}
+	return states
+}
+
+// robotsOrigin converts the rules of origin for the robots report.
+func robotsOrigin(origin string, state domain.RobotsState) domain.RobotsOrigin {
+	report := domain.RobotsOrigin{
+		Origin:      origin,
+		Disallow:    state.Disallow,
+		Allow:       state.Allow,
+		Found:       state.Found,
+		Unreachable: state.Unreachable,
+	}
+	if state.CrawlDelay > 0 {
+		report.CrawlDelay = state.CrawlDelay.String()
+	}
+	return report
+}
@@ -0,0 +1,249 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestCrawlDelayGate_SpacesCallers(t *testing.T) {
+	if gate := newCrawlDelayGate(0); gate != nil {
+		t.Errorf("Expected no gate without a delay, got %+v", gate)
+	}
+
+	const interval = 30 * time.Millisecond
+	gate := newCrawlDelayGate(interval)
+
+	var mu sync.Mutex
+	var times []time.Time
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := gate.wait(context.Background()); err != nil {
+				t.Errorf("Expected no error from wait, got: %v", err)
+			}
+			mu.Lock()
+			times = append(times, time.Now())
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+	if total := times[len(times)-1].Sub(times[0]); total < 3*interval-5*time.Millisecond {
+		t.Errorf("Expected 4 callers spread over at least %v, got %v", 3*interval, total)
+	}
+}
+
+func TestCrawlDelayGate_Canceled(t *testing.T) {
+	gate := newCrawlDelayGate(time.Hour)
+	if err := gate.wait(context.Background()); err != nil {
+		t.Fatalf("Expected the first slot immediately, got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := gate.wait(ctx); err == nil {
+		t.Error("Expected an error when the context ends before the slot")
+	}
+}
+
+func TestRun_HonorsRobotsTxt(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 0.1\nDisallow: /private\n"))
+			return
+		}
+		mu.Lock()
+		times = append(times, time.Now())
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body>
+			<a href="/a">A</a><a href="/b">B</a><a href="/private/x">X</a>
+		</body></html>`))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL + "/")
+	config.IgnoreRobots = false
+	config.FollowLinks = true
+	config.Concurrency = 3
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if slices.Contains(paths, "/private/x") {
+		t.Error("Expected /private/x not to be requested")
+	}
+	if len(times) != 3 {
+		t.Fatalf("Expected 3 requests, got %d: %v", len(times), paths)
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 90*time.Millisecond {
+			t.Errorf("Expected requests at least 100ms apart, got %v between %s and %s", gap, paths[i-1], paths[i])
+		}
+	}
+
+	report := results.Robots
+	if report == nil {
+		t.Fatal("Expected a robots report")
+	}
+	if !report.Found {
+		t.Error("Expected robots.txt to be reported as found")
+	}
+	if report.CrawlDelay != "100ms" {
+		t.Errorf("Expected crawl delay 100ms, got %q", report.CrawlDelay)
+	}
+	if !slices.Equal(report.Disallow, []string{"/private"}) {
+		t.Errorf("Expected disallow rules [/private], got %v", report.Disallow)
+	}
+	if report.BlockedURLs != 1 {
+		t.Errorf("Expected 1 blocked URL, got %d", report.BlockedURLs)
+	}
+	if !slices.Equal(report.BlockedSample, []string{server.URL + "/private/x"}) {
+		t.Errorf("Expected blocked sample [%s/private/x], got %v", server.URL, report.BlockedSample)
+	}
+	if results.TotalRequests != 3 {
+		t.Errorf("Expected blocked URLs not to count as requests, got %d requests", results.TotalRequests)
+	}
+}
+
+func TestRun_NoRobotsReportWhenIgnored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tester, err := New(testConfig(server.URL), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+	if results.Robots != nil {
+		t.Errorf("Expected no robots report with robots.txt ignored, got %+v", results.Robots)
+	}
+}
+
+func TestRobotsOrigins_ReportedAndCheckpointed(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), "crawl.ckpt")
+	other := domain.RobotsState{Disallow: []string{"/secure"}, CrawlDelay: 2 * time.Second, Found: true}
+	if err := writeCheckpoint(checkpointPath, &domain.CrawlCheckpoint{
+		Results:       &domain.TestResults{},
+		BaseURL:       "http://example.com",
+		Robots:        &domain.RobotsState{Disallow: []string{"/admin"}, Found: true},
+		RobotsOrigins: map[string]domain.RobotsState{"https://example.com": other},
+		Version:       checkpointVersion,
+	}); err != nil {
+		t.Fatalf("writeCheckpoint() error = %v", err)
+	}
+
+	config := testConfig("http://example.com")
+	config.IgnoreRobots = false
+	config.CheckpointPath = checkpointPath
+	config.Resume = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	// The restored rules are used without fetching robots.txt again
+	if tester.robotsFor(context.Background(), "https://example.com/secure/x").IsAllowed("https://example.com/secure/x") {
+		t.Error("Expected the restored rules of https://example.com to disallow /secure/x")
+	}
+
+	tester.results = &domain.TestResults{Robots: &domain.RobotsReport{}}
+	tester.fillRobotsReport()
+	want := []domain.RobotsOrigin{{Origin: "https://example.com", Disallow: []string{"/secure"}, CrawlDelay: "2s", Found: true}}
+	if got := tester.results.Robots.Origins; len(got) != 1 || got[0].Origin != want[0].Origin ||
+		!slices.Equal(got[0].Disallow, want[0].Disallow) || got[0].CrawlDelay != want[0].CrawlDelay || !got[0].Found {
+		t.Errorf("Expected other origins %+v, got %+v", want, got)
+	}
+	if !slices.Equal(tester.results.Robots.Disallow, []string{"/admin"}) {
+		t.Errorf("Expected the base URL's rules at the top level, got %v", tester.results.Robots.Disallow)
+	}
+
+	tester.saveCheckpoint()
+	cp, err := loadCheckpoint(checkpointPath, config.BaseURL)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() error = %v", err)
+	}
+	if _, ok := cp.RobotsOrigins["http://example.com"]; ok || len(cp.RobotsOrigins) != 1 {
+		t.Errorf("Expected only the other origin in robots_origins, got %+v", cp.RobotsOrigins)
+	}
+	if got := cp.RobotsOrigins["https://example.com"]; !slices.Equal(got.Disallow, other.Disallow) || got.CrawlDelay != other.CrawlDelay {
+		t.Errorf("Expected the other origin's rules to be checkpointed, got %+v", got)
+	}
+}
+
+func TestRobotsOrigins_CancelledFetchNotCheckpointed(t *testing.T) {
+	base := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /admin\n"))
+	}))
+	defer base.Close()
+	fetching := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fetching)
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	config := testConfig(base.URL)
+	config.IgnoreRobots = false
+	config.CheckpointPath = filepath.Join(t.TempDir(), "crawl.ckpt")
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+	tester.results.Robots = &domain.RobotsReport{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-fetching
+		cancel()
+	}()
+	if tester.robotsAllowed(ctx, domain.URLTask{URL: slow.URL + "/page"}) {
+		t.Error("Expected a URL not to be requested once the crawl is cancelled")
+	}
+	if blocked := tester.robotsReport().BlockedURLs; blocked != 0 {
+		t.Errorf("Expected no robots blocks from a cancelled fetch, got %d", blocked)
+	}
+
+	tester.saveCheckpoint()
+	cp, err := loadCheckpoint(config.CheckpointPath, config.BaseURL)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() error = %v", err)
+	}
+	if state, ok := cp.RobotsOrigins[slow.URL]; ok {
+		t.Errorf("Expected no rules for the cancelled origin, got %+v", state)
+	}
+}
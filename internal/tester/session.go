@@ -0,0 +1,48 @@
+package tester
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/util"
+)
+
+// checkSession denylists a URL whose response cleared an authentication cookie,
+// so links to it are not followed again while the crawl continues.
+func (t *Tester) checkSession(resp *http.Response, task domain.URLTask) {
+	if t.config.Denylist.Disabled || t.config.Auth == nil || len(t.config.Auth.Cookies) == 0 {
+		return
+	}
+
+	cookie := clearedCookie(resp, t.config.Auth.Cookies)
+	if cookie == "" {
+		return
+	}
+
+	t.crawler.DenyURL(task.URL)
+	t.logger.Warn("Link invalidated the session and will not be followed again",
+		"url", util.SanitizeURLDefault(task.URL),
+		"cookie", cookie,
+		"hint", "Refresh the auth cookie if later requests fail; add the path to denylist.patterns to skip it from the start")
+}
+
+// clearedCookie returns the name of a session cookie that resp, or a redirect
+// response before it, deleted or emptied, or "" if none was.
+func clearedCookie(resp *http.Response, session map[string]string) string {
+	cookies := resp.Cookies()
+	if trace := redirectTraceFrom(resp); trace != nil {
+		cookies = append(cookies, trace.cookies...)
+	}
+
+	now := time.Now()
+	for _, cookie := range cookies {
+		if _, ok := session[cookie.Name]; !ok {
+			continue
+		}
+		if cookie.Value == "" || cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(now)) {
+			return cookie.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package tester
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestProcessURL_DenylistsSessionInvalidatingLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bye":
+			// Clear the session on the redirect, as most logout handlers do
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "", MaxAge: -1})
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case "/expire":
+			http.SetCookie(w, &http.Cookie{Name: "theme", Value: "", MaxAge: -1})
+			_, _ = w.Write([]byte("ok"))
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		disabled   bool
+		wantDenied bool
+	}{
+		{name: "auth cookie cleared", path: "/bye", wantDenied: true},
+		{name: "other cookie cleared", path: "/expire"},
+		{name: "denylist disabled", path: "/bye", disabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(server.URL)
+			config.Auth = &domain.AuthConfig{Type: "cookie", Cookies: map[string]string{"session": "abc"}}
+			config.Denylist.Disabled = tt.disabled
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			processTask(t, tester, server.URL+tt.path)
+
+			result := tester.crawler.AddLink(domain.Link{URL: tt.path + "?again=1"}, 1, tester.urlQueue)
+			if denied := result.Reason == domain.AddURLDenied; denied != tt.wantDenied {
+				t.Errorf("Expected later links to %s denied=%v, got reason %q", tt.path, tt.wantDenied, result.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package tester
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/routes"
+)
+
+// successCriteria is a domain.SuccessCriteria with its status codes parsed.
+type successCriteria struct {
+	status          []domain.StatusRange
+	bodyContains    []string
+	bodyNotContains []string
+	headers         []headerAssertion // Sorted by name, so failures are reported consistently
+}
+
+// headerAssertion requires a response header containing value ("" = any value).
+type headerAssertion struct {
+	name  string
+	value string
+}
+
+// successChecker picks the success criteria of a request by its route.
+type successChecker struct {
+	templater *routes.Templater
+	defaults  *successCriteria
+	routes    map[string]*successCriteria
+}
+
+// newSuccessChecker parses policy; routes are named by templater as in the route tables.
+func newSuccessChecker(policy domain.SuccessPolicy, templater *routes.Templater) (*successChecker, error) {
+	defaults, err := parseSuccessCriteria(policy.SuccessCriteria)
+	if err != nil {
+		return nil, err
+	}
+	c := &successChecker{templater: templater, defaults: defaults, routes: make(map[string]*successCriteria)}
+	for route := range policy.Routes {
+		criteria, err := parseSuccessCriteria(policy.ForRoute(route))
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route, err)
+		}
+		c.routes[route] = criteria
+	}
+	return c, nil
+}
+
+// parseSuccessCriteria parses the status codes of criteria.
+func parseSuccessCriteria(criteria domain.SuccessCriteria) (*successCriteria, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+	status, err := domain.ParseStatusRanges(criteria.Status)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &successCriteria{
+		status:          status,
+		bodyContains:    criteria.BodyContains,
+		bodyNotContains: criteria.BodyNotContains,
+	}
+	for name, value := range criteria.Headers {
+		parsed.headers = append(parsed.headers, headerAssertion{name: name, value: value})
+	}
+	slices.SortFunc(parsed.headers, func(a, b headerAssertion) int { return strings.Compare(a.name, b.name) })
+	return parsed, nil
+}
+
+// criteria returns the success criteria of a request.
+func (c *successChecker) criteria(method, rawURL string) *successCriteria {
+	if len(c.routes) > 0 {
+		if criteria, ok := c.routes[c.templater.Route(method, rawURL)]; ok {
+			return criteria
+		}
+	}
+	return c.defaults
+}
+
+// needsBody reports whether the criteria assert on the response body.
+func (c *successCriteria) needsBody() bool {
+	return len(c.bodyContains) > 0 || len(c.bodyNotContains) > 0
+}
+
+// check returns "" if resp, whose body starts with body, meets the criteria,
+// or else the class of the failure and, for failed assertions, why. The body
+// of a HEAD response is not checked.
+func (c *successCriteria) check(resp *http.Response, body []byte) (domain.ErrorClass, string) {
+	if !slices.ContainsFunc(c.status, func(r domain.StatusRange) bool { return r.Contains(resp.StatusCode) }) {
+		if class := domain.StatusErrorClass(resp.StatusCode); class != "" {
+			return class, ""
+		}
+		return domain.ErrorClassHTTPOther, ""
+	}
+
+	for _, header := range c.headers {
+		values := resp.Header.Values(header.name)
+		if len(values) == 0 {
+			return domain.ErrorClassAssertion, fmt.Sprintf("missing header %s", header.name)
+		}
+		if !slices.ContainsFunc(values, func(v string) bool { return strings.Contains(v, header.value) }) {
+			return domain.ErrorClassAssertion, fmt.Sprintf("header %s does not contain %q", header.name, header.value)
+		}
+	}
+
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return "", ""
+	}
+	for _, want := range c.bodyContains {
+		if !bytes.Contains(body, []byte(want)) {
+			return domain.ErrorClassAssertion, fmt.Sprintf("body does not contain %q", want)
+		}
+	}
+	for _, unwanted := range c.bodyNotContains {
+		if bytes.Contains(body, []byte(unwanted)) {
+			return domain.ErrorClassAssertion, fmt.Sprintf("body contains %q", unwanted)
+		}
+	}
+	return "", ""
+}
+
+// bodyCapture keeps the first limit bytes read from a response body, for body
+// assertions, while link extraction and draining read it as usual.
+type bodyCapture struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	limit int64
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := b.limit - int64(b.buf.Len()); room > 0 {
+		b.buf.Write(p[:min(int64(n), room)])
+	}
+	return n, err
+}
+
+// successCriteria returns the success criteria of task, capturing the body of
+// resp as it is read when they assert on it.
+func (t *Tester) successCriteria(task domain.URLTask, resp *http.Response) (*successCriteria, *bodyCapture) {
+	criteria := t.success.criteria(task.Method, task.URL)
+	if !criteria.needsBody() {
+		return criteria, nil
+	}
+	capture := &bodyCapture{ReadCloser: resp.Body, limit: t.maxResponseSize()}
+	resp.Body = capture
+	return criteria, capture
+}
+
+// checkSuccess decides whether a response, whose body has been read, is a
+// successful request and counts it. A body that could not be read fails it.
+func (t *Tester) checkSuccess(ctx context.Context, validation *domain.URLValidation, resp *http.Response,
+	criteria *successCriteria, capture *bodyCapture, bodyErr error) {
+	if bodyErr != nil {
+		t.failBodyRead(ctx, validation, bodyErr)
+	} else {
+		var body []byte
+		if capture != nil {
+			body = capture.buf.Bytes()
+		}
+		class, reason := criteria.check(resp, body)
+		validation.IsValid = class == ""
+		validation.ErrorClass = class
+		validation.Error = reason
+	}
+
+	if validation.IsValid {
+		atomic.AddInt64(&t.results.SuccessfulRequests, 1)
+	} else {
+		atomic.AddInt64(&t.results.FailedRequests, 1)
+	}
+}
@@ -0,0 +1,139 @@
+package tester
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestProcessURL_SuccessCriteria(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/missing/42":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<html>Gone</html>"))
+		case "/maintenance":
+			_, _ = w.Write([]byte("<html>Down for maintenance</html>"))
+		default:
+			_, _ = w.Write([]byte("<html>Welcome</html>"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	success := domain.SuccessPolicy{
+		SuccessCriteria: domain.SuccessCriteria{
+			BodyContains:    []string{"<html"},
+			BodyNotContains: []string{"maintenance"},
+			Headers:         map[string]string{"Cache-Control": "public"},
+		},
+		Routes: map[string]domain.SuccessCriteria{
+			"/missing/:id": {Status: []string{"404"}, BodyContains: []string{"not found"}, Headers: map[string]string{"Content-Type": ""}},
+			"/error":       {Headers: map[string]string{"Retry-After": ""}},
+		},
+	}
+	tests := []struct {
+		name      string
+		path      string
+		wantClass domain.ErrorClass
+		wantError string
+	}{
+		{"meets criteria", "/", "", ""},
+		{"error status", "/error", domain.ErrorClassHTTP5xx, ""},
+		{"unwanted body", "/maintenance", domain.ErrorClassAssertion, `body contains "maintenance"`},
+		{"route override", "/missing/42", domain.ErrorClassAssertion, `body does not contain "not found"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(server.URL)
+			config.Success = success
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			validations, errs := processTask(t, tester, server.URL+tt.path)
+			if len(validations) != 1 || len(errs) != 0 {
+				t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+			}
+			validation := validations[0]
+			if validation.IsValid != (tt.wantClass == "") || validation.ErrorClass != tt.wantClass || validation.Error != tt.wantError {
+				t.Errorf("Expected class %q and error %q, got valid=%v class=%q error=%q",
+					tt.wantClass, tt.wantError, validation.IsValid, validation.ErrorClass, validation.Error)
+			}
+
+			wantSuccessful, wantFailed := int64(1), int64(0)
+			if tt.wantClass != "" {
+				wantSuccessful, wantFailed = 0, 1
+			}
+			if tester.results.SuccessfulRequests != wantSuccessful || tester.results.FailedRequests != wantFailed {
+				t.Errorf("Expected %d successful and %d failed, got %d and %d", wantSuccessful, wantFailed,
+					tester.results.SuccessfulRequests, tester.results.FailedRequests)
+			}
+		})
+	}
+}
+
+func TestProcessURL_AcceptedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	config := testConfig(server.URL)
+	config.Success.Status = []string{"200-299", "404"}
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	validations, errs := processTask(t, tester, server.URL)
+	if len(validations) != 1 || len(errs) != 0 {
+		t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+	}
+	validation := validations[0]
+	if !validation.IsValid || validation.ErrorClass != "" {
+		t.Errorf("Expected an accepted 404 to succeed, got class %q", validation.ErrorClass)
+	}
+	if tester.results.SuccessfulRequests != 1 {
+		t.Errorf("Expected 1 successful request, got %d", tester.results.SuccessfulRequests)
+	}
+}
+
+func TestSuccessCriteria_Check(t *testing.T) {
+	criteria, err := parseSuccessCriteria(domain.SuccessCriteria{
+		Status:       []string{"2xx"},
+		BodyContains: []string{"ok"},
+		Headers:      map[string]string{"X-Version": "2"},
+	})
+	if err != nil {
+		t.Fatalf("parseSuccessCriteria() error = %v", err)
+	}
+
+	response := func(method string, status int, header http.Header) *http.Response {
+		return &http.Response{StatusCode: status, Header: header, Request: &http.Request{Method: method}}
+	}
+	tests := []struct {
+		name string
+		resp *http.Response
+		body string
+		want domain.ErrorClass
+	}{
+		{"success", response("GET", 200, http.Header{"X-Version": {"1", "2"}}), "ok", ""},
+		{"redirect not accepted", response("GET", 301, http.Header{"X-Version": {"2"}}), "ok", domain.ErrorClassHTTPOther},
+		{"missing header", response("GET", 200, http.Header{}), "ok", domain.ErrorClassAssertion},
+		{"wrong header value", response("GET", 200, http.Header{"X-Version": {"1"}}), "ok", domain.ErrorClassAssertion},
+		{"missing body text", response("GET", 200, http.Header{"X-Version": {"2"}}), "", domain.ErrorClassAssertion},
+		{"HEAD has no body to check", response("HEAD", 200, http.Header{"X-Version": {"2"}}), "", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := criteria.check(tt.resp, []byte(tt.body)); got != tt.want {
+			t.Errorf("%s: check() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
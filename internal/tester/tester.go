@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -19,7 +20,9 @@ import (
 	"github.com/1mb-dev/goflow/pkg/ratelimit/bucket"
 	"github.com/1mb-dev/lobster/v2/internal/crawler"
 	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/linkgraph"
 	"github.com/1mb-dev/lobster/v2/internal/robots"
+	"github.com/1mb-dev/lobster/v2/internal/routes"
 	"github.com/1mb-dev/lobster/v2/internal/util"
 )
 
@@ -33,6 +36,9 @@ const (
 
 	// defaultQueueSize is the default URL queue capacity when not configured.
 	defaultQueueSize = 10000
+
+	// frontierPumpInterval is how often spilled URLs are moved back into the queue.
+	frontierPumpInterval = 20 * time.Millisecond
 )
 
 // Tester orchestrates the stress testing process
@@ -43,9 +49,26 @@ type Tester struct {
 	results      *domain.TestResults
 	rateLimiter  domain.RateLimiter
 	crawler      domain.URLCrawler
-	robotsParser domain.RobotsChecker
+	robotsParser domain.RobotsChecker       // robots.txt rules of the base URL's host
+	robotsCache  *robots.Cache              // robots.txt rules of every origin, fetched on first use
+	crawlDelays  map[string]*crawlDelayGate // Per-host gates spacing requests by robots.txt Crawl-delay
+	robotsMu     sync.Mutex                 // Guards crawlDelays and the blocked URL sample in the robots report
+	graph        *linkgraph.Graph           // Link graph for export (nil = not kept)
+	routeStats   *routes.Stats              // Per-route and per-URL statistics (aggregator only)
+	series       *timeSeries                // Requests per metrics interval
+	success      *successChecker            // Decides which responses are successful requests
+	inFlight     int64                      // Requests in progress (atomic)
+	raw          *rawWriter                 // Raw output stream (nil = not written)
 	logger       *slog.Logger
 
+	// Checkpoint state: resumeFrom is the loaded checkpoint (nil = fresh run),
+	// and pauseMu is held for reading by workers while they process a task
+	// so a checkpoint can wait for in-flight tasks to finish
+	resumeFrom   *domain.CrawlCheckpoint
+	pauseMu      sync.RWMutex
+	startTime    time.Time
+	priorElapsed time.Duration
+
 	// Result channels for lock-free aggregation
 	validationsCh   chan domain.URLValidation
 	errorsCh        chan domain.ErrorInfo
@@ -56,11 +79,55 @@ type Tester struct {
 // New creates a new stress tester
 func New(config domain.TesterConfig, logger *slog.Logger) (*Tester, error) {
 	// Create crawler
-	crawlerInstance, err := crawler.New(config.BaseURL, config.MaxDepth)
+	crawlerInstance, err := crawler.NewWithOptions(config.BaseURL, config.MaxDepth, crawler.Options{
+		Normalization:  config.Normalization,
+		Frontier:       config.Frontier,
+		Traps:          config.Traps,
+		Denylist:       config.Denylist,
+		Forms:          config.Forms,
+		TrackFrontier:  config.CheckpointPath != "",
+		TrackReferrers: config.LinkCheck,
+		CheckExternal:  config.LinkCheck && config.CheckExternal,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating crawler: %w", err)
 	}
 
+	// Load the checkpoint before fetching robots.txt, whose rules it may carry
+	var resumeFrom *domain.CrawlCheckpoint
+	if config.Resume {
+		if config.CheckpointPath == "" {
+			return nil, fmt.Errorf("resume requires a checkpoint file")
+		}
+		resumeFrom, err = loadCheckpoint(config.CheckpointPath, config.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		if resumeFrom.DryRun != config.DryRun {
+			logger.Warn("Resuming with a different dry-run setting than the checkpointed run",
+				"checkpoint_dry_run", resumeFrom.DryRun,
+				"dry_run", config.DryRun)
+		}
+	}
+
+	templater, err := routes.NewTemplater(config.Routes)
+	if err != nil {
+		return nil, fmt.Errorf("creating route templater: %w", err)
+	}
+	success, err := newSuccessChecker(config.Success, templater)
+	if err != nil {
+		return nil, fmt.Errorf("parsing success criteria: %w", err)
+	}
+
+	// Keep the link graph only when it will be exported, continuing the checkpointed one
+	var graph *linkgraph.Graph
+	if config.GraphPath != "" {
+		graph = linkgraph.New()
+		if resumeFrom != nil && resumeFrom.Graph != nil {
+			graph.Restore(*resumeFrom.Graph)
+		}
+	}
+
 	// Create token bucket rate limiter using goflow
 	var rateLimiter bucket.Limiter
 	if config.Rate > 0 {
@@ -103,20 +170,44 @@ func New(config domain.TesterConfig, logger *slog.Logger) (*Tester, error) {
 		}
 	}
 
+	// An unset redirect policy means net/http's default behavior
+	if config.Redirects == (domain.RedirectPolicy{}) {
+		config.Redirects = domain.DefaultRedirectPolicy()
+	}
+
 	// Create HTTP client with connection pooling
+	// Redirects are followed by the client, with every hop recorded by the policy
 	httpClient := &http.Client{
-		Timeout:   config.RequestTimeout,
-		Transport: transport,
+		Timeout:       config.RequestTimeout,
+		Transport:     transport,
+		CheckRedirect: newRedirectPolicy(config.Redirects, hostOf(config.BaseURL)),
 	}
 
-	// Create robots.txt parser and fetch robots.txt
+	// Fetch the base URL's robots.txt up front; other hosts' are fetched when first reached
+	robotsCache := robots.NewCache(config.UserAgent, robots.DefaultCacheTTL)
 	robotsParser := robots.New(config.UserAgent)
-	if !config.IgnoreRobots {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if err := robotsParser.FetchAndParse(ctx, config.BaseURL); err != nil {
-			logger.Warn("Failed to fetch robots.txt, proceeding with caution", "error", err)
+	if !config.IgnoreRobots && resumeFrom != nil && resumeFrom.Robots != nil {
+		// Keep the rules the crawl started with so resumed sessions stay consistent
+		robotsParser.Restore(*resumeFrom.Robots)
+		if err := robotsCache.Put(config.BaseURL, robotsParser); err != nil {
+			return nil, fmt.Errorf("restoring robots.txt rules: %w", err)
+		}
+		for origin, state := range resumeFrom.RobotsOrigins {
+			parser := robots.New(config.UserAgent)
+			parser.Restore(state)
+			if err := robotsCache.Put(origin, parser); err != nil {
+				return nil, fmt.Errorf("restoring robots.txt rules of %s: %w", origin, err)
+			}
+		}
+		logger.Info("robots.txt rules restored from checkpoint")
+	} else if !config.IgnoreRobots {
+		// The robots.txt client's own timeout bounds the fetch; a timeout there means
+		// robots.txt is unreachable, while a cancelled context would not be cached
+		robotsParser, err = robotsCache.Get(context.Background(), config.BaseURL)
+		if err != nil {
+			logger.Warn("Failed to fetch robots.txt, crawling is blocked",
+				"error", err,
+				"hint", "Unreachable robots.txt disallows every path (RFC 9309); use -ignore-robots only if you own the site")
 		} else if robotsParser.RobotsTxtFound() {
 			logger.Info("robots.txt found and parsed successfully")
 		} else {
@@ -126,12 +217,16 @@ func New(config domain.TesterConfig, logger *slog.Logger) (*Tester, error) {
 		logger.Warn("WARNING: Ignoring robots.txt directives. Please ensure you have permission to test this site!")
 	}
 
+	if delay := robotsParser.GetCrawlDelay(); !config.IgnoreRobots && delay > 0 {
+		logger.Info("Honoring robots.txt Crawl-delay", "delay", delay)
+	}
+
 	// Size result channels proportionally to avoid backpressure
 	// Use larger buffers when queue is large to handle burst processing
 	resultBufferSize := min(queueSize, 10000)
 	slowBufferSize := min(queueSize/10, 1000)
 
-	return &Tester{
+	t := &Tester{
 		config:          config,
 		client:          httpClient,
 		urlQueue:        make(chan domain.URLTask, queueSize),
@@ -139,22 +234,76 @@ func New(config domain.TesterConfig, logger *slog.Logger) (*Tester, error) {
 		rateLimiter:     rateLimiter,
 		crawler:         crawlerInstance,
 		robotsParser:    robotsParser,
+		robotsCache:     robotsCache,
+		crawlDelays:     make(map[string]*crawlDelayGate),
+		graph:           graph,
+		success:         success,
 		logger:          logger,
+		resumeFrom:      resumeFrom,
 		validationsCh:   make(chan domain.URLValidation, resultBufferSize),
 		errorsCh:        make(chan domain.ErrorInfo, resultBufferSize),
 		responseTimesCh: make(chan domain.ResponseTimeEntry, resultBufferSize),
 		slowRequestsCh:  make(chan domain.SlowRequest, slowBufferSize),
-	}, nil
+	}
+	t.routeStats = routes.NewStats(templater, t.latencyPrecision())
+	t.series = newTimeSeries(t.latencyPrecision())
+	return t, nil
 }
 
 // Run executes the stress test
 func (t *Tester) Run(ctx context.Context) (*domain.TestResults, error) {
 	startTime := time.Now()
+	t.startTime = startTime
 
-	// Initialize results
-	t.results.ResponseTimes = make([]domain.ResponseTimeEntry, 0)
-	t.results.Errors = make([]domain.ErrorInfo, 0)
-	t.results.SlowRequests = make([]domain.SlowRequest, 0)
+	// Initialize results, continuing from the checkpoint when resuming
+	if t.resumeFrom != nil {
+		t.results = t.resumeFrom.Results
+		t.priorElapsed = t.resumeFrom.Elapsed
+		t.routeStats.Restore(t.results.RouteStats, t.results.URLStats)
+	}
+	if t.results.URLValidations == nil {
+		t.results.URLValidations = make([]domain.URLValidation, 0)
+	}
+	if t.results.ResponseTimes == nil {
+		t.results.ResponseTimes = make([]domain.ResponseTimeEntry, 0)
+	}
+	if t.results.Errors == nil {
+		t.results.Errors = make([]domain.ErrorInfo, 0)
+	}
+	if t.results.SlowRequests == nil {
+		t.results.SlowRequests = make([]domain.SlowRequest, 0)
+	}
+	t.series.begin(startTime, t.priorElapsed, t.results.TimeSeries)
+	if t.config.IgnoreRobots {
+		t.results.Robots = nil
+	} else if t.results.Robots == nil {
+		t.results.Robots = &domain.RobotsReport{}
+	}
+
+	defer func() {
+		if err := t.crawler.Close(); err != nil {
+			t.logger.Warn("Failed to clean up crawl frontier", "error", err)
+		}
+	}()
+
+	// Queue the checkpointed frontier before any worker starts
+	resumed := t.resumeFrom != nil
+	if resumed {
+		if err := t.crawler.Restore(t.resumeFrom.Crawl, t.urlQueue); err != nil {
+			return nil, fmt.Errorf("restoring checkpoint: %w", err)
+		}
+		t.logger.Info("Resuming crawl from checkpoint",
+			"file", t.config.CheckpointPath,
+			"saved_at", t.resumeFrom.SavedAt,
+			"frontier", t.resumeFrom.Crawl.FrontierSize(),
+			"discovered", t.resumeFrom.Crawl.Discovered,
+			"elapsed", t.resumeFrom.Elapsed)
+		t.resumeFrom = nil
+	}
+
+	if err := t.openRawOutput(resumed); err != nil {
+		return nil, err
+	}
 
 	var wg sync.WaitGroup
 	var aggregatorWg sync.WaitGroup
@@ -166,22 +315,32 @@ func (t *Tester) Run(ctx context.Context) (*domain.TestResults, error) {
 	// Start workers
 	for i := 0; i < t.config.Concurrency; i++ {
 		wg.Add(1)
-		go t.worker(ctx, &wg)
+		go t.worker(ctx, i, &wg)
 	}
 
 	// Start URL discovery with the base URL
-	t.crawler.AddURL(t.config.BaseURL, 0, t.urlQueue)
+	if !resumed {
+		t.crawler.AddURL(t.config.BaseURL, 0, t.urlQueue)
+	}
 	t.results.URLsDiscovered = t.crawler.GetDiscoveredCount()
 
+	// Refill the queue from the disk overflow as workers drain it
+	var pumpWg sync.WaitGroup
+	pumpWg.Add(1)
+	go t.frontierPump(ctx, &pumpWg)
+
 	// Start monitoring
 	go t.monitor(ctx, startTime)
 
 	// Wait for context cancellation or completion
 	<-ctx.Done()
 
-	// Close URL queue and wait for workers to finish
-	close(t.urlQueue)
+	// Stop the pump and the workers before closing the queue they send on;
+	// a worker finishing its last response may still queue the links it found
+	pumpWg.Wait()
 	wg.Wait()
+	close(t.urlQueue)
+	t.closeRawOutput()
 
 	// Close result channels and wait for aggregator to finish
 	close(t.validationsCh)
@@ -189,22 +348,34 @@ func (t *Tester) Run(ctx context.Context) (*domain.TestResults, error) {
 	close(t.responseTimesCh)
 	close(t.slowRequestsCh)
 	aggregatorWg.Wait()
+	t.series.flush(time.Now(), 0)
+
+	// Save the final state so an interrupted crawl can be resumed
+	t.saveCheckpoint()
 
 	// Check for dropped URLs and warn user
 	if droppedCount := t.crawler.GetDroppedCount(); droppedCount > 0 {
 		t.logger.Warn("URLs dropped due to queue overflow",
 			"dropped_count", droppedCount,
-			"hint", "Consider increasing --queue-size")
+			"hint", "Consider increasing --queue-size or setting --spill-dir")
 	}
 
+	t.results.DuplicatesByNormalization = t.crawler.GetNormalizedDuplicateCount()
+	t.results.DuplicatesByCanonical = t.crawler.GetCanonicalDuplicateCount()
+	t.results.CrawlTraps = t.crawler.GetCrawlTraps()
+	t.results.DeniedLinks = t.crawler.GetDeniedLinks()
+	t.fillRobotsReport()
+
 	// Calculate final results
-	t.calculateResults(time.Since(startTime))
+	t.calculateResults(t.priorElapsed + time.Since(startTime))
+	t.exportLinkGraph()
 
 	return t.results, nil
 }
 
 // aggregator collects results from workers via channels (lock-free).
 // Uses nil channel pattern: closed channels are set to nil to disable their select cases.
+// When checkpointing is enabled it also saves crawl state periodically, since it owns the results.
 func (t *Tester) aggregator(wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -214,15 +385,49 @@ func (t *Tester) aggregator(wg *sync.WaitGroup) {
 	responseTimesCh := t.responseTimesCh
 	slowRequestsCh := t.slowRequestsCh
 
-	// Continue while any channel is still open
-	for validationsCh != nil || errorsCh != nil || responseTimesCh != nil || slowRequestsCh != nil {
+	// Checkpoint ticks pause workers; pausedCh fires once no task is in flight
+	var checkpointTick <-chan time.Time
+	if t.config.CheckpointPath != "" {
+		ticker := time.NewTicker(t.checkpointInterval())
+		defer ticker.Stop()
+		checkpointTick = ticker.C
+	}
+	pausedCh := make(chan struct{}, 1)
+	pausing := false
+
+	// Continue while any channel is still open or a pause is pending
+	for validationsCh != nil || errorsCh != nil || responseTimesCh != nil || slowRequestsCh != nil || pausing {
 		select {
+		case <-checkpointTick:
+			if !pausing {
+				pausing = true
+				go func() {
+					t.pauseMu.Lock()
+					pausedCh <- struct{}{}
+				}()
+			}
+
+		case <-pausedCh:
+			// Paused workers have finished sending; collect their buffered results first
+			if len(validationsCh)+len(errorsCh)+len(responseTimesCh)+len(slowRequestsCh) > 0 {
+				pausedCh <- struct{}{}
+				continue
+			}
+			t.saveCheckpoint()
+			t.pauseMu.Unlock()
+			pausing = false
+
 		case validation, ok := <-validationsCh:
 			if !ok {
 				validationsCh = nil
 				continue
 			}
-			t.results.URLValidations = append(t.results.URLValidations, validation)
+			t.recordValidation(validation)
+			// Requests without a response are counted from their error instead
+			if validation.StatusCode != 0 {
+				t.routeStats.Record(validation)
+				t.series.record(validation)
+			}
 
 		case errInfo, ok := <-errorsCh:
 			if !ok {
@@ -230,13 +435,15 @@ func (t *Tester) aggregator(wg *sync.WaitGroup) {
 				continue
 			}
 			t.results.Errors = append(t.results.Errors, errInfo)
+			t.routeStats.RecordError(errInfo.Method, errInfo.URL, errInfo.Class)
+			t.series.recordFailure(errInfo.Class)
 
 		case responseTime, ok := <-responseTimesCh:
 			if !ok {
 				responseTimesCh = nil
 				continue
 			}
-			t.results.ResponseTimes = append(t.results.ResponseTimes, responseTime)
+			t.recordLatency(responseTime)
 
 		case slowReq, ok := <-slowRequestsCh:
 			if !ok {
@@ -248,8 +455,8 @@ func (t *Tester) aggregator(wg *sync.WaitGroup) {
 	}
 }
 
-// worker processes URLs from the queue
-func (t *Tester) worker(ctx context.Context, wg *sync.WaitGroup) {
+// worker processes URLs from the queue; id identifies it in the raw output
+func (t *Tester) worker(ctx context.Context, id int, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for {
@@ -258,7 +465,31 @@ func (t *Tester) worker(ctx context.Context, wg *sync.WaitGroup) {
 			if !ok {
 				return
 			}
+			task.Worker = id
+			t.pauseMu.RLock()
 			t.processURL(ctx, task)
+			// Tasks cut short by shutdown stay in the frontier to be retried on resume
+			if ctx.Err() == nil {
+				t.crawler.Complete(task)
+			}
+			t.pauseMu.RUnlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// frontierPump moves spilled URLs from disk back into the queue as space frees up.
+func (t *Tester) frontierPump(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(frontierPumpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.crawler.DrainSpilled(ctx, t.urlQueue)
 		case <-ctx.Done():
 			return
 		}
@@ -270,13 +501,13 @@ func (t *Tester) processDryRun(ctx context.Context, task domain.URLTask) {
 	atomic.AddInt64(&t.results.TotalRequests, 1)
 
 	// Make HTTP request to discover links (but skip rate limiting)
-	req, err := http.NewRequestWithContext(ctx, "GET", task.URL, http.NoBody)
+	req, err := http.NewRequestWithContext(withRequestPhase(withRedirectTrace(ctx)), "GET", task.URL, http.NoBody)
 	if err != nil {
 		t.logger.Debug("Error creating request in dry-run",
 			"url", util.SanitizeURLDefault(task.URL),
 			"error", err)
 		atomic.AddInt64(&t.results.FailedRequests, 1)
-		t.recordError(task.URL, fmt.Sprintf("creating request: %v", err), task.Depth)
+		t.recordError(task, domain.ErrorClassOther, fmt.Sprintf("creating request: %v", err))
 		return
 	}
 
@@ -290,18 +521,19 @@ func (t *Tester) processDryRun(ctx context.Context, task domain.URLTask) {
 			"url", util.SanitizeURLDefault(task.URL),
 			"error", err)
 		atomic.AddInt64(&t.results.FailedRequests, 1)
-		t.recordError(task.URL, fmt.Sprintf("applying auth: %v", err), task.Depth)
+		t.recordError(task, domain.ErrorClassOther, fmt.Sprintf("applying auth: %v", err))
 		return
 	}
 
 	// Execute request
 	resp, err := t.client.Do(req)
 	if err != nil {
+		err = requestFailed(req, err)
 		t.logger.Debug("Error making request in dry-run",
 			"url", util.SanitizeURLDefault(task.URL),
 			"error", err)
 		atomic.AddInt64(&t.results.FailedRequests, 1)
-		t.recordError(task.URL, fmt.Sprintf("request failed: %v", err), task.Depth)
+		t.recordError(task, errorClass(ctx, err), fmt.Sprintf("request failed: %v", err))
 		return
 	}
 	defer func() {
@@ -311,14 +543,26 @@ func (t *Tester) processDryRun(ctx context.Context, task domain.URLTask) {
 	// Record basic validation (without performance metrics)
 	validation := domain.URLValidation{
 		URL:        task.URL,
+		Method:     task.Method,
+		SourceURL:  task.SourceURL,
+		AnchorText: task.AnchorText,
+		Feed:       task.Feed,
+		Script:     task.Script,
+		Confidence: task.Confidence,
 		StatusCode: resp.StatusCode,
 		Depth:      task.Depth,
-		IsValid:    resp.StatusCode >= 200 && resp.StatusCode < 400,
 	}
 
-	// Discover links from response
+	// Discover links from response, then drain the body so the connection is reused
+	criteria, capture := t.successCriteria(task, resp)
 	validation.LinksFound = t.discoverLinksFromResponse(resp, task)
+	var bodyErr error
+	validation.BytesReceived, bodyErr = t.drainBody(resp, task)
+	t.checkSuccess(ctx, &validation, resp, criteria, capture, bodyErr)
+	t.recordRedirects(&validation, resp, task)
+	t.checkSession(resp, task)
 
+	t.streamRequest(task, validation)
 	t.addValidation(validation)
 
 	t.logger.Info("URL discovered (dry-run)",
@@ -330,11 +574,23 @@ func (t *Tester) processDryRun(ctx context.Context, task domain.URLTask) {
 
 // processURL performs a single URL request and records results
 func (t *Tester) processURL(ctx context.Context, task domain.URLTask) {
-	// Check robots.txt compliance (unless ignoring)
-	if !t.config.IgnoreRobots && !t.robotsParser.IsAllowed(task.URL) {
-		t.logger.Debug("URL blocked by robots.txt", "url", util.SanitizeURLDefault(task.URL))
-		// Record as skipped, not as an error
-		atomic.AddInt64(&t.results.TotalRequests, 1)
+	// Check robots.txt compliance (unless ignoring); blocked URLs are reported, not requested
+	if !t.robotsAllowed(ctx, task) {
+		return
+	}
+
+	// Honor the robots.txt Crawl-delay before requesting the crawled host
+	if !t.waitCrawlDelay(ctx, task) {
+		return
+	}
+
+	// The request is in flight, including any rate-limit wait, until its result is sent
+	atomic.AddInt64(&t.inFlight, 1)
+	defer atomic.AddInt64(&t.inFlight, -1)
+
+	// In link-check mode, check each URL once and record it for the broken-links report
+	if t.config.LinkCheck {
+		t.processLinkCheck(ctx, task)
 		return
 	}
 
@@ -348,7 +604,7 @@ func (t *Tester) processURL(ctx context.Context, task domain.URLTask) {
 	if t.rateLimiter != nil {
 		if err := t.rateLimiter.Wait(ctx); err != nil {
 			// Context was canceled or deadline exceeded
-			t.recordError(task.URL, fmt.Sprintf("rate limiter wait canceled: %v", err), task.Depth)
+			t.recordError(task, domain.ErrorClassCancelled, fmt.Sprintf("rate limiter wait canceled: %v", err))
 			atomic.AddInt64(&t.results.FailedRequests, 1)
 			return
 		}
@@ -357,9 +613,9 @@ func (t *Tester) processURL(ctx context.Context, task domain.URLTask) {
 	atomic.AddInt64(&t.results.TotalRequests, 1)
 
 	// Make HTTP request with 429 retry logic
-	resp, responseTime, err := t.makeHTTPRequestWithRetry(ctx, task.URL)
+	resp, responseTime, err := t.makeHTTPRequestWithRetry(ctx, task)
 	if err != nil {
-		t.recordError(task.URL, fmt.Sprintf("making request: %v", err), task.Depth)
+		t.recordError(task, errorClass(ctx, err), fmt.Sprintf("making request: %v", err))
 		atomic.AddInt64(&t.results.FailedRequests, 1)
 		return
 	}
@@ -367,24 +623,38 @@ func (t *Tester) processURL(ctx context.Context, task domain.URLTask) {
 		_ = resp.Body.Close()
 	}()
 
-	atomic.AddInt64(&t.results.SuccessfulRequests, 1)
-
-	// Record response time
-	t.recordResponseTime(task.URL, responseTime)
-
 	// Create validation record
 	validation := domain.URLValidation{
 		URL:           task.URL,
+		Method:        task.Method,
 		StatusCode:    resp.StatusCode,
 		ResponseTime:  responseTime,
 		ContentLength: resp.ContentLength,
 		ContentType:   resp.Header.Get("Content-Type"),
+		SourceURL:     task.SourceURL,
+		AnchorText:    task.AnchorText,
+		Feed:          task.Feed,
+		Script:        task.Script,
+		Confidence:    task.Confidence,
 		Depth:         task.Depth,
-		IsValid:       resp.StatusCode >= 200 && resp.StatusCode < 400,
 	}
 
-	// Discover links if configured
+	// Discover links if configured, then read the rest of the body so the download is
+	// timed and the connection can be reused; the response is then checked against
+	// its success criteria
+	criteria, capture := t.successCriteria(task, resp)
 	validation.LinksFound = t.discoverLinksFromResponse(resp, task)
+	var bodyErr error
+	validation.BytesReceived, bodyErr = t.drainBody(resp, task)
+	t.checkSuccess(ctx, &validation, resp, criteria, capture, bodyErr)
+	atomic.AddInt64(&t.results.BytesReceived, validation.BytesReceived)
+
+	// Record response time; the phase timing is complete once the body has been read
+	validation.Timing = requestTimingFrom(resp)
+	t.recordResponseTime(task.URL, responseTime, validation.Timing)
+
+	t.recordRedirects(&validation, resp, task)
+	t.checkSession(resp, task)
 
 	// Record slow requests exceeding threshold
 	if responseTime > defaultSlowRequestThreshold {
@@ -392,6 +662,7 @@ func (t *Tester) processURL(ctx context.Context, task domain.URLTask) {
 	}
 
 	// Add validation to results (thread-safe)
+	t.streamRequest(task, validation)
 	t.addValidation(validation)
 
 	t.logger.Debug("URL processed",
@@ -404,7 +675,7 @@ func (t *Tester) processURL(ctx context.Context, task domain.URLTask) {
 
 // makeHTTPRequestWithRetry wraps makeHTTPRequest with exponential backoff retry for 429 responses.
 // Returns the actual request duration (excluding backoff time) for accurate latency metrics.
-func (t *Tester) makeHTTPRequestWithRetry(ctx context.Context, url string) (*http.Response, time.Duration, error) {
+func (t *Tester) makeHTTPRequestWithRetry(ctx context.Context, task domain.URLTask) (*http.Response, time.Duration, error) {
 	const (
 		maxRetries     = 4 // Max retry attempts for 429
 		initialBackoff = 1 * time.Second
@@ -417,7 +688,7 @@ func (t *Tester) makeHTTPRequestWithRetry(ctx context.Context, url string) (*htt
 	backoff := initialBackoff
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		resp, duration, err := t.makeHTTPRequest(ctx, url)
+		resp, duration, err := t.makeHTTPRequest(ctx, task)
 		lastRequestDuration = duration
 
 		// If request failed (network error, etc), return error immediately
@@ -430,18 +701,19 @@ func (t *Tester) makeHTTPRequestWithRetry(ctx context.Context, url string) (*htt
 			return resp, lastRequestDuration, nil
 		}
 
-		// Close the 429 response body before retrying
+		// Drain and close the 429 response body before retrying, keeping the connection
+		t.drainBody(resp, task)
 		_ = resp.Body.Close()
 
 		// If this was the last attempt, return the 429 response
 		if attempt == maxRetries {
 			// Re-make request one final time to return a valid response object
-			return t.makeHTTPRequest(ctx, url)
+			return t.makeHTTPRequest(ctx, task)
 		}
 
 		// Log the backoff
 		t.logger.Info("Received 429 Too Many Requests, backing off",
-			"url", util.SanitizeURLDefault(url),
+			"url", util.SanitizeURLDefault(task.URL),
 			"attempt", attempt+1,
 			"backoff", backoff,
 			"max_retries", maxRetries)
@@ -466,14 +738,25 @@ func (t *Tester) makeHTTPRequestWithRetry(ctx context.Context, url string) (*htt
 }
 
 // makeHTTPRequest creates and executes an HTTP request, returning the response and duration
-func (t *Tester) makeHTTPRequest(ctx context.Context, url string) (*http.Response, time.Duration, error) {
+func (t *Tester) makeHTTPRequest(ctx context.Context, task domain.URLTask) (*http.Response, time.Duration, error) {
 	startTime := time.Now()
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
+	// Create request, tracing redirect hops and phase timing; form submissions carry their fields in the body
+	method := task.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader = http.NoBody
+	if task.Body != "" {
+		body = strings.NewReader(task.Body)
+	}
+	req, err := http.NewRequestWithContext(withRequestPhase(withRequestTimer(withRedirectTrace(ctx))), method, task.URL, body)
 	if err != nil {
 		return nil, 0, fmt.Errorf("creating request: %w", err)
 	}
+	if task.Body != "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
 	// Set headers
 	req.Header.Set("User-Agent", t.config.UserAgent)
@@ -489,9 +772,10 @@ func (t *Tester) makeHTTPRequest(ctx context.Context, url string) (*http.Respons
 	responseTime := time.Since(startTime)
 
 	if err != nil {
-		return nil, responseTime, err
+		return nil, responseTime, requestFailed(req, err)
 	}
 
+	timeBody(resp)
 	return resp, responseTime, nil
 }
 
@@ -563,71 +847,200 @@ func (t *Tester) applyAuthentication(req *http.Request) error {
 	return nil
 }
 
-// discoverLinksFromResponse extracts links from HTML responses and adds them to the crawl queue
+// discoverLinksFromResponse extracts links from a response and adds them to the crawl queue.
+// The content type selects the body extractor; Link headers are read from any response.
 func (t *Tester) discoverLinksFromResponse(resp *http.Response, task domain.URLTask) int {
-	// Only process HTML responses
-	if !t.config.FollowLinks || task.Depth >= t.config.MaxDepth ||
-		!strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+	if !t.config.FollowLinks || task.Depth >= t.config.MaxDepth {
 		return 0
 	}
 
-	// Check Content-Length before reading body
-	maxSize := t.config.MaxResponseSize
-	if maxSize == 0 {
-		maxSize = 10 * 1024 * 1024 // Default 10MB
+	extractor := crawler.BodyExtractor(resp.Header.Get("Content-Type"))
+	// HTML pages are also read for feed autodiscovery and script files when only those are extracted
+	readHTML := extractor == domain.ExtractorHTML &&
+		(t.extractorEnabled(domain.ExtractorFeed) || t.extractorEnabled(domain.ExtractorJS))
+	var body string
+	if extractor != "" && (t.extractorEnabled(extractor) || readHTML) {
+		var ok bool
+		if body, ok = t.readBodyForLinks(resp, task, extractor); !ok {
+			extractor = ""
+		}
+	} else {
+		extractor = ""
+	}
+
+	// Pages whose canonical URL was already crawled are duplicates; their links are too
+	if extractor == domain.ExtractorHTML && t.config.Normalization.HonorCanonical {
+		if canonical := t.crawler.ExtractCanonical(body); canonical != "" &&
+			t.crawler.RegisterCanonical(task.URL, canonical) {
+			t.logger.Debug("Skipping link extraction: canonical URL already crawled",
+				"url", util.SanitizeURLDefault(task.URL),
+				"canonical", util.SanitizeURLDefault(canonical))
+			return 0
+		}
+	}
+
+	var links []domain.Link
+	switch extractor {
+	case domain.ExtractorHTML:
+		if t.extractorEnabled(domain.ExtractorHTML) {
+			links = t.crawler.ExtractLinksWithText(body)
+			links = append(links, t.crawler.ExtractFormLinks(body)...)
+		}
+		if t.extractorEnabled(domain.ExtractorFeed) {
+			links = append(links, t.crawler.ExtractFeedLinks(body)...)
+		}
+		if t.extractorEnabled(domain.ExtractorJS) {
+			links = append(links, t.crawler.ExtractScriptLinks(body)...)
+		}
+	case domain.ExtractorJSON:
+		links = t.crawler.ExtractJSONLinks(body)
+	case domain.ExtractorFeed:
+		links = t.crawler.ExtractFeedItems(body)
+	case domain.ExtractorJS:
+		links = t.crawler.ExtractJSEndpoints(body)
+	}
+	if t.extractorEnabled(domain.ExtractorLinkHeader) {
+		links = append(links, t.crawler.ParseLinkHeader(resp.Header.Values("Link"))...)
+	}
+	if extractor == "" && len(links) == 0 {
+		return 0
+	}
+
+	if t.graph != nil {
+		t.graph.MarkCrawled(task.URL, task.Depth)
+	}
+
+	// Pages can ask crawlers not to follow any of their links
+	if !t.config.IgnoreNofollow {
+		if robots.HeaderNofollow(resp.Header.Values("X-Robots-Tag"), t.config.UserAgent) {
+			atomic.AddInt64(&t.results.NofollowSkips.XRobotsTag, int64(len(links)))
+			return len(links)
+		}
+		if extractor == domain.ExtractorHTML && robots.MetaNofollow(body, t.config.UserAgent) {
+			atomic.AddInt64(&t.results.NofollowSkips.MetaRobots, int64(len(links)))
+			return len(links)
+		}
+	}
+
+	// Queue links, resolving relative links against the page's final URL
+	for _, link := range links {
+		if link.NoFollow && !t.config.IgnoreNofollow {
+			atomic.AddInt64(&t.results.NofollowSkips.RelNofollow, 1)
+			continue
+		}
+		link.SourceURL = resp.Request.URL.String()
+		result := t.crawler.AddLink(link, task.Depth+1, t.urlQueue)
+		t.recordLink(task, result, task.Depth+1)
+		if result.Added {
+			t.results.URLsDiscovered = t.crawler.GetDiscoveredCount()
+		}
 	}
+
+	return len(links)
+}
+
+// extractorEnabled reports whether the named link extractor is configured to run.
+func (t *Tester) extractorEnabled(extractor string) bool {
+	if len(t.config.LinkExtractors) == 0 {
+		return slices.Contains(domain.DefaultLinkExtractors, extractor)
+	}
+	return slices.Contains(t.config.LinkExtractors, extractor)
+}
+
+// readBodyForLinks reads a response body for link extraction. HTML is read up to
+// linkExtractionLimit; JSON, feeds and scripts must be complete to parse, so they are read up to MaxResponseSize.
+func (t *Tester) readBodyForLinks(resp *http.Response, task domain.URLTask, extractor string) (string, bool) {
+	// Check Content-Length before reading body
+	maxSize := t.maxResponseSize()
 	if resp.ContentLength > maxSize {
 		t.logger.Debug("Skipping link extraction: response too large",
 			"url", util.SanitizeURLDefault(task.URL),
 			"content_length", resp.ContentLength,
 			"max_size", maxSize)
-		return 0
+		return "", false
 	}
 
-	// Limit body reading for link extraction (smaller than MaxResponseSize)
-	limitedReader := io.LimitReader(resp.Body, linkExtractionLimit)
-	body, readErr := io.ReadAll(limitedReader)
+	// Limit body reading for link extraction (smaller than MaxResponseSize for HTML)
+	limit := int64(linkExtractionLimit)
+	if extractor != domain.ExtractorHTML {
+		limit = maxSize
+	}
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, limit))
 	if readErr != nil && !errors.Is(readErr, io.EOF) {
 		t.logger.Debug("Error reading response body for link extraction",
 			"url", util.SanitizeURLDefault(task.URL),
 			"error", readErr)
-		return 0
+		return "", false
 	}
+	return string(body), true
+}
 
-	// Extract and queue links
-	links := t.crawler.ExtractLinks(string(body))
-	for _, link := range links {
-		result := t.crawler.AddURL(link, task.Depth+1, t.urlQueue)
-		if result.Added {
-			t.results.URLsDiscovered = t.crawler.GetDiscoveredCount()
-		}
+// maxResponseSize returns the cap on response body bytes read per request.
+func (t *Tester) maxResponseSize() int64 {
+	if t.config.MaxResponseSize > 0 {
+		return t.config.MaxResponseSize
 	}
+	return domain.DefaultMaxResponseSize
+}
 
-	return len(links)
+// drainBody reads what is left of a response body, so that its connection can be
+// reused, and returns the body bytes received and any error reading it. Bodies larger
+// than MaxResponseSize are abandoned at the cap; closing them closes the connection instead.
+func (t *Tester) drainBody(resp *http.Response, task domain.URLTask) (int64, error) {
+	maxSize := t.maxResponseSize()
+	timer := requestTimerFrom(resp)
+	var received int64
+	if timer != nil {
+		received = timer.bytesRead()
+	}
+
+	// Read one byte past the cap to tell a body that fits from one that does not
+	drained, err := io.Copy(io.Discard, io.LimitReader(resp.Body, max(maxSize-received, 0)+1))
+	if timer != nil {
+		received = timer.bytesRead()
+	} else {
+		received += drained
+	}
+	if err != nil {
+		t.logger.Debug("Error reading response body",
+			"url", util.SanitizeURLDefault(task.URL),
+			"error", err)
+	}
+	if received > maxSize {
+		t.logger.Debug("Response body exceeds max size, not read to the end",
+			"url", util.SanitizeURLDefault(task.URL),
+			"max_size", maxSize)
+		received = maxSize
+	}
+	return received, err
 }
 
 // recordError records an error encountered during testing.
 // Error messages are sanitized to hide internal infrastructure details
 // unless verbose mode is enabled.
-func (t *Tester) recordError(url, errMsg string, depth int) {
+func (t *Tester) recordError(task domain.URLTask, class domain.ErrorClass, errMsg string) {
 	// Sanitize error message to hide internal details unless verbose
 	sanitizedErr := util.SanitizeErrorForDisplay(errMsg, t.config.Verbose)
 
 	errorInfo := domain.ErrorInfo{
-		URL:       url,
+		URL:       task.URL,
+		Method:    task.Method,
 		Error:     sanitizedErr,
+		Class:     class,
 		Timestamp: time.Now(),
-		Depth:     depth,
+		Depth:     task.Depth,
 	}
+	t.streamRequest(task, domain.URLValidation{Error: sanitizedErr, ErrorClass: class})
 	t.addError(errorInfo)
 }
 
-// recordResponseTime records a response time measurement
-func (t *Tester) recordResponseTime(url string, responseTime time.Duration) {
+// recordResponseTime records a response time measurement and its phase timing (nil if not traced)
+func (t *Tester) recordResponseTime(url string, responseTime time.Duration, timing *domain.RequestTiming) {
 	entry := domain.ResponseTimeEntry{
 		URL:          url,
 		ResponseTime: responseTime,
 		Timestamp:    time.Now(),
+		Timing:       timing,
 	}
 	t.addResponseTime(entry)
 }
@@ -668,24 +1081,27 @@ func (t *Tester) monitor(ctx context.Context, startTime time.Time) {
 		}
 	}()
 
-	if t.config.NoProgress {
-		// No progress updates, just wait for context
-		<-ctx.Done()
-		return
+	// Progress updates every second; time-series buckets close every metrics interval
+	var progressTick <-chan time.Time
+	if !t.config.NoProgress {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		progressTick = ticker.C
 	}
-
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	bucketTicker := time.NewTicker(t.metricsInterval())
+	defer bucketTicker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case now := <-bucketTicker.C:
+			t.series.flush(now, atomic.LoadInt64(&t.inFlight))
+		case <-progressTick:
 			// Safely load atomic counters
 			total := atomic.LoadInt64(&t.results.TotalRequests)
 			successful := atomic.LoadInt64(&t.results.SuccessfulRequests)
 			failed := atomic.LoadInt64(&t.results.FailedRequests)
 			discovered := t.results.URLsDiscovered
-			queueSize := len(t.urlQueue)
+			queueSize := len(t.urlQueue) + t.crawler.GetSpilledCount()
 
 			// Calculate elapsed time and rate
 			elapsed := time.Since(startTime)
@@ -715,7 +1131,7 @@ func (t *Tester) monitor(ctx context.Context, startTime time.Time) {
 					queueSize)
 			}
 		case <-ctx.Done():
-			if !t.config.Verbose {
+			if !t.config.Verbose && !t.config.NoProgress {
 				// Print newline to finalize progress line
 				fmt.Fprintf(os.Stderr, "\n")
 			}
@@ -728,32 +1144,30 @@ func (t *Tester) monitor(ctx context.Context, startTime time.Time) {
 // Note: Safe to access results directly since aggregator has finished
 func (t *Tester) calculateResults(duration time.Duration) {
 	t.results.Duration = duration.String()
-
-	// Calculate response time statistics
-	responseTimes := make([]time.Duration, len(t.results.ResponseTimes))
-	for i, entry := range t.results.ResponseTimes {
-		responseTimes[i] = entry.ResponseTime
+	if t.config.LinkCheck {
+		addReferrers(t.results.BrokenLinks, t.crawler.GetReferrers)
 	}
 
-	if len(responseTimes) > 0 {
-		sort.Slice(responseTimes, func(i, j int) bool {
-			return responseTimes[i] < responseTimes[j]
-		})
-
-		t.results.MinResponseTime = responseTimes[0].String()
-		t.results.MaxResponseTime = responseTimes[len(responseTimes)-1].String()
-
-		// Calculate average
-		var total time.Duration
-		for _, rt := range responseTimes {
-			total += rt
-		}
-		t.results.AverageResponseTime = (total / time.Duration(len(responseTimes))).String()
+	// Calculate response time statistics from the histograms, which cover every request
+	histograms := t.results.LatencyHistograms()
+	t.results.Latency = nil
+	if histograms.ResponseTime.Count() > 0 {
+		latency := domain.SummarizeLatency(histograms.ResponseTime)
+		t.results.Latency = &latency
+		t.results.MinResponseTime = latency.Min.String()
+		t.results.MaxResponseTime = latency.Max.String()
+		t.results.AverageResponseTime = latency.Mean.String()
 	}
+	t.results.Timing = timingBreakdown(histograms)
+	t.results.RouteStats = t.routeStats.Routes(t.config.Routes.Sort)
+	t.results.URLStats = t.routeStats.URLs(t.config.Routes.Sort)
+	t.results.ErrorClasses = domain.TotalErrorClasses(t.results.RouteStats)
+	t.results.TimeSeries = t.series.snapshot()
 
 	// Calculate rates
 	if duration.Seconds() > 0 {
 		t.results.RequestsPerSecond = float64(t.results.TotalRequests) / duration.Seconds()
+		t.results.BytesPerSecond = float64(t.results.BytesReceived) / duration.Seconds()
 	}
 
 	if t.results.TotalRequests > 0 {
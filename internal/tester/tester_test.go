@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -36,6 +38,24 @@ func testConfig(baseURL string) domain.TesterConfig {
 	}
 }
 
+// processTask runs processURL for rawURL and returns the validations and errors
+// it sent to the aggregator.
+func processTask(t *testing.T, tester *Tester, rawURL string) ([]domain.URLValidation, []domain.ErrorInfo) {
+	t.Helper()
+	tester.processURL(context.Background(), domain.URLTask{URL: rawURL})
+	close(tester.validationsCh)
+	close(tester.errorsCh)
+	var validations []domain.URLValidation
+	for v := range tester.validationsCh {
+		validations = append(validations, v)
+	}
+	var errs []domain.ErrorInfo
+	for errInfo := range tester.errorsCh {
+		errs = append(errs, errInfo)
+	}
+	return validations, errs
+}
+
 func TestNew_Success(t *testing.T) {
 	config := testConfig("http://example.com")
 	logger := testLogger()
@@ -181,7 +201,7 @@ func TestMakeHTTPRequest_Success(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	resp, duration, err := tester.makeHTTPRequest(ctx, server.URL)
+	resp, duration, err := tester.makeHTTPRequest(ctx, domain.URLTask{URL: server.URL})
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -218,7 +238,7 @@ func TestMakeHTTPRequest_ContextCanceled(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	_, _, err = tester.makeHTTPRequest(ctx, server.URL)
+	_, _, err = tester.makeHTTPRequest(ctx, domain.URLTask{URL: server.URL})
 
 	if err == nil {
 		t.Fatal("Expected error due to context timeout")
@@ -317,6 +337,42 @@ func TestDiscoverLinksFromResponse_FollowLinksDisabled(t *testing.T) {
 	testDiscoverLinksScenario(t, "text/html", `<a href="/page">Link</a>`, false, 5, 0, 0)
 }
 
+func TestDiscoverLinksFromResponse_HonorCanonical(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<link rel="canonical" href="/"><a href="/page1">Link</a>`))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	config.FollowLinks = true
+	config.MaxDepth = 5
+	config.Normalization = domain.DefaultURLNormalization()
+	config.Normalization.HonorCanonical = true
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	// The canonical target has already been discovered
+	tester.crawler.AddURL(server.URL, 0, tester.urlQueue)
+
+	resp, err := http.Get(server.URL + "/variant?color=red")
+	if err != nil {
+		t.Fatalf("Failed to get test page: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	task := domain.URLTask{URL: server.URL + "/variant?color=red", Depth: 1}
+	if linksFound := tester.discoverLinksFromResponse(resp, task); linksFound != 0 {
+		t.Errorf("Expected links of canonical duplicate to be skipped, got %d", linksFound)
+	}
+	if got := tester.crawler.GetCanonicalDuplicateCount(); got != 1 {
+		t.Errorf("Expected 1 canonical duplicate, got %d", got)
+	}
+}
+
 func TestRun_BasicWorkflow(t *testing.T) {
 	requestCount := int32(0)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -502,6 +558,11 @@ func TestCalculateResults(t *testing.T) {
 		t.Errorf("Expected avg 200ms, got %s", tester.results.AverageResponseTime)
 	}
 
+	// Check the typed latency statistics
+	if latency := tester.results.Latency; latency == nil || latency.Count != 3 || latency.Mean != 200*time.Millisecond {
+		t.Errorf("Expected latency statistics over 3 response times with mean 200ms, got %+v", latency)
+	}
+
 	// Check rates
 	if tester.results.RequestsPerSecond != 5.0 {
 		t.Errorf("Expected 5 req/s, got %.1f", tester.results.RequestsPerSecond)
@@ -519,6 +580,53 @@ func TestCalculateResults(t *testing.T) {
 	}
 }
 
+func TestAggregator_RouteStats(t *testing.T) {
+	config := testConfig("http://example.com")
+	config.Routes = domain.RoutePolicy{Sort: domain.RouteSortErrors}
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go tester.aggregator(&wg)
+
+	tester.addValidation(domain.URLValidation{URL: "http://example.com/", StatusCode: 200, ResponseTime: 5 * time.Millisecond, IsValid: true})
+	tester.addValidation(domain.URLValidation{URL: "http://example.com/users/1", StatusCode: 200, ResponseTime: 10 * time.Millisecond, IsValid: true})
+	tester.addValidation(domain.URLValidation{URL: "http://example.com/users/2", StatusCode: 503, ResponseTime: 20 * time.Millisecond})
+	tester.recordError(domain.URLTask{URL: "http://example.com/users/3"}, domain.ErrorClassConnectionRefused, "connection refused")
+	// Link-check failures send both an error and a response-less validation
+	tester.recordError(domain.URLTask{URL: "http://example.com/users/4"}, domain.ErrorClassConnectionReset, "connection reset")
+	tester.addValidation(domain.URLValidation{URL: "http://example.com/users/4", Error: "connection reset"})
+
+	close(tester.validationsCh)
+	close(tester.errorsCh)
+	close(tester.responseTimesCh)
+	close(tester.slowRequestsCh)
+	wg.Wait()
+	tester.calculateResults(time.Second)
+
+	routes := tester.results.RouteStats
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %+v", routes)
+	}
+	users := routes[0]
+	if users.Name != "/users/:id" || users.Requests != 4 || users.Errors != 3 {
+		t.Errorf("Expected /users/:id first with 4 requests and 3 errors, got %s with %d and %d", users.Name, users.Requests, users.Errors)
+	}
+	if users.Latency.Count != 2 || users.StatusCodes[503] != 1 {
+		t.Errorf("Expected 2 timed responses including one 503, got %d and %v", users.Latency.Count, users.StatusCodes)
+	}
+	if len(tester.results.URLStats) != 5 {
+		t.Errorf("Expected statistics for 5 URLs, got %d", len(tester.results.URLStats))
+	}
+	classes := tester.results.ErrorClasses
+	if len(classes) != 3 || classes[domain.ErrorClassHTTP5xx] != 1 || classes[domain.ErrorClassConnectionRefused] != 1 || classes[domain.ErrorClassConnectionReset] != 1 {
+		t.Errorf("Expected one 5xx, one refused and one reset connection, got %v", classes)
+	}
+}
+
 func TestAggregator_ChannelCollection(t *testing.T) {
 	config := testConfig("http://example.com")
 	logger := testLogger()
@@ -595,7 +703,7 @@ func TestRecordError(t *testing.T) {
 	go tester.aggregator(&wg)
 
 	// Record error
-	tester.recordError("http://test.com", "test error message", 1)
+	tester.recordError(domain.URLTask{URL: "http://test.com", Depth: 1}, domain.ErrorClassOther, "test error message")
 
 	// Close channels to signal completion and wait for aggregator to finish
 	close(tester.validationsCh)
@@ -926,7 +1034,7 @@ func TestApplyAuthentication_IntegrationWithHTTPRequest(t *testing.T) {
 	defer cancel()
 
 	// Make a request
-	resp, _, err := tester.makeHTTPRequest(ctx, server.URL)
+	resp, _, err := tester.makeHTTPRequest(ctx, domain.URLTask{URL: server.URL})
 	if err != nil {
 		t.Fatalf("Failed to make HTTP request: %v", err)
 	}
@@ -990,7 +1098,7 @@ func TestMakeHTTPRequestWithRetry_429Backoff(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, _, err := tester.makeHTTPRequestWithRetry(ctx, server.URL)
+	resp, _, err := tester.makeHTTPRequestWithRetry(ctx, domain.URLTask{URL: server.URL})
 	if err != nil {
 		t.Fatalf("Expected successful retry, got error: %v", err)
 	}
@@ -1047,7 +1155,7 @@ func TestMakeHTTPRequestWithRetry_429MaxRetries(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
-	resp, _, err := tester.makeHTTPRequestWithRetry(ctx, server.URL)
+	resp, _, err := tester.makeHTTPRequestWithRetry(ctx, domain.URLTask{URL: server.URL})
 	if err != nil {
 		t.Fatalf("Expected response (not error) after max retries, got: %v", err)
 	}
@@ -1081,7 +1189,7 @@ func TestMakeHTTPRequestWithRetry_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	_, _, err = tester.makeHTTPRequestWithRetry(ctx, server.URL)
+	_, _, err = tester.makeHTTPRequestWithRetry(ctx, domain.URLTask{URL: server.URL})
 	if err == nil {
 		t.Error("Expected context cancellation error, got nil")
 	}
@@ -1113,7 +1221,7 @@ func TestMakeHTTPRequestWithRetry_Respect429Disabled(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, _, err := tester.makeHTTPRequestWithRetry(ctx, server.URL)
+	resp, _, err := tester.makeHTTPRequestWithRetry(ctx, domain.URLTask{URL: server.URL})
 	if err != nil {
 		t.Fatalf("Expected response, got error: %v", err)
 	}
@@ -1471,3 +1579,438 @@ func TestMonitor_VerboseMode(t *testing.T) {
 		t.Error("Monitor in verbose mode did not exit in time")
 	}
 }
+
+func TestRun_SpillsQueueOverflowToDisk(t *testing.T) {
+	const linkCount = 20
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path != "/" {
+			_, _ = w.Write([]byte("<html><body>leaf</body></html>"))
+			return
+		}
+		var body strings.Builder
+		body.WriteString("<html><body>")
+		for i := 0; i < linkCount; i++ {
+			body.WriteString(`<a href="/page` + strconv.Itoa(i) + `">link</a>`)
+		}
+		body.WriteString("</body></html>")
+		_, _ = w.Write([]byte(body.String()))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	config.FollowLinks = true
+	config.QueueSize = 2
+	config.Frontier = domain.DefaultFrontierConfig()
+	config.Frontier.SpillDir = t.TempDir()
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	if dropped := tester.crawler.GetDroppedCount(); dropped != 0 {
+		t.Errorf("Expected no dropped URLs with spilling enabled, got %d", dropped)
+	}
+
+	// Every discovered page must have been fetched despite the tiny queue
+	if got := len(results.URLValidations); got != linkCount+1 {
+		t.Errorf("Expected %d validated URLs, got %d", linkCount+1, got)
+	}
+}
+
+func TestRun_ReportsCrawlTraps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path != "/" {
+			_, _ = w.Write([]byte("<html><body>results</body></html>"))
+			return
+		}
+		var body strings.Builder
+		body.WriteString("<html><body>")
+		for i := 0; i < 10; i++ {
+			body.WriteString(`<a href="/search?q=` + strconv.Itoa(i) + `">search</a>`)
+		}
+		body.WriteString("</body></html>")
+		_, _ = w.Write([]byte(body.String()))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	config.FollowLinks = true
+	config.Traps = domain.DefaultTrapPolicy()
+	config.Traps.MaxPathVariants = 3
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	if got := len(results.URLValidations); got != 4 {
+		t.Errorf("Expected the root and 3 search variants validated, got %d", got)
+	}
+	if len(results.CrawlTraps) != 1 {
+		t.Fatalf("Expected 1 crawl trap, got %+v", results.CrawlTraps)
+	}
+	trap := results.CrawlTraps[0]
+	if trap.Reason != domain.TrapPathVariants || trap.Suppressed != 7 {
+		t.Errorf("Expected 7 path variants suppressed, got %+v", trap)
+	}
+}
+
+func TestDiscoverLinksFromResponse_Nofollow(t *testing.T) {
+	links := `<a href="/one">One</a><a href="/two" rel="nofollow">Two</a>`
+
+	tests := []struct {
+		name       string
+		header     string
+		head       string
+		ignore     bool
+		wantQueued int
+		wantSkips  domain.NofollowSkips
+	}{
+		{
+			name:       "rel=nofollow skips the link",
+			wantQueued: 1,
+			wantSkips:  domain.NofollowSkips{RelNofollow: 1},
+		},
+		{
+			name:      "meta robots skips every link",
+			head:      `<meta name="robots" content="noindex,nofollow">`,
+			wantSkips: domain.NofollowSkips{MetaRobots: 2},
+		},
+		{
+			name:      "X-Robots-Tag skips every link",
+			header:    "nofollow",
+			head:      `<meta name="robots" content="nofollow">`,
+			wantSkips: domain.NofollowSkips{XRobotsTag: 2},
+		},
+		{
+			name:       "override follows everything",
+			header:     "nofollow",
+			ignore:     true,
+			wantQueued: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				if tt.header != "" {
+					w.Header().Set("X-Robots-Tag", tt.header)
+				}
+				_, _ = w.Write([]byte("<html><head>" + tt.head + "</head><body>" + links + "</body></html>"))
+			}))
+			defer server.Close()
+
+			config := testConfig(server.URL)
+			config.FollowLinks = true
+			config.MaxDepth = 3
+			config.IgnoreNofollow = tt.ignore
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Failed to get test page: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if found := tester.discoverLinksFromResponse(resp, domain.URLTask{URL: server.URL, Depth: 0}); found != 2 {
+				t.Errorf("Expected 2 links found, got %d", found)
+			}
+			if queued := len(tester.urlQueue); queued != tt.wantQueued {
+				t.Errorf("Expected %d links queued, got %d", tt.wantQueued, queued)
+			}
+			if tester.results.NofollowSkips != tt.wantSkips {
+				t.Errorf("Expected skips %+v, got %+v", tt.wantSkips, tester.results.NofollowSkips)
+			}
+		})
+	}
+}
+
+func TestRun_CrawlsHypermediaAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/":
+			w.Header().Set("Content-Type", "application/hal+json")
+			_, _ = w.Write([]byte(`{"_links": {"self": {"href": "/"}, "orders": {"href": "/orders"}}}`))
+		case "/orders":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Header().Set("Link", `</orders?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`{"data": [{"id": "1", "links": {"self": "/orders/1"}}]}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		extractors []string
+		want       []string
+	}{
+		{
+			name: "all extractors",
+			want: []string{"/", "/orders", "/orders/1", "/orders?page=2"},
+		},
+		{
+			name:       "JSON only",
+			extractors: []string{domain.ExtractorJSON},
+			want:       []string{"/", "/orders", "/orders/1"},
+		},
+		{
+			name:       "HTML only",
+			extractors: []string{domain.ExtractorHTML},
+			want:       []string{"/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(server.URL + "/")
+			config.FollowLinks = true
+			config.MaxDepth = 3
+			config.LinkExtractors = tt.extractors
+
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			results, err := tester.Run(ctx)
+			if err != nil {
+				t.Fatalf("Expected no error from Run, got: %v", err)
+			}
+
+			var got []string
+			for _, validation := range results.URLValidations {
+				got = append(got, strings.TrimPrefix(validation.URL, server.URL))
+			}
+			sort.Strings(got)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Expected %v crawled, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRun_DiscoversFeedItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head>
+				<body><a href="/about">About</a></body></html>`))
+		case "/feed.xml":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = w.Write([]byte(`<rss version="2.0"><channel><link>/</link>
+				<item><title>Hello</title><link>/posts/hello</link></item></channel></rss>`))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><body>ok</body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL + "/")
+	config.FollowLinks = true
+	config.MaxDepth = 3
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	feeds := make(map[string]string)
+	for _, validation := range results.URLValidations {
+		feeds[strings.TrimPrefix(validation.URL, server.URL)] = validation.Feed
+	}
+	if len(feeds) != 4 {
+		t.Errorf("Expected 4 URLs crawled, got %v", feeds)
+	}
+	if feed := feeds["/posts/hello"]; feed != server.URL+"/feed.xml" {
+		t.Errorf("Expected feed item tagged with %s/feed.xml, got %q", server.URL, feed)
+	}
+	if feed := feeds["/about"]; feed != "" {
+		t.Errorf("Expected page link without a feed, got %q", feed)
+	}
+}
+
+func TestRun_SubmitsForms(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		mu.Lock()
+		requests[r.Method+" "+r.URL.RequestURI()+" "+r.PostForm.Encode()] = true
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<html><body>
+				<form action="/search"><input name="q"><select name="sort"><option>new</option><option>old</option></select></form>
+				<form method="post" action="/subscribe"><input name="email" type="email" value="a@example.com"></form>
+			</body></html>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<html><body>ok</body></html>`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		submitPost bool
+		want       []string
+	}{
+		{
+			name: "GET forms only",
+			want: []string{"GET / ", "GET /search?q=test&sort=new ", "GET /search?q=test&sort=old "},
+		},
+		{
+			name:       "POST forms opted in",
+			submitPost: true,
+			want:       []string{"GET / ", "GET /search?q=test&sort=new ", "GET /search?q=test&sort=old ", "POST /subscribe email=a%40example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			clear(requests)
+			mu.Unlock()
+
+			config := testConfig(server.URL + "/")
+			config.FollowLinks = true
+			config.MaxDepth = 2
+			config.Forms = domain.DefaultFormPolicy()
+			config.Forms.SubmitPost = tt.submitPost
+
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			if _, err := tester.Run(ctx); err != nil {
+				t.Fatalf("Expected no error from Run, got: %v", err)
+			}
+
+			mu.Lock()
+			var got []string
+			for request := range requests {
+				got = append(got, request)
+			}
+			mu.Unlock()
+			sort.Strings(got)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Expected requests %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRun_ExtractsScriptEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><script src="/app.js"></script></head><body>home</body></html>`))
+		case "/app.js":
+			w.Header().Set("Content-Type", "text/javascript")
+			_, _ = w.Write([]byte(`fetch("/api/users").then(r => r.json()); const about = "/about";`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		extractors []string
+		want       map[string]string // path -> confidence
+	}{
+		{
+			name: "off by default",
+			want: map[string]string{"/": ""},
+		},
+		{
+			name:       "js extractor enabled",
+			extractors: []string{domain.ExtractorHTML, domain.ExtractorJS},
+			want: map[string]string{
+				"/":          "",
+				"/app.js":    "",
+				"/api/users": domain.ConfidenceHigh,
+				"/about":     domain.ConfidenceLow,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(server.URL + "/")
+			config.FollowLinks = true
+			config.MaxDepth = 3
+			config.LinkExtractors = tt.extractors
+
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			results, err := tester.Run(ctx)
+			if err != nil {
+				t.Fatalf("Expected no error from Run, got: %v", err)
+			}
+
+			if len(results.URLValidations) != len(tt.want) {
+				t.Errorf("Expected %d URLs crawled, got %+v", len(tt.want), results.URLValidations)
+			}
+			for _, validation := range results.URLValidations {
+				path := strings.TrimPrefix(validation.URL, server.URL)
+				confidence, ok := tt.want[path]
+				if !ok {
+					t.Errorf("Unexpected URL crawled: %s", path)
+					continue
+				}
+				if validation.Confidence != confidence {
+					t.Errorf("%s: expected confidence %q, got %q", path, confidence, validation.Confidence)
+				}
+				if confidence != "" && validation.Script != server.URL+"/app.js" {
+					t.Errorf("%s: expected script %s/app.js, got %q", path, server.URL, validation.Script)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package tester
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+// timeSeries collects the requests completed in each metrics interval. The
+// aggregator records requests while the monitor closes intervals, so it is locked.
+type timeSeries struct {
+	mu        sync.Mutex
+	buckets   []domain.TimeBucket
+	current   domain.TimeBucket
+	latency   *histogram.Histogram
+	precision int
+}
+
+// newTimeSeries returns a series for a run whose latency histograms keep precision
+// significant digits, with its first interval starting now. Interval histograms
+// keep one digit less, as route histograms do.
+func newTimeSeries(precision int) *timeSeries {
+	s := &timeSeries{precision: max(histogram.MinPrecision, precision-1)}
+	s.begin(time.Now(), 0, nil)
+	return s
+}
+
+// begin restarts the series at now, offset into the run, continuing the
+// buckets of an earlier run when resuming.
+func (s *timeSeries) begin(now time.Time, offset time.Duration, earlier []domain.TimeBucket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets = slices.Clone(earlier)
+	s.reset(now, offset)
+}
+
+// reset starts an empty interval at now. Callers hold s.mu.
+func (s *timeSeries) reset(now time.Time, offset time.Duration) {
+	s.current = domain.TimeBucket{Start: now, Offset: offset}
+	s.latency = histogram.New(s.precision)
+}
+
+// record adds a request that got a response.
+func (s *timeSeries) record(validation domain.URLValidation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Requests++
+	s.current.BytesReceived += validation.BytesReceived
+	if validation.IsValid {
+		s.current.Successes++
+	} else {
+		s.fail(validation.FailureClass())
+	}
+	if validation.ResponseTime > 0 {
+		s.latency.Record(validation.ResponseTime)
+	}
+}
+
+// recordFailure adds a request that got no response, failing with class.
+func (s *timeSeries) recordFailure(class domain.ErrorClass) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Requests++
+	s.fail(class)
+}
+
+// fail counts a failure of class in the current interval. Callers hold s.mu.
+func (s *timeSeries) fail(class domain.ErrorClass) {
+	if s.current.Failures == nil {
+		s.current.Failures = make(map[domain.ErrorClass]int64)
+	}
+	s.current.Failures[class]++
+}
+
+// flush closes the current interval at now, with inFlight requests in progress,
+// and starts the next one.
+func (s *timeSeries) flush(now time.Time, inFlight int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elapsed := now.Sub(s.current.Start)
+	if elapsed <= 0 {
+		return
+	}
+
+	bucket := s.current
+	bucket.Duration = elapsed
+	bucket.InFlight = inFlight
+	bucket.P50 = s.latency.Quantile(0.50)
+	bucket.P95 = s.latency.Quantile(0.95)
+	bucket.P99 = s.latency.Quantile(0.99)
+	s.buckets = append(s.buckets, bucket)
+
+	s.reset(now, bucket.Offset+elapsed)
+}
+
+// snapshot returns the closed buckets.
+func (s *timeSeries) snapshot() []domain.TimeBucket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.buckets)
+}
+
+// metricsInterval returns the width of each time-series bucket.
+func (t *Tester) metricsInterval() time.Duration {
+	if t.config.MetricsInterval > 0 {
+		return t.config.MetricsInterval
+	}
+	return domain.DefaultMetricsInterval
+}
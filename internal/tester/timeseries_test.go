@@ -0,0 +1,106 @@
+package tester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestTimeSeries_Buckets(t *testing.T) {
+	series := newTimeSeries(3)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	series.begin(start, 0, nil)
+
+	series.record(domain.URLValidation{StatusCode: 200, ResponseTime: 10 * time.Millisecond, BytesReceived: 100, IsValid: true})
+	series.record(domain.URLValidation{StatusCode: 503, ResponseTime: 90 * time.Millisecond, BytesReceived: 20})
+	series.recordFailure(domain.ErrorClassTimeoutTTFB)
+	series.flush(start.Add(time.Second), 4)
+
+	series.record(domain.URLValidation{StatusCode: 404, ResponseTime: 5 * time.Millisecond})
+	series.flush(start.Add(1500*time.Millisecond), 0)
+
+	// An interval closed twice at the same instant adds no bucket
+	series.flush(start.Add(1500*time.Millisecond), 0)
+
+	buckets := series.snapshot()
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(buckets))
+	}
+
+	first := buckets[0]
+	if first.Requests != 3 || first.Successes != 1 || first.BytesReceived != 120 || first.InFlight != 4 {
+		t.Errorf("Expected 3 requests, 1 success, 120 bytes and 4 in flight, got %+v", first)
+	}
+	if first.Failures[domain.ErrorClassHTTP5xx] != 1 || first.Failures[domain.ErrorClassTimeoutTTFB] != 1 || first.FailureCount() != 2 {
+		t.Errorf("Expected one 5xx and one timeout, got %v", first.Failures)
+	}
+	if !first.Start.Equal(start) || first.Offset != 0 || first.Duration != time.Second {
+		t.Errorf("Expected the first bucket to cover the first second, got start %v, offset %v, duration %v", first.Start, first.Offset, first.Duration)
+	}
+	// By nearest rank, the median of two responses is the slower one
+	if first.P50 != first.P99 || first.P99 < 89*time.Millisecond || first.P99 > 91*time.Millisecond {
+		t.Errorf("Expected p50 and p99 near 90ms, got %v and %v", first.P50, first.P99)
+	}
+
+	second := buckets[1]
+	if second.Offset != time.Second || second.Duration != 500*time.Millisecond || second.Failures[domain.ErrorClassHTTP4xx] != 1 {
+		t.Errorf("Expected a half-second bucket after 1s with one 4xx, got %+v", second)
+	}
+}
+
+func TestTimeSeries_ContinuesResumedRun(t *testing.T) {
+	series := newTimeSeries(3)
+	earlier := []domain.TimeBucket{{Offset: 0, Duration: time.Second, Requests: 7}}
+	start := time.Now()
+	series.begin(start, 10*time.Second, earlier)
+
+	series.recordFailure(domain.ErrorClassCancelled)
+	series.flush(start.Add(time.Second), 0)
+
+	buckets := series.snapshot()
+	if len(buckets) != 2 || buckets[0].Requests != 7 {
+		t.Fatalf("Expected the earlier bucket to be kept, got %+v", buckets)
+	}
+	if buckets[1].Offset != 10*time.Second {
+		t.Errorf("Expected the new bucket to start 10s into the run, got %v", buckets[1].Offset)
+	}
+}
+
+func TestRun_RecordsTimeSeries(t *testing.T) {
+	server, _ := linkedSite(t, 3)
+
+	config := testConfig(server.URL)
+	config.DryRun = true
+	config.FollowLinks = true
+	config.MetricsInterval = 50 * time.Millisecond
+
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	if len(results.TimeSeries) < 2 {
+		t.Fatalf("Expected a bucket per 50ms interval, got %d", len(results.TimeSeries))
+	}
+	var requests int64
+	var covered time.Duration
+	for _, bucket := range results.TimeSeries {
+		requests += bucket.Requests
+		covered += bucket.Duration
+	}
+	if requests != 4 {
+		t.Errorf("Expected the buckets to hold all 4 requests, got %d", requests)
+	}
+	if last := results.TimeSeries[len(results.TimeSeries)-1]; last.Offset+last.Duration != covered {
+		t.Errorf("Expected contiguous buckets covering %v, last ends at %v", covered, last.Offset+last.Duration)
+	}
+}
@@ -0,0 +1,189 @@
+package tester
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+	"github.com/1mb-dev/lobster/v2/internal/histogram"
+)
+
+// requestTimerKey is the context key of a request's requestTimer.
+type requestTimerKey struct{}
+
+// requestTimer collects the phase timestamps of a request from httptrace callbacks,
+// and the body bytes read. Callbacks can run on other goroutines (dual-stack dialing), hence the mutex.
+type requestTimer struct {
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	lastRead     time.Time
+	timing       domain.RequestTiming
+	bytes        int64
+	mu           sync.Mutex
+}
+
+// withRequestTimer returns ctx with a requestTimer attached and fed by an httptrace.ClientTrace.
+func withRequestTimer(ctx context.Context) context.Context {
+	timer := &requestTimer{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { timer.mark(&timer.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { timer.add(&timer.timing.DNS, &timer.dnsStart) },
+		ConnectStart: func(string, string) {
+			timer.mu.Lock()
+			// Parallel dials (dual-stack) are timed from the first one
+			if timer.connectStart.IsZero() {
+				timer.connectStart = time.Now()
+			}
+			timer.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				timer.add(&timer.timing.Connect, &timer.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { timer.mark(&timer.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { timer.add(&timer.timing.TLS, &timer.tlsStart) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			timer.mu.Lock()
+			timer.timing.ConnReused = info.Reused
+			timer.connectStart = time.Time{}
+			timer.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { timer.mark(&timer.wroteRequest) },
+		GotFirstResponseByte: func() { timer.mark(&timer.firstByte) },
+	}
+	ctx = context.WithValue(ctx, requestTimerKey{}, timer)
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// mark sets a phase start to now.
+func (rt *requestTimer) mark(at *time.Time) {
+	rt.mu.Lock()
+	*at = time.Now()
+	rt.mu.Unlock()
+}
+
+// add adds the time since start to a phase duration; redirect hops accumulate.
+func (rt *requestTimer) add(phase *time.Duration, start *time.Time) {
+	rt.mu.Lock()
+	if !start.IsZero() {
+		*phase += time.Since(*start)
+	}
+	rt.mu.Unlock()
+}
+
+// read records n body bytes read just now.
+func (rt *requestTimer) read(n int) {
+	rt.mu.Lock()
+	rt.lastRead = time.Now()
+	rt.bytes += int64(n)
+	rt.mu.Unlock()
+}
+
+// bytesRead returns the body bytes read so far.
+func (rt *requestTimer) bytesRead() int64 {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.bytes
+}
+
+// result returns the phases recorded so far. Transfer and TTLB run to the last body read.
+func (rt *requestTimer) result() domain.RequestTiming {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	timing := rt.timing
+	if !rt.wroteRequest.IsZero() && rt.firstByte.After(rt.wroteRequest) {
+		timing.TTFB = rt.firstByte.Sub(rt.wroteRequest)
+	}
+	if !rt.firstByte.IsZero() && rt.lastRead.After(rt.firstByte) {
+		timing.Transfer = rt.lastRead.Sub(rt.firstByte)
+	}
+	if rt.lastRead.After(rt.start) {
+		timing.TTLB = rt.lastRead.Sub(rt.start)
+	} else if rt.firstByte.After(rt.start) {
+		timing.TTLB = rt.firstByte.Sub(rt.start)
+	}
+	return timing
+}
+
+// timedBody records when a response body was last read, for the transfer phase, and how much was read.
+type timedBody struct {
+	io.ReadCloser
+	timer *requestTimer
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 || err == io.EOF {
+		b.timer.read(n)
+	}
+	return n, err
+}
+
+// timeBody wraps resp's body so reading it is timed by the request's timer.
+func timeBody(resp *http.Response) {
+	if timer := requestTimerFrom(resp); timer != nil {
+		resp.Body = &timedBody{ReadCloser: resp.Body, timer: timer}
+	}
+}
+
+// requestTimerFrom returns the timer of the request that produced resp, if any.
+func requestTimerFrom(resp *http.Response) *requestTimer {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	timer, _ := resp.Request.Context().Value(requestTimerKey{}).(*requestTimer)
+	return timer
+}
+
+// requestTimingFrom returns the phase breakdown of the request that produced resp,
+// or nil if it was not traced. Call it once the body has been read.
+func requestTimingFrom(resp *http.Response) *domain.RequestTiming {
+	timer := requestTimerFrom(resp)
+	if timer == nil {
+		return nil
+	}
+	timing := timer.result()
+	return &timing
+}
+
+// timingBreakdown summarizes the phase timings in histograms, or returns nil if no
+// request was traced.
+func timingBreakdown(histograms *domain.LatencyHistograms) *domain.TimingBreakdown {
+	if histograms.TTFB.Count() == 0 {
+		return nil
+	}
+	return &domain.TimingBreakdown{
+		DNS:               phaseStats(histograms.DNS),
+		Connect:           phaseStats(histograms.Connect),
+		TLS:               phaseStats(histograms.TLS),
+		TTFB:              phaseStats(histograms.TTFB),
+		Transfer:          phaseStats(histograms.Transfer),
+		TTLB:              phaseStats(histograms.TTLB),
+		ReusedConnections: histograms.ReusedConnections,
+		NewConnections:    histograms.NewConnections,
+	}
+}
+
+// phaseStats returns the statistics of a phase's histogram.
+func phaseStats(h *histogram.Histogram) domain.PhaseStats {
+	stats := domain.SummarizeLatency(h)
+	return domain.PhaseStats{
+		Count: int(stats.Count),
+		Mean:  stats.Mean,
+		P50:   stats.P50,
+		P95:   stats.P95,
+		P99:   stats.P99,
+		Max:   stats.Max,
+	}
+}
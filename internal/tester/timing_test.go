@@ -0,0 +1,251 @@
+package tester
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestMakeHTTPRequest_RecordsPhaseTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("first part"))
+		w.(http.Flusher).Flush()
+		time.Sleep(30 * time.Millisecond)
+		_, _ = w.Write([]byte("second part"))
+	}))
+	defer server.Close()
+
+	tester, err := New(testConfig(server.URL), testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	fetch := func() *domain.RequestTiming {
+		resp, _, err := tester.makeHTTPRequest(context.Background(), domain.URLTask{URL: server.URL})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if _, err := io.ReadAll(resp.Body); err != nil {
+			t.Fatalf("Failed to read body: %v", err)
+		}
+		return requestTimingFrom(resp)
+	}
+
+	first := fetch()
+	if first == nil {
+		t.Fatal("Expected the request to be timed")
+	}
+	if first.ConnReused {
+		t.Error("Expected the first request to open a new connection")
+	}
+	if first.Connect <= 0 {
+		t.Errorf("Expected a connect time, got %v", first.Connect)
+	}
+	if first.DNS != 0 || first.TLS != 0 {
+		t.Errorf("Expected no DNS or TLS phase for a plain-HTTP IP address, got DNS %v, TLS %v", first.DNS, first.TLS)
+	}
+	if first.TTFB < 20*time.Millisecond {
+		t.Errorf("Expected TTFB of at least 20ms, got %v", first.TTFB)
+	}
+	if first.Transfer < 30*time.Millisecond {
+		t.Errorf("Expected transfer of at least 30ms, got %v", first.Transfer)
+	}
+	if first.TTLB < first.TTFB+first.Transfer {
+		t.Errorf("Expected TTLB to cover TTFB and transfer, got %v", first.TTLB)
+	}
+
+	second := fetch()
+	if !second.ConnReused {
+		t.Error("Expected the second request to reuse the connection")
+	}
+	if second.Connect != 0 {
+		t.Errorf("Expected no connect phase on a reused connection, got %v", second.Connect)
+	}
+}
+
+func TestMakeHTTPRequest_RecordsTLSHandshake(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	config.InsecureSkipVerify = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	resp, _, err := tester.makeHTTPRequest(context.Background(), domain.URLTask{URL: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if timing := requestTimingFrom(resp); timing == nil || timing.TLS <= 0 {
+		t.Errorf("Expected a TLS handshake time, got %+v", timing)
+	}
+}
+
+func TestTimingBreakdown(t *testing.T) {
+	untraced := domain.NewLatencyHistograms(3)
+	untraced.Record(domain.ResponseTimeEntry{ResponseTime: time.Second})
+	if got := timingBreakdown(untraced); got != nil {
+		t.Errorf("Expected no breakdown without traced requests, got %+v", got)
+	}
+
+	histograms := domain.NewLatencyHistograms(3)
+	for i := 1; i <= 100; i++ {
+		timing := &domain.RequestTiming{
+			TTFB:       time.Duration(i) * time.Millisecond,
+			Transfer:   time.Millisecond,
+			TTLB:       time.Duration(i+1) * time.Millisecond,
+			ConnReused: i > 10,
+		}
+		if i <= 10 {
+			timing.Connect = 2 * time.Millisecond
+		}
+		histograms.Record(domain.ResponseTimeEntry{Timing: timing})
+	}
+
+	breakdown := timingBreakdown(histograms)
+	if breakdown.ReusedConnections != 90 || breakdown.NewConnections != 10 {
+		t.Errorf("Expected 90 reused and 10 new connections, got %d and %d", breakdown.ReusedConnections, breakdown.NewConnections)
+	}
+	if breakdown.Connect.Count != 10 || breakdown.Connect.P99 != 2*time.Millisecond {
+		t.Errorf("Expected connect stats over the 10 new connections, got %+v", breakdown.Connect)
+	}
+	if breakdown.DNS.Count != 0 {
+		t.Errorf("Expected no DNS samples, got %+v", breakdown.DNS)
+	}
+
+	// Count, mean and max are exact; percentiles are within the histogram's precision
+	ttfb := breakdown.TTFB
+	if ttfb.Count != 100 || ttfb.Mean != 50500*time.Microsecond || ttfb.Max != 100*time.Millisecond {
+		t.Errorf("Expected count 100, mean 50.5ms and max 100ms, got %+v", ttfb)
+	}
+	for _, tt := range []struct {
+		got, want time.Duration
+	}{{ttfb.P50, 51 * time.Millisecond}, {ttfb.P95, 96 * time.Millisecond}, {ttfb.P99, 100 * time.Millisecond}} {
+		if diff := tt.got - tt.want; diff < -tt.want/1000 || diff > tt.want/1000 {
+			t.Errorf("Expected %v within 0.1%%, got %v", tt.want, tt.got)
+		}
+	}
+	if breakdown.TTLB.Count != 100 || breakdown.TTLB.Max != 101*time.Millisecond {
+		t.Errorf("Expected TTLB stats over all 100 requests, got %+v", breakdown.TTLB)
+	}
+}
+
+func TestRun_RecordsTimingBreakdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body><a href="/a">A</a><a href="/b">B</a></body></html>`))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL + "/")
+	config.FollowLinks = true
+	config.Concurrency = 1
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	results, err := tester.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Run, got: %v", err)
+	}
+
+	if results.Timing == nil {
+		t.Fatal("Expected a timing breakdown")
+	}
+	if results.Timing.TTFB.Count != 3 {
+		t.Errorf("Expected 3 timed requests, got %d", results.Timing.TTFB.Count)
+	}
+	if results.Timing.NewConnections < 1 || results.Timing.ReusedConnections+results.Timing.NewConnections != 3 {
+		t.Errorf("Expected 3 requests split into new and reused connections, got %d new and %d reused",
+			results.Timing.NewConnections, results.Timing.ReusedConnections)
+	}
+	for _, validation := range results.URLValidations {
+		if validation.Timing == nil {
+			t.Errorf("Expected %s to carry its phase timing", validation.URL)
+		}
+	}
+}
+
+func TestProcessURL_DrainsBodyForConnectionReuse(t *testing.T) {
+	// Larger than the link extraction limit, so link discovery leaves most of it unread
+	page := "<html><body>" + strings.Repeat("x", 3*linkExtractionLimit) + "</body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	config.FollowLinks = true
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	for range 2 {
+		tester.processURL(context.Background(), domain.URLTask{URL: server.URL})
+	}
+	close(tester.validationsCh)
+	var validations []domain.URLValidation
+	for v := range tester.validationsCh {
+		validations = append(validations, v)
+	}
+	if len(validations) != 2 {
+		t.Fatalf("Expected 2 validations, got %d", len(validations))
+	}
+
+	for _, validation := range validations {
+		if validation.BytesReceived != int64(len(page)) {
+			t.Errorf("Expected %d bytes received, got %d", len(page), validation.BytesReceived)
+		}
+	}
+	if !validations[1].Timing.ConnReused {
+		t.Error("Expected the drained connection to be reused")
+	}
+	if got := tester.results.BytesReceived; got != 2*int64(len(page)) {
+		t.Errorf("Expected %d bytes received in total, got %d", 2*len(page), got)
+	}
+}
+
+func TestProcessURL_CapsBodyAtMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 5000)))
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	config.MaxResponseSize = 1000
+	tester, err := New(config, testLogger())
+	if err != nil {
+		t.Fatalf("Failed to create tester: %v", err)
+	}
+
+	validations, errs := processTask(t, tester, server.URL)
+	if len(validations) != 1 || len(errs) != 0 {
+		t.Fatalf("Expected 1 validation and no errors, got %d and %v", len(validations), errs)
+	}
+	validation := validations[0]
+	if validation.BytesReceived != 1000 {
+		t.Errorf("Expected the body to be read up to the 1000 byte cap, got %d", validation.BytesReceived)
+	}
+}
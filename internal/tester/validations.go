@@ -0,0 +1,41 @@
+package tester
+
+import (
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+// recordValidation counts the result of a URL, records the redirect issues,
+// broken link and page status it shows, and keeps it in the report while fewer
+// than the URL validations limit are kept. Called from the aggregator only.
+func (t *Tester) recordValidation(validation domain.URLValidation) {
+	if t.results.StatusCounts == nil {
+		t.results.StatusCounts = make(map[int]int64)
+	}
+	t.results.StatusCounts[validation.StatusCode]++
+
+	t.results.RedirectIssues = append(t.results.RedirectIssues, redirectIssues(validation, t.config.Redirects.LongChainHops)...)
+	if t.config.LinkCheck {
+		if link, ok := brokenLink(validation, hostOf(t.config.BaseURL)); ok {
+			t.results.BrokenLinks = append(t.results.BrokenLinks, link)
+		}
+	}
+	if t.graph != nil {
+		t.graph.SetStatus(validation.URL, validation.Depth, validation.StatusCode)
+	}
+
+	if len(t.results.URLValidations) < t.urlValidationsLimit() {
+		t.results.URLValidations = append(t.results.URLValidations, validation)
+	}
+}
+
+// urlValidationsLimit returns how many per-URL results are kept.
+func (t *Tester) urlValidationsLimit() int {
+	switch {
+	case t.config.NoURLValidations:
+		return 0
+	case t.config.MaxURLValidations > 0:
+		return t.config.MaxURLValidations
+	default:
+		return domain.DefaultMaxURLValidations
+	}
+}
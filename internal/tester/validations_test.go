@@ -0,0 +1,48 @@
+package tester
+
+import (
+	"testing"
+
+	"github.com/1mb-dev/lobster/v2/internal/domain"
+)
+
+func TestRecordValidation_BoundsURLValidations(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(config *domain.TesterConfig)
+		want   int
+	}{
+		{"limit", func(config *domain.TesterConfig) { config.MaxURLValidations = 3 }, 3},
+		{"none kept", func(config *domain.TesterConfig) { config.NoURLValidations = true }, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig("http://example.com")
+			config.LinkCheck = true
+			tt.modify(&config)
+			tester, err := New(config, testLogger())
+			if err != nil {
+				t.Fatalf("Failed to create tester: %v", err)
+			}
+
+			for i := 0; i < 10; i++ {
+				status := 200
+				if i%2 == 1 {
+					status = 404
+				}
+				tester.recordValidation(domain.URLValidation{URL: "http://example.com/", StatusCode: status, IsValid: status == 200})
+			}
+
+			if got := len(tester.results.URLValidations); got != tt.want {
+				t.Errorf("Expected %d validations kept, got %d", tt.want, got)
+			}
+			if tester.results.StatusCounts[200] != 5 || tester.results.StatusCounts[404] != 5 {
+				t.Errorf("Expected every URL to be counted, got %v", tester.results.StatusCounts)
+			}
+			if len(tester.results.BrokenLinks) != 5 {
+				t.Errorf("Expected every failed URL to be a broken link, got %d", len(tester.results.BrokenLinks))
+			}
+		})
+	}
+}
@@ -3,9 +3,7 @@ package validator
 
 import (
 	"fmt"
-	"sort"
 	"strings"
-	"time"
 
 	"github.com/1mb-dev/lobster/v2/internal/domain"
 )
@@ -41,39 +39,11 @@ func NewWithComparison(targets domain.PerformanceTargets, comparisonTarget strin
 func (v *Validator) ValidateResults(results *domain.TestResults) {
 	v.targets = make([]domain.PerformanceTarget, 0)
 
-	// Parse response times for calculations
-	responseTimes := make([]time.Duration, len(results.ResponseTimes))
-	for i, entry := range results.ResponseTimes {
-		responseTimes[i] = entry.ResponseTime
-	}
-
-	var avgResponseTime, p95ResponseTime, p99ResponseTime time.Duration
-	if len(responseTimes) > 0 {
-		// Calculate average
-		var total time.Duration
-		for _, rt := range responseTimes {
-			total += rt
-		}
-		avgResponseTime = total / time.Duration(len(responseTimes))
-
-		// Calculate percentiles using proper sorting
-		sort.Slice(responseTimes, func(i, j int) bool {
-			return responseTimes[i] < responseTimes[j]
-		})
-
-		p95Index := int(float64(len(responseTimes)) * 0.95)
-		p99Index := int(float64(len(responseTimes)) * 0.99)
-
-		if p95Index >= len(responseTimes) {
-			p95Index = len(responseTimes) - 1
-		}
-		if p99Index >= len(responseTimes) {
-			p99Index = len(responseTimes) - 1
-		}
-
-		p95ResponseTime = responseTimes[p95Index]
-		p99ResponseTime = responseTimes[p99Index]
-	}
+	// Response time statistics are computed once, with the results
+	latency := results.LatencyStats()
+	avgResponseTime := latency.Mean
+	p95ResponseTime := latency.P95
+	p99ResponseTime := latency.P99
 
 	// Throughput validation
 	v.targets = append(v.targets, domain.PerformanceTarget{
@@ -133,6 +103,25 @@ func (v *Validator) ValidateResults(results *domain.TestResults) {
 		Description: fmt.Sprintf("Target: <%.1f%% for production reliability", v.targetConfig.ErrorRate),
 		Passed:      errorRate < v.targetConfig.ErrorRate,
 	})
+
+	// Error class caps, in taxonomy order
+	for _, class := range domain.ErrorClasses {
+		limit, ok := v.targetConfig.ErrorClasses[class]
+		if !ok {
+			continue
+		}
+		var classRate float64
+		if results.TotalRequests > 0 {
+			classRate = float64(results.ErrorClasses[class]) / float64(results.TotalRequests) * 100
+		}
+		v.targets = append(v.targets, domain.PerformanceTarget{
+			Name:        fmt.Sprintf("Errors: %s", class),
+			Target:      fmt.Sprintf("≤ %.1f%%", limit),
+			Actual:      fmt.Sprintf("%.2f%% (%d)", classRate, results.ErrorClasses[class]),
+			Description: fmt.Sprintf("Target: at most %.1f%% of requests failing with %s", limit, class),
+			Passed:      classRate <= limit,
+		})
+	}
 }
 
 // PrintValidationReport prints a detailed performance validation report
@@ -213,6 +213,37 @@ func TestValidateResults_PercentilesCalculation(t *testing.T) {
 	}
 }
 
+func TestValidateResults_UsesHistograms(t *testing.T) {
+	targets := domain.DefaultPerformanceTargets()
+	targets.P95ResponseTimeMs = 500
+	v := New(targets)
+
+	// Raw samples are a subset; the histogram covers every request
+	histograms := domain.NewLatencyHistograms(3)
+	for i := 1; i <= 1000; i++ {
+		histograms.Record(domain.ResponseTimeEntry{ResponseTime: time.Duration(i) * time.Millisecond})
+	}
+	results := &domain.TestResults{
+		TotalRequests:      1000,
+		SuccessfulRequests: 1000,
+		RequestsPerSecond:  100,
+		ResponseTimes:      []domain.ResponseTimeEntry{{ResponseTime: 10 * time.Millisecond}},
+		Histograms:         histograms,
+	}
+
+	v.ValidateResults(results)
+
+	for _, target := range v.targets {
+		if strings.Contains(target.Name, "95th") {
+			if target.Passed || !(strings.HasPrefix(target.Actual, "950.") || strings.HasPrefix(target.Actual, "951.")) {
+				t.Errorf("Expected P95 of about 951ms from the histogram to fail, got %s (passed=%v)", target.Actual, target.Passed)
+			}
+			return
+		}
+	}
+	t.Fatal("Expected P95 target to exist")
+}
+
 func TestValidateResults_SuccessRate(t *testing.T) {
 	targets := domain.PerformanceTargets{
 		RequestsPerSecond: 1,
@@ -359,6 +390,42 @@ func TestValidateResults_TargetCount(t *testing.T) {
 	}
 }
 
+func TestValidateResults_ErrorClasses(t *testing.T) {
+	targets := domain.DefaultPerformanceTargets()
+	targets.ErrorClasses = map[domain.ErrorClass]float64{
+		domain.ErrorClassHTTP5xx:     1.0,
+		domain.ErrorClassTimeoutTTFB: 0,
+		domain.ErrorClassDNS:         0,
+	}
+	v := New(targets)
+
+	results := &domain.TestResults{
+		TotalRequests: 200,
+		ErrorClasses:  map[domain.ErrorClass]int64{domain.ErrorClassHTTP5xx: 2, domain.ErrorClassTimeoutTTFB: 1},
+	}
+	v.ValidateResults(results)
+
+	classTargets := v.targets[6:]
+	if len(classTargets) != 3 {
+		t.Fatalf("Expected a target per capped class, got %d", len(classTargets))
+	}
+	// Targets follow the taxonomy order, not the map's
+	want := []struct {
+		name   string
+		passed bool
+	}{
+		{"Errors: dns", true},
+		{"Errors: timeout_ttfb", false},
+		{"Errors: http_5xx", true},
+	}
+	for i, w := range want {
+		if classTargets[i].Name != w.name || classTargets[i].Passed != w.passed {
+			t.Errorf("Expected target %q passed=%v, got %q passed=%v (%s)",
+				w.name, w.passed, classTargets[i].Name, classTargets[i].Passed, classTargets[i].Actual)
+		}
+	}
+}
+
 func TestPrintValidationReport(t *testing.T) {
 	_ = t // Test verifies no panic occurs
 	targets := domain.PerformanceTargets{